@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// a11y reports whether output is for screen readers: no color, glyphs or
+// tables, and what they signal spelled out in words.
+var a11y bool
+
+// Dates spelled so that screen readers read them as dates, unlike the short
+// formats.
+const (
+	a11yDateFormat     = "Monday 2 January 2006"
+	a11yDateTimeFormat = "Monday 2 January 2006 15:04"
+)
+
+func init() {
+	RegisterWriter("linear", func(w io.Writer, opts WriterOptions) Writer { return NewLinearWriter(w) })
+}
+
+// a11yPriority is the priority in words, "priority 1" being the most urgent
+// like in the app.
+func a11yPriority(priority int) string {
+	if priority < 1 || priority > 4 {
+		return "no priority"
+	}
+	return fmt.Sprintf("priority %d", 5-priority)
+}
+
+// a11yDueDate is the due date in words, followed by how urgent it is at now
+// unless it is later than tomorrow.
+func a11yDueDate(dueDate time.Time, allDay bool, now time.Time) string {
+	format := a11yDateTimeFormat
+	if allDay {
+		format = a11yDateFormat
+	}
+	due := dueDate.Local().Format(format)
+	switch dueClass(dueDate, allDay, now) {
+	case "overdue":
+		return due + ", overdue"
+	case "today":
+		return due + ", due today"
+	case "tomorrow":
+		return due + ", due tomorrow"
+	}
+	return due
+}
+
+func a11yComments(count int) string {
+	if count == 1 {
+		return "1 comment"
+	}
+	return fmt.Sprintf("%d comments", count)
+}
+
+// headerLabel spells a header like "DueDate" as words, "Due date".
+func headerLabel(header string) string {
+	var b strings.Builder
+	previous := ' '
+	for _, r := range header {
+		if unicode.IsUpper(r) && unicode.IsLower(previous) {
+			b.WriteRune(' ')
+			b.WriteRune(unicode.ToLower(r))
+		} else {
+			b.WriteRune(r)
+		}
+		previous = r
+	}
+	return b.String()
+}
+
+// LinearWriter writes every record on a line of its own, each field after
+// its header, like "ID: 10; priority 1; Content: Write the report", which
+// screen readers read in order where they would read a table cell by cell.
+// Empty fields are left out, and so is a header the field starts with
+// already.
+type LinearWriter struct {
+	w      io.Writer
+	header []string
+}
+
+func NewLinearWriter(w io.Writer) *LinearWriter {
+	return &LinearWriter{w: w}
+}
+
+func (w *LinearWriter) WriteHeader(record []string) error {
+	w.header = record
+	return nil
+}
+
+func (w *LinearWriter) Write(record []string) error {
+	if w.header == nil && len(record) == 2 {
+		value := strings.TrimSpace(ansiRegex.ReplaceAllString(record[1], ""))
+		if value == "" {
+			return nil
+		}
+		_, err := fmt.Fprintf(w.w, "%s: %s\n", headerLabel(ansiRegex.ReplaceAllString(record[0], "")), value)
+		return err
+	}
+	fields := []string{}
+	for i, field := range record {
+		// Indentation and padding only mean something to the eye.
+		field = strings.TrimSpace(ansiRegex.ReplaceAllString(field, ""))
+		if field == "" {
+			continue
+		}
+		if i < len(w.header) && w.header[i] != "" {
+			label := headerLabel(w.header[i])
+			if !strings.HasPrefix(strings.ToLower(field), strings.ToLower(label)) {
+				field = label + ": " + field
+			}
+		}
+		fields = append(fields, field)
+	}
+	_, err := fmt.Fprintln(w.w, strings.Join(fields, "; "))
+	return err
+}
+
+func (w *LinearWriter) Flush() {}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestA11yFormat(t *testing.T) {
+	now := time.Date(2020, 3, 3, 14, 0, 0, 0, time.Local)
+
+	assert.Equal(t, "priority 1", a11yPriority(4), "they should be equal")
+	assert.Equal(t, "priority 4", a11yPriority(1), "they should be equal")
+	assert.Equal(t, "no priority", a11yPriority(0), "they should be equal")
+
+	assert.Equal(t, "Monday 2 March 2020, overdue", a11yDueDate(time.Date(2020, 3, 2, 0, 0, 0, 0, time.Local), true, now), "they should be equal")
+	assert.Equal(t, "Tuesday 3 March 2020 18:00, due today", a11yDueDate(time.Date(2020, 3, 3, 18, 0, 0, 0, time.Local), false, now), "they should be equal")
+	assert.Equal(t, "Wednesday 4 March 2020, due tomorrow", a11yDueDate(time.Date(2020, 3, 4, 0, 0, 0, 0, time.Local), true, now), "they should be equal")
+	assert.Equal(t, "Friday 6 March 2020", a11yDueDate(time.Date(2020, 3, 6, 0, 0, 0, 0, time.Local), true, now), "they should be equal")
+
+	assert.Equal(t, "1 comment", a11yComments(1), "they should be equal")
+	assert.Equal(t, "Due date", headerLabel("DueDate"), "they should be equal")
+	assert.Equal(t, "ID", headerLabel("ID"), "they should be equal")
+}
+
+func TestLinearWriter(t *testing.T) {
+	var b bytes.Buffer
+	w := NewLinearWriter(&b)
+	w.WriteHeader([]string{"ID", "Priority", "DueDate", "Labels", "Content"})
+	w.Write([]string{"\x1b[2m10\x1b[0m", "priority 1", "Tuesday 3 March 2020, overdue", "", "    Write the report"})
+	w.Flush()
+	assert.Equal(t, "ID: 10; priority 1; Due date: Tuesday 3 March 2020, overdue; Content: Write the report\n", b.String(), "they should be equal")
+
+	b.Reset()
+	w = NewLinearWriter(&b)
+	w.Write([]string{"LastSync", "never"})
+	w.Write([]string{"URL", ""})
+	w.Write([]string{"10", "p1", "Write the report"})
+	w.Flush()
+	assert.Equal(t, "Last sync: never\n10; p1; Write the report\n", b.String(), "they should be equal")
+}
@@ -1,14 +1,19 @@
 package main
 
 import (
-	"context"
+	"fmt"
+	"os"
 	"strconv"
 	"strings"
 
 	"github.com/sachaos/todoist/lib"
+	"github.com/spf13/viper"
 	"github.com/urfave/cli"
 )
 
+// priorityMapping converts between the priorities of the app, p1 being the
+// highest, and those of the API, where 4 is the highest. It is its own
+// inverse.
 var priorityMapping = map[int]int{
 	1: 4,
 	2: 3,
@@ -16,39 +21,143 @@ var priorityMapping = map[int]int{
 	4: 1,
 }
 
+// parsePriority returns the API priority of a priority as shown in the app,
+// like p1, with or without the p.
+func parsePriority(s string) (int, error) {
+	p, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(s), "p"))
+	if err != nil || priorityMapping[p] == 0 {
+		return 0, InvalidPriority(s)
+	}
+	return priorityMapping[p], nil
+}
+
 func Add(c *cli.Context) error {
 	client := GetClient(c)
 
 	item := todoist.Item{}
-	if !c.Args().Present() {
-		return CommandFailed
+	if !c.Args().Present() && c.String("from-eml") == "" && c.String("url") == "" {
+		return ArgumentRequired
 	}
 
 	item.Content = c.Args().First()
-	item.Priority = priorityMapping[c.Int("priority")]
-	item.ProjectID = c.Int("project-id")
-	if item.ProjectID == 0 {
-		item.ProjectID = client.Store.Projects.GetIDByName(c.String("project-name"))
-	}
-	item.LabelIDs = func(str string) []int {
-		stringIDs := strings.Split(str, ",")
-		ids := []int{}
-		for _, stringID := range stringIDs {
-			id, err := strconv.Atoi(stringID)
+	comment := ""
+	if path := c.String("from-eml"); path != "" {
+		email, err := readEmailFile(path)
+		if err != nil {
+			return err
+		}
+		if item.Content == "" {
+			item.Content = email.Subject
+		}
+		item.Description = email.Description
+		comment = email.Body
+	}
+	link := ""
+	if c.String("url") != "" {
+		var err error
+		if link, err = parseWebURL(c.String("url")); err != nil {
+			return err
+		}
+		if item.Content == "" {
+			title, err := fetchPageTitle(GetContext(c), link)
 			if err != nil {
-				continue
+				fmt.Fprintln(os.Stderr, "could not fetch the title of the page:", err)
+			}
+			if title == "" {
+				title = link
+			}
+			item.Content = title
+		}
+		item.Description = strings.TrimSpace(item.Description + "\n" + link)
+	}
+	priority := c.String("priority")
+	if !flagIsSet(c, "priority", "p") && viper.IsSet("default_priority") {
+		priority = viper.GetString("default_priority")
+	}
+	var err error
+	if item.Priority, err = parsePriority(priority); err != nil {
+		return err
+	}
+	item.ProjectID = c.String("project-id")
+	projectName := c.String("project-name")
+	if item.ProjectID == "" && projectName == "" {
+		projectName = viper.GetString("default_project")
+	}
+	if item.ProjectID == "" && projectName != "" {
+		// With a workspace in use, the name is one of its projects.
+		workspaceID, scoped, err := workspaceScope(client.Store, c.String("workspace"))
+		if err != nil {
+			return err
+		}
+		if scoped {
+			item.ProjectID = projectIDInWorkspace(client.Store, projectName, workspaceID)
+		} else {
+			item.ProjectID = client.Store.Projects.GetIDByName(projectName)
+		}
+		if item.ProjectID == "" {
+			return ProjectNotFound(projectName)
+		}
+	}
+	item.LabelNames = labelNamesByIDs(client.Store, c.String("label-ids"))
+	if !flagIsSet(c, "label-ids", "L") {
+		for _, name := range viper.GetStringSlice("default_labels") {
+			name = strings.TrimPrefix(name, "@")
+			if client.Store.Labels.GetIDByName(name) == "" {
+				return LabelNotFound(name)
 			}
-			ids = append(ids, id)
+			item.LabelNames = append(item.LabelNames, name)
+		}
+	}
+	if label := urlLabel(); link != "" && label != "" {
+		tagged := false
+		for _, name := range item.LabelNames {
+			tagged = tagged || name == label
 		}
-		return ids
-	}(c.String("label-ids"))
+		if !tagged {
+			item.LabelNames = append(item.LabelNames, label)
+		}
+	}
 
 	item.DateString = c.String("date")
 	item.AutoReminder = c.Bool("reminder")
+	if !flagIsSet(c, "reminder", "r") {
+		item.AutoReminder = viper.GetBool("default_reminder")
+	}
 
-	if err := client.AddItem(context.Background(), item); err != nil {
-		return err
+	if comment == "" {
+		if err := client.AddItem(GetContext(c), item); err != nil {
+			return err
+		}
+		return Sync(c)
 	}
 
+	add := todoist.NewCommand("item_add", item.AddParam())
+	note := todoist.Note{ItemID: add.TempID, Content: comment}
+	if err := client.ExecCommands(GetContext(c), todoist.Commands{add, todoist.NewCommand("note_add", note.AddParam())}); err != nil {
+		return err
+	}
 	return Sync(c)
 }
+
+// labelNamesByIDs returns the names of the labels with the comma separated
+// ids in str, since tasks refer to labels by name.
+func labelNamesByIDs(store *todoist.Store, str string) []string {
+	names := []string{}
+	for _, id := range strings.Split(str, ",") {
+		if label := store.FindLabel(strings.TrimSpace(id)); label != nil {
+			names = append(names, label.Name)
+		}
+	}
+	return names
+}
+
+// flagIsSet reports whether any of the names of a flag was given on the
+// command line.
+func flagIsSet(c *cli.Context, names ...string) bool {
+	for _, name := range names {
+		if c.IsSet(name) {
+			return true
+		}
+	}
+	return false
+}
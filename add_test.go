@@ -0,0 +1,19 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePriority(t *testing.T) {
+	for s, expected := range map[string]int{"p1": 4, "P2": 3, "3": 2, "p4": 1} {
+		priority, err := parsePriority(s)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, priority, "they should be equal")
+	}
+	for _, s := range []string{"p0", "p5", "high", ""} {
+		_, err := parsePriority(s)
+		assert.Equal(t, "invalid_priority", AsError(err).Code, "they should be equal")
+	}
+}
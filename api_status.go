@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/urfave/cli"
+)
+
+func ApiStatus(c *cli.Context) error {
+	client := GetClient(c)
+	store := client.Store
+
+	rateLimit := "unknown"
+	rateLimitReset := ""
+	if store.RateLimit.Known {
+		rateLimit = fmt.Sprintf("%d/%d", store.RateLimit.Remaining, store.RateLimit.Limit)
+		if !store.RateLimit.Reset.IsZero() {
+			rateLimitReset = store.RateLimit.Reset.Local().Format(ShortDateTimeFormat)
+		}
+	}
+
+	lastSync := "never"
+	syncTokenAge := ""
+	if !store.LastSync.IsZero() {
+		lastSync = store.LastSync.Local().Format(ShortDateTimeFormat)
+		syncTokenAge = time.Since(store.LastSync).Round(time.Second).String()
+	}
+
+	cacheSize := ""
+	if fi, err := os.Stat(default_cache_path); err == nil {
+		cacheSize = strconv.FormatInt(fi.Size(), 10) + " bytes"
+	}
+
+	records := [][]string{
+		[]string{"RateLimit", rateLimit},
+		[]string{"RateLimitReset", rateLimitReset},
+		[]string{"LastSync", lastSync},
+		[]string{"SyncTokenAge", syncTokenAge},
+		[]string{"CacheSize", cacheSize},
+	}
+	defer writer.Flush()
+
+	for _, record := range records {
+		writer.Write(record)
+	}
+	return nil
+}
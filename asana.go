@@ -0,0 +1,286 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/spf13/viper"
+	"github.com/urfave/cli"
+)
+
+// defaultAsanaColumns are the headers of the CSV Asana exports projects as.
+// The asana_columns map of the config overrides them for other exports.
+var defaultAsanaColumns = map[string]string{
+	"name":           "Name",
+	"section":        "Section/Column",
+	"assignee":       "Assignee",
+	"assignee_email": "Assignee Email",
+	"due":            "Due Date",
+	"tags":           "Tags",
+	"notes":          "Notes",
+	"project":        "Projects",
+	"parent":         "Parent task",
+	"completed":      "Completed At",
+}
+
+func asanaColumns() map[string]string {
+	columns := map[string]string{}
+	for key, header := range defaultAsanaColumns {
+		columns[key] = header
+	}
+	for key, header := range viper.GetStringMapString("asana_columns") {
+		columns[key] = header
+	}
+	return columns
+}
+
+// AsanaTask is a row of an Asana export.
+type AsanaTask struct {
+	Name          string
+	Section       string
+	Assignee      string
+	AssigneeEmail string
+	Due           string
+	Tags          []string
+	Notes         string
+	Project       string
+	Parent        string
+	Completed     bool
+}
+
+// readAsanaCSV reads the tasks of an Asana export, whose headers are the
+// values of columns. Only the name is required.
+func readAsanaCSV(r io.Reader, columns map[string]string) ([]AsanaTask, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	index := map[string]int{}
+	for i, name := range header {
+		index[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	if _, ok := index[columns["name"]]; !ok {
+		return nil, &Error{Code: "invalid_argument", Message: fmt.Sprintf("no %q column", columns["name"]), Hint: "map the columns of the file in the \"asana_columns\" section of the config"}
+	}
+
+	tasks := []AsanaTask{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		field := func(key string) string {
+			if i, ok := index[columns[key]]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		task := AsanaTask{
+			Name:          field("name"),
+			Section:       field("section"),
+			Assignee:      field("assignee"),
+			AssigneeEmail: field("assignee_email"),
+			Due:           field("due"),
+			Notes:         field("notes"),
+			Project:       strings.TrimSpace(strings.Split(field("project"), ",")[0]),
+			Parent:        field("parent"),
+			Completed:     field("completed") != "",
+		}
+		for _, tag := range strings.Split(field("tags"), ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				task.Tags = append(task.Tags, strings.ReplaceAll(tag, " ", "_"))
+			}
+		}
+		if task.Name != "" {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+// asanaCommands adds tasks to the projects of their Project column, or to
+// project if given, created if there is none, in the sections of the same
+// name. Subtasks go under the task named by their Parent column, and tags
+// become labels. Tasks are only assigned in projects already shared with the
+// assignee, the names of the others are returned.
+func asanaCommands(store *todoist.Store, tasks []AsanaTask, project string) (todoist.Commands, []string) {
+	commands := todoist.Commands{}
+	unassigned := []string{}
+
+	projects := map[string]interface{}{}
+	projectID := func(name string) interface{} {
+		if id, ok := projects[name]; ok {
+			return id
+		}
+		if id := store.Projects.GetIDByName(name); id != "" {
+			projects[name] = id
+			return id
+		}
+		command := todoist.NewCommand("project_add", todoist.Project{Name: name}.AddParam())
+		commands = append(commands, command)
+		projects[name] = command.TempID
+		return command.TempID
+	}
+	sections := map[string]interface{}{}
+	sectionID := func(projectID interface{}, name string) interface{} {
+		key := fmt.Sprint(projectID) + "\x00" + name
+		if id, ok := sections[key]; ok {
+			return id
+		}
+		for _, section := range store.ProjectSections(fmt.Sprint(projectID)) {
+			if section.Name == name {
+				sections[key] = section.ID
+				return section.ID
+			}
+		}
+		command := todoist.NewCommand("section_add", map[string]interface{}{"name": name, "project_id": projectID})
+		commands = append(commands, command)
+		sections[key] = command.TempID
+		return command.TempID
+	}
+
+	// Parents are added before their subtasks, wherever they are in the
+	// file. Subtasks whose parent isn't found are added as tasks.
+	added := map[string]interface{}{}
+	pending := tasks
+	for len(pending) > 0 {
+		deferred := []AsanaTask{}
+		for _, task := range pending {
+			name := project
+			if name == "" {
+				name = task.Project
+			}
+			pid := projectID(name)
+
+			param := todoist.Item{BaseItem: todoist.BaseItem{Content: task.Name}, Description: task.Notes, LabelNames: task.Tags}.AddParam().(map[string]interface{})
+			param["project_id"] = pid
+			if task.Parent != "" {
+				parentID, ok := added[fmt.Sprint(pid)+"\x00"+task.Parent]
+				if !ok && parentInTasks(pending, task.Parent) {
+					deferred = append(deferred, task)
+					continue
+				}
+				if ok {
+					param["parent_id"] = parentID
+				}
+			}
+			if task.Section != "" && task.Section != "Untitled section" && param["parent_id"] == nil {
+				param["section_id"] = sectionID(pid, task.Section)
+			}
+			if _, err := time.Parse(todoist.RFC3339Date, task.Due); err == nil {
+				param["due"] = &todoist.Due{Date: task.Due}
+			}
+			if task.Assignee != "" || task.AssigneeEmail != "" {
+				uid := asanaAssignee(store, task)
+				if id, ok := pid.(string); ok && uid != "" && store.IsShared(id, uid) {
+					param["responsible_uid"] = uid
+				} else if task.Assignee != "" {
+					unassigned = append(unassigned, task.Assignee)
+				} else {
+					unassigned = append(unassigned, task.AssigneeEmail)
+				}
+			}
+
+			add := todoist.NewCommand("item_add", param)
+			commands = append(commands, add)
+			added[fmt.Sprint(pid)+"\x00"+task.Name] = add.TempID
+			if task.Completed {
+				commands = append(commands, todoist.NewCommand("item_close", map[string]interface{}{"id": add.TempID}))
+			}
+		}
+		if len(deferred) == len(pending) {
+			// The parents are nowhere to be added, e.g. in a cycle.
+			for i := range deferred {
+				deferred[i].Parent = ""
+			}
+		}
+		pending = deferred
+	}
+
+	sort.Strings(unassigned)
+	names := []string{}
+	for i, name := range unassigned {
+		if i == 0 || name != unassigned[i-1] {
+			names = append(names, name)
+		}
+	}
+	return commands, names
+}
+
+// parentInTasks reports whether a task of tasks is called name.
+func parentInTasks(tasks []AsanaTask, name string) bool {
+	for _, task := range tasks {
+		if task.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// asanaAssignee returns the user id of the assignee of task, by email or
+// else by name, or "".
+func asanaAssignee(store *todoist.Store, task AsanaTask) string {
+	for _, name := range []string{task.AssigneeEmail, task.Assignee} {
+		if name == "" {
+			continue
+		}
+		if uid, err := resolveAssignee(store, name); err == nil {
+			return uid
+		}
+	}
+	return ""
+}
+
+func ImportAsana(c *cli.Context) error {
+	client := GetClient(c)
+
+	if !c.Args().Present() {
+		return ArgumentRequired
+	}
+	path := c.Args().First()
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	tasks, err := readAsanaCSV(f, asanaColumns())
+	if err != nil {
+		return err
+	}
+	if len(tasks) == 0 {
+		fmt.Fprintln(os.Stderr, "There are no tasks to import.")
+		return nil
+	}
+
+	project := c.String("project")
+	if project == "" {
+		// Tasks without a project go to one named after the file.
+		for i := range tasks {
+			if tasks[i].Project == "" {
+				tasks[i].Project = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+			}
+		}
+	}
+
+	commands, unassigned := asanaCommands(client.Store, tasks, project)
+	if err := client.ExecCommands(GetContext(c), commands); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Imported %d task(s)\n", len(tasks))
+	if len(unassigned) > 0 {
+		fmt.Fprintf(os.Stderr, "Left the tasks of %s unassigned, share the project with them and use `todoist delegate`\n", strings.Join(unassigned, ", "))
+	}
+	return Sync(c)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadAsanaCSV(t *testing.T) {
+	columns := asanaColumns()
+	columns["name"] = "Task"
+	tasks, err := readAsanaCSV(strings.NewReader("\ufeffTask,Tags,Projects,Completed At\n"+
+		"Write copy,\"copy, needs review\",\"Work, Launch\",\n"+
+		",,Work,\n"+
+		"Kickoff,,,2020-01-03\n"), columns)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(tasks), "they should be equal")
+	assert.Equal(t, []string{"copy", "needs_review"}, tasks[0].Tags, "they should be equal")
+	assert.Equal(t, "Work", tasks[0].Project, "they should be equal")
+	assert.False(t, tasks[0].Completed)
+	assert.True(t, tasks[1].Completed)
+
+	_, err = readAsanaCSV(strings.NewReader("Name\nWrite copy\n"), columns)
+	assert.Error(t, err)
+}
+
+func TestAsanaCommands(t *testing.T) {
+	store := testStore(t, `{
+		"projects": [{"id": "2", "name": "Work"}],
+		"sections": [{"id": "31", "project_id": "2", "name": "Doing"}],
+		"collaborators": [{"id": "7", "full_name": "Alex Kim", "email": "alex@example.com"}],
+		"collaborator_states": [{"project_id": "2", "user_id": "7", "state": "active"}]
+	}`)
+	tasks := []AsanaTask{
+		{Name: "Proofread", Project: "Work", Parent: "Write copy"},
+		{Name: "Write copy", Project: "Work", Section: "Doing", AssigneeEmail: "alex@example.com", Due: "2020-01-02"},
+		{Name: "Kickoff", Project: "Launch", Section: "Planning", Assignee: "Alex Kim", Completed: true},
+		{Name: "Orphan", Project: "Work", Parent: "Gone", Section: "Untitled section"},
+	}
+
+	commands, unassigned := asanaCommands(store, tasks, "")
+	types := []string{}
+	for _, command := range commands {
+		types = append(types, command.Type)
+	}
+	assert.Equal(t, []string{"item_add", "project_add", "section_add", "item_add", "item_close", "item_add", "item_add"}, types, "they should be equal")
+
+	write := commands[0].Args.(map[string]interface{})
+	assert.Equal(t, "Write copy", write["content"], "they should be equal")
+	assert.Equal(t, "2", write["project_id"], "they should be equal")
+	assert.Equal(t, "31", write["section_id"], "they should be equal")
+	assert.Equal(t, "7", write["responsible_uid"], "they should be equal")
+	assert.Equal(t, "2020-01-02", write["due"].(*todoist.Due).Date, "they should be equal")
+
+	kickoff := commands[3].Args.(map[string]interface{})
+	assert.Equal(t, commands[1].TempID, kickoff["project_id"], "they should be equal")
+	assert.Equal(t, commands[2].TempID, kickoff["section_id"], "they should be equal")
+	assert.Nil(t, kickoff["responsible_uid"])
+	assert.Equal(t, []string{"Alex Kim"}, unassigned, "they should be equal")
+
+	orphan := commands[5].Args.(map[string]interface{})
+	assert.Equal(t, "Orphan", orphan["content"], "they should be equal")
+	assert.Nil(t, orphan["parent_id"])
+	assert.Nil(t, orphan["section_id"])
+
+	proofread := commands[6].Args.(map[string]interface{})
+	assert.Equal(t, commands[0].TempID, proofread["parent_id"], "they should be equal")
+
+	commands, _ = asanaCommands(store, tasks[2:3], "Imported")
+	assert.Equal(t, "Imported", commands[0].Args.(map[string]interface{})["name"], "they should be equal")
+}
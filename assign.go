@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sachaos/todoist/lib"
+)
+
+// assignees returns the collaborators together with the members of the
+// workspaces, who can be assigned tasks of workspace projects without
+// sharing them.
+func assignees(store *todoist.Store) []todoist.Collaborator {
+	all := append([]todoist.Collaborator{}, store.Collaborators...)
+	for _, user := range store.WorkspaceUsers {
+		known := user.IsDeleted
+		for _, collaborator := range all {
+			known = known || collaborator.ID == user.UserID
+		}
+		if !known {
+			all = append(all, todoist.Collaborator{ID: user.UserID, Email: user.Email, FullName: user.FullName})
+		}
+	}
+	return all
+}
+
+// resolveAssignee returns the user id of name, which is "me", the full name,
+// first name or email of a collaborator or workspace member, or "unassigned"
+// for "".
+func resolveAssignee(store *todoist.Store, name string) (string, error) {
+	switch strings.ToLower(name) {
+	case "me":
+		return store.User.ID, nil
+	case "unassigned", "nobody":
+		return "", nil
+	}
+
+	matches := []todoist.Collaborator{}
+	for _, collaborator := range assignees(store) {
+		if strings.EqualFold(collaborator.FullName, name) || strings.EqualFold(collaborator.Email, name) {
+			return collaborator.ID, nil
+		}
+		if first := strings.Fields(collaborator.FullName); len(first) > 0 && strings.EqualFold(first[0], name) {
+			matches = append(matches, collaborator)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", CollaboratorNotFound(name)
+	case 1:
+		return matches[0].ID, nil
+	}
+	names := make([]string, len(matches))
+	for i, collaborator := range matches {
+		names[i] = collaborator.FullName
+	}
+	return "", &Error{Code: "ambiguous_collaborator", Message: fmt.Sprintf("%q could be %s", name, strings.Join(names, " or ")), Hint: "use the full name or the email"}
+}
+
+// filterAssigned returns the items assigned to the user with id, or the
+// unassigned ones for "".
+func filterAssigned(items []listedItem, id string) []listedItem {
+	assigned := []listedItem{}
+	for _, listed := range items {
+		if listed.item.ResponsibleID() == id {
+			assigned = append(assigned, listed)
+		}
+	}
+	return assigned
+}
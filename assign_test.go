@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveAssignee(t *testing.T) {
+	store := testStore(t, `{
+		"user": {"id": "1"},
+		"collaborators": [{"id": "1", "full_name": "Me Myself"},
+		                  {"id": "2", "full_name": "Alex Kim", "email": "alex@example.com"},
+		                  {"id": "3", "full_name": "Alex Park"},
+		                  {"id": "4", "full_name": "Sam Lee"}]
+	}`)
+
+	for name, expected := range map[string]string{
+		"me":               "1",
+		"unassigned":       "",
+		"alex kim":         "2",
+		"alex@example.com": "2",
+		"Sam":              "4",
+	} {
+		id, err := resolveAssignee(store, name)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, id, "they should be equal")
+	}
+
+	_, err := resolveAssignee(store, "alex")
+	assert.Equal(t, "ambiguous_collaborator", AsError(err).Code, "they should be equal")
+	_, err = resolveAssignee(store, "bob")
+	assert.Equal(t, "collaborator_not_found", AsError(err).Code, "they should be equal")
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/urfave/cli"
+)
+
+// ProjectAttachment is a file attached to a comment of a task of a project.
+type ProjectAttachment struct {
+	Item       *todoist.Item
+	Attachment todoist.FileAttachment
+	// Name is the file name to save it as, unique among the attachments of
+	// the project.
+	Name string
+}
+
+// safeFileName makes s usable as a file name on every platform.
+func safeFileName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, s)
+	return strings.Trim(strings.TrimSpace(s), ".")
+}
+
+// projectAttachments returns the attachments of the tasks of the project
+// with projectID, open and completed ones in the cache, named after their
+// task, like "Reply to customer emails - screenshot.png".
+func projectAttachments(store *todoist.Store, projectID string) []ProjectAttachment {
+	attachments := []ProjectAttachment{}
+	used := map[string]bool{}
+	for i := range store.Items {
+		item := &store.Items[i]
+		if item.ProjectID != projectID {
+			continue
+		}
+		for _, attachment := range store.ItemAttachments(item.ID) {
+			base := safeFileName(todoist.GetContentTitle(item)) + " - " + safeFileName(attachment.FileName)
+			ext := filepath.Ext(base)
+			name := base
+			for n := 2; used[strings.ToLower(name)]; n++ {
+				name = fmt.Sprintf("%s (%d)%s", strings.TrimSuffix(base, ext), n, ext)
+			}
+			used[strings.ToLower(name)] = true
+			attachments = append(attachments, ProjectAttachment{Item: item, Attachment: attachment, Name: name})
+		}
+	}
+	return attachments
+}
+
+// PullAttachments downloads the attachments of a project into a directory.
+// Files already there are skipped, so that an interrupted pull can be run
+// again.
+func PullAttachments(c *cli.Context) error {
+	client := GetClient(c)
+
+	name := c.String("project")
+	if name == "" {
+		return &Error{Code: "argument_required", Message: "missing --project", Hint: "give the name of the project with --project"}
+	}
+	projectID := client.Store.Projects.GetIDByName(name)
+	if projectID == "" {
+		return ProjectNotFound(name)
+	}
+
+	dir := c.String("out")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	attachments := projectAttachments(client.Store, projectID)
+	if len(attachments) == 0 {
+		fmt.Fprintln(os.Stderr, "The project has no attachments.")
+		return nil
+	}
+
+	for _, attachment := range attachments {
+		path := filepath.Join(dir, attachment.Name)
+		if _, err := os.Stat(path); err == nil {
+			fmt.Fprintln(os.Stderr, "Skipped", path)
+			continue
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		if err := client.Download(GetContext(c), attachment.Attachment.FileURL, f); err != nil {
+			f.Close()
+			os.Remove(path)
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stderr, "Saved", path)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectAttachments(t *testing.T) {
+	store := testStore(t, `{
+		"items": [
+			{"id": "1", "project_id": "5", "content": "Fix: the [login](https://example.com) page"},
+			{"id": "2", "project_id": "5", "content": "Done", "checked": true},
+			{"id": "3", "project_id": "6", "content": "Other project"}
+		],
+		"notes": [
+			{"id": "10", "item_id": "1", "file_attachment": {"file_name": "screen.png", "file_url": "https://example.com/1.png"}},
+			{"id": "11", "item_id": "1", "file_attachment": {"file_name": "Screen.png", "file_url": "https://example.com/2.png"}},
+			{"id": "12", "item_id": "2", "file_attachment": {"file_name": "../notes.pdf", "file_url": "https://example.com/3.pdf"}},
+			{"id": "13", "item_id": "3", "file_attachment": {"file_name": "other.png", "file_url": "https://example.com/4.png"}}
+		]
+	}`)
+
+	names := []string{}
+	for _, attachment := range projectAttachments(store, "5") {
+		names = append(names, attachment.Name)
+	}
+	assert.Equal(t, []string{
+		"Fix_ the login page - screen.png",
+		"Fix_ the login page - Screen (2).png",
+		"Done - _notes.pdf",
+	}, names, "they should be equal")
+}
@@ -1,45 +1,200 @@
 package main
 
 import (
+	"crypto/sha256"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	"github.com/sachaos/todoist/lib"
 )
 
-func LoadCache(filename string, s *todoist.Store) error {
-	err := ReadCache(filename, s)
+var legacyCachePath = filepath.Join(configPath, ".todoist.cache.json")
+
+// partialStore is the store read from a database cache with only some of its
+// items, which must not be written back over the cache.
+var partialStore *todoist.Store
+
+// lockCache takes an advisory lock on the cache, shared for reading and
+// exclusive for writing, and returns a function releasing it. The lock is
+// held on a separate file because writes replace the cache file.
+func lockCache(filename string, exclusive bool) (func(), error) {
+	f, err := os.OpenFile(filename+".lock", os.O_RDWR|os.O_CREATE, 0600)
 	if err != nil {
-		err = WriteCache(default_cache_path, s)
-		if err != nil {
-			return CommandFailed
+		return nil, err
+	}
+	if err := lockFile(f, exclusive); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
+
+// accountCachePath returns the cache of the account of token, so that
+// switching accounts never shows the tasks of another one. The single cache
+// of older versions is taken over when it belongs to the same account.
+func accountCachePath(token string, backend string) (string, error) {
+	sum := sha256.Sum256([]byte(token))
+	if backend == "sqlite" {
+		return filepath.Join(configPath, fmt.Sprintf(".todoist.cache.%x.db", sum[:4])), nil
+	}
+	path := filepath.Join(configPath, fmt.Sprintf(".todoist.cache.%x.json", sum[:4]))
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		return path, nil
+	}
+	// Only the token is decoded, the rest may be in an outdated format.
+	var legacy struct {
+		User struct {
+			Token string `json:"token"`
+		} `json:"user"`
+	}
+	if err := readJSONFile(legacyCachePath, &legacy); err != nil || legacy.User.Token != token {
+		return path, nil
+	}
+	return path, os.Rename(legacyCachePath, path)
+}
+
+// LoadCache reads the cache into s, starting a new one if there is none. It
+// reports whether the cache was written by a version using the old API, in
+// which case s is empty and has to be synced again. A cache which can't be
+// read otherwise is left as it is.
+func LoadCache(filename string, s *todoist.Store) (bool, error) {
+	outdated := false
+	if _, err := os.Stat(filename); err == nil {
+		err := ReadCache(filename, s)
+		if err == nil && s.CacheVersion >= todoist.CacheVersion {
+			return false, nil
 		}
+		if err != nil && !outdatedCache(filename) {
+			return false, err
+		}
+		outdated = true
+	} else if !os.IsNotExist(err) {
+		return false, CacheError(err)
 	}
-	return nil
+	*s = todoist.Store{CacheVersion: todoist.CacheVersion}
+	if err := WriteCache(filename, s); err != nil {
+		return false, err
+	}
+	return outdated, nil
+}
+
+// outdatedCache reports whether filename is a JSON cache of an older version,
+// whose store may not be read as the fields of the old API differ.
+func outdatedCache(filename string) bool {
+	if isDatabaseCache(filename) {
+		return false
+	}
+	var cache struct {
+		CacheVersion int `json:"cache_version"`
+	}
+	if err := readJSONFile(filename, &cache); err != nil {
+		return false
+	}
+	return cache.CacheVersion < todoist.CacheVersion
 }
 
 func ReadCache(filename string, s *todoist.Store) error {
+	if isDatabaseCache(filename) {
+		if err := readDatabaseCache(filename, s, true); err != nil {
+			return databaseCacheError(err)
+		}
+		s.ConstructItemTree()
+		return nil
+	}
+	unlock, err := lockCache(filename, false)
+	if err != nil {
+		return CacheError(err)
+	}
 	jsonString, err := ioutil.ReadFile(filename)
+	unlock()
 	if err != nil {
-		return CommandFailed
+		return CacheError(err)
 	}
 	err = json.Unmarshal(jsonString, &s)
 	if err != nil {
-		return CommandFailed
+		return CacheError(err)
+	}
+	s.ConstructItemTree()
+	return nil
+}
+
+// ReadCacheWithoutItems reads the database cache at filename into s but for
+// its items, which ReadCacheItems reads when the filter is known.
+func ReadCacheWithoutItems(filename string, s *todoist.Store) error {
+	if err := readDatabaseCache(filename, s, false); err != nil {
+		return databaseCacheError(err)
+	}
+	s.ConstructItemTree()
+	partialStore = s
+	return nil
+}
+
+// ReadCacheItems reads the items of s left out by ReadCacheWithoutItems:
+// those which may match ex, with their ancestors, by the indexes of the
+// database, or all of them if ex is nil.
+func ReadCacheItems(filename string, s *todoist.Store, ex Expression) error {
+	if s != partialStore {
+		return nil
+	}
+	if ex == nil {
+		var full todoist.Store
+		if err := ReadCache(filename, &full); err != nil {
+			return err
+		}
+		s.Items = full.Items
+		partialStore = nil
+	} else {
+		items, err := readDatabaseItems(filename, newItemQuery(ex, s))
+		if err != nil {
+			return databaseCacheError(err)
+		}
+		s.Items = items
 	}
 	s.ConstructItemTree()
 	return nil
 }
 
 func WriteCache(filename string, s *todoist.Store) error {
+	if s == partialStore {
+		return CacheError(errors.New("only some of the tasks of the cache were read"))
+	}
+	if isDatabaseCache(filename) {
+		if err := writeDatabaseCache(filename, s); err != nil {
+			return databaseCacheError(err)
+		}
+		if err := updateSearchIndex(filename, s); err != nil {
+			return CacheError(err)
+		}
+		return nil
+	}
 	buf, err := json.MarshalIndent(s, "", "  ")
 	if err != nil {
-		return CommandFailed
+		return CacheError(err)
 	}
-	err2 := ioutil.WriteFile(filename, buf, os.ModePerm)
-	if err2 != nil {
-		return CommandFailed
+	unlock, err := lockCache(filename, true)
+	if err != nil {
+		return CacheError(err)
+	}
+	defer unlock()
+	if err := writeFileAtomic(filename, buf); err != nil {
+		return CacheError(err)
+	}
+	if err := updateSearchIndex(filename, s); err != nil {
+		return CacheError(err)
 	}
 	return nil
 }
+
+func databaseCacheError(err error) error {
+	if e, ok := err.(*Error); ok {
+		return e
+	}
+	return CacheError(err)
+}
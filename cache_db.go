@@ -0,0 +1,262 @@
+package main
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sachaos/todoist/lib"
+)
+
+// cacheTables are the resources a database cache keeps one row per object
+// of, so that writing the cache only touches the objects which changed.
+var cacheTables = []string{"items", "projects", "labels", "filters", "notes", "sections"}
+
+type cacheRow struct {
+	ID        string
+	ProjectID string
+	Position  int
+	Data      string
+	// The fields of items which queries for filters look at.
+	ParentID string
+	Checked  bool
+	Priority int
+	Due      string
+	Labels   []string
+}
+
+// isDatabaseCache reports whether the cache at filename is a database
+// instead of a JSON file.
+func isDatabaseCache(filename string) bool {
+	return strings.HasSuffix(filename, ".db")
+}
+
+// splitStore splits s into the rows of cacheTables and the JSON of its other
+// fields.
+func splitStore(s *todoist.Store) (map[string][]cacheRow, map[string]string, error) {
+	buf, err := json.Marshal(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(buf, &fields); err != nil {
+		return nil, nil, err
+	}
+
+	tables := map[string][]cacheRow{}
+	for _, table := range cacheTables {
+		var objects []json.RawMessage
+		if err := json.Unmarshal(fields[table], &objects); err != nil {
+			return nil, nil, err
+		}
+		rows := make([]cacheRow, len(objects))
+		for i, object := range objects {
+			row, err := newCacheRow(table, object)
+			if err != nil {
+				return nil, nil, err
+			}
+			row.Position = i
+			rows[i] = row
+		}
+		tables[table] = rows
+		delete(fields, table)
+	}
+
+	meta := map[string]string{}
+	for key, value := range fields {
+		meta[key] = string(value)
+	}
+	return tables, meta, nil
+}
+
+// newCacheRow returns the row of object of table.
+func newCacheRow(table string, object json.RawMessage) (cacheRow, error) {
+	if table != "items" {
+		var keys struct {
+			ID        string `json:"id"`
+			ProjectID string `json:"project_id"`
+		}
+		err := json.Unmarshal(object, &keys)
+		return cacheRow{ID: keys.ID, ProjectID: keys.ProjectID, Data: string(object)}, err
+	}
+	var item todoist.Item
+	if err := json.Unmarshal(object, &item); err != nil {
+		return cacheRow{}, err
+	}
+	row := cacheRow{ID: item.ID, ProjectID: item.ProjectID, Data: string(object), Checked: item.Checked, Priority: item.Priority, Labels: item.LabelNames}
+	if item.ParentID != nil {
+		row.ParentID = *item.ParentID
+	}
+	if item.Due != nil {
+		row.Due = item.DateTime().Format(todoist.RFC3339Date)
+	}
+	return row, nil
+}
+
+// itemQuery selects the open items of a database cache which may match a
+// filter, narrowed by what every task matching it has. It may select more,
+// the filter is still evaluated on the items.
+type itemQuery struct {
+	// projectIDs are the projects the items may be in, nil for any.
+	projectIDs []string
+	// labels are the labels the items all have.
+	labels []string
+	// priorities are those the items may have, nil for any.
+	priorities []int
+	noDue      bool
+	// dueFrom and dueUntil bound the due dates, "" for no bound.
+	dueFrom, dueUntil string
+}
+
+// newItemQuery returns the query of the items which may match ex, which
+// needs the projects of store. What the query can't tell about, like
+// searches or alternatives, narrows nothing.
+func newItemQuery(ex Expression, store *todoist.Store) itemQuery {
+	q := itemQuery{}
+	q.narrow(ex, store)
+	return q
+}
+
+func (q *itemQuery) narrow(ex Expression, store *todoist.Store) {
+	// Due dates are compared by the day, in the time zone they were written
+	// in, so the bounds leave a day to spare.
+	day := func(t time.Time, days int) string {
+		return t.Local().AddDate(0, 0, days).Format(todoist.RFC3339Date)
+	}
+	switch e := ex.(type) {
+	case BoolInfixOpExpr:
+		if e.operator == '&' {
+			q.narrow(e.left, store)
+			q.narrow(e.right, store)
+		}
+	case ProjectExpr:
+		ids := append([]string{}, store.Projects.GetIDsByName(e.name, e.isAll)...)
+		if q.projectIDs != nil {
+			ids = intersectIDs(q.projectIDs, ids)
+		}
+		q.projectIDs = ids
+	case LabelExpr:
+		if e.name != "" {
+			q.labels = append(q.labels, e.name)
+		}
+	case StringExpr:
+		matched := priorityRegex.FindStringSubmatch(e.literal)
+		if len(matched) == 0 {
+			return
+		}
+		priorities := []int{}
+		for priority, p := range priorityMapping {
+			if strconv.Itoa(p) == matched[1] && (q.priorities == nil || containsInt(q.priorities, priority)) {
+				priorities = append(priorities, priority)
+			}
+		}
+		q.priorities = priorities
+	case DateExpr:
+		switch e.operation {
+		case NO_DUE_DATE:
+			q.noDue = true
+		case DUE_ON:
+			q.bound(day(e.datetime, -1), day(e.datetime, 1))
+		case DUE_BEFORE:
+			q.bound("", day(e.datetime, 1))
+		case DUE_AFTER:
+			q.bound(day(e.datetime, -1), "")
+		}
+	}
+}
+
+// bound narrows the due dates of q to from until until, "" for no bound.
+func (q *itemQuery) bound(from string, until string) {
+	if from != "" && from > q.dueFrom {
+		q.dueFrom = from
+	}
+	if until != "" && (q.dueUntil == "" || until < q.dueUntil) {
+		q.dueUntil = until
+	}
+}
+
+// where returns the condition of q on the items table, with its arguments.
+func (q itemQuery) where() (string, []interface{}) {
+	conditions := []string{"checked = 0"}
+	args := []interface{}{}
+	in := func(column string, values []interface{}) {
+		if len(values) == 0 {
+			conditions = append(conditions, "0")
+			return
+		}
+		conditions = append(conditions, column+" IN (?"+strings.Repeat(", ?", len(values)-1)+")")
+		args = append(args, values...)
+	}
+	if q.projectIDs != nil {
+		values := []interface{}{}
+		for _, id := range q.projectIDs {
+			values = append(values, id)
+		}
+		in("project_id", values)
+	}
+	for _, label := range q.labels {
+		conditions = append(conditions, "id IN (SELECT item_id FROM item_labels WHERE name = ?)")
+		args = append(args, label)
+	}
+	if q.priorities != nil {
+		values := []interface{}{}
+		for _, priority := range q.priorities {
+			values = append(values, priority)
+		}
+		in("priority", values)
+	}
+	if q.noDue {
+		conditions = append(conditions, "due IS NULL")
+	}
+	if q.dueFrom != "" {
+		conditions = append(conditions, "due >= ?")
+		args = append(args, q.dueFrom)
+	}
+	if q.dueUntil != "" {
+		conditions = append(conditions, "due <= ?")
+		args = append(args, q.dueUntil)
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+func intersectIDs(a []string, b []string) []string {
+	ids := []string{}
+	for _, id := range b {
+		for _, other := range a {
+			if id == other {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// joinStore decodes the rows and fields of splitStore into s.
+func joinStore(tables map[string][]cacheRow, meta map[string]string, s *todoist.Store) error {
+	fields := map[string]interface{}{}
+	for key, value := range meta {
+		fields[key] = json.RawMessage(value)
+	}
+	for _, table := range cacheTables {
+		objects := make([]json.RawMessage, len(tables[table]))
+		for i, row := range tables[table] {
+			objects[i] = json.RawMessage(row.Data)
+		}
+		fields[table] = objects
+	}
+	buf, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(buf, s)
+}
@@ -0,0 +1,24 @@
+//go:build !sqlite
+// +build !sqlite
+
+package main
+
+import (
+	"github.com/sachaos/todoist/lib"
+)
+
+// SQLiteUnsupported is returned for database caches by builds without the
+// sqlite tag, which needs cgo.
+var SQLiteUnsupported = &Error{Code: "sqlite_unsupported", Message: "this build of todoist has no SQLite support", Hint: "build with `go build -tags sqlite` or use a cache path not ending in .db"}
+
+func readDatabaseCache(filename string, s *todoist.Store, items bool) error {
+	return SQLiteUnsupported
+}
+
+func readDatabaseItems(filename string, q itemQuery) (todoist.Items, error) {
+	return nil, SQLiteUnsupported
+}
+
+func writeDatabaseCache(filename string, s *todoist.Store) error {
+	return SQLiteUnsupported
+}
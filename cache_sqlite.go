@@ -0,0 +1,333 @@
+//go:build sqlite
+// +build sqlite
+
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sachaos/todoist/lib"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// itemColumns are the columns items have besides those of every table, for
+// the queries of filters.
+var itemColumns = []string{"parent_id TEXT", "checked INTEGER NOT NULL DEFAULT 0", "priority INTEGER NOT NULL DEFAULT 0", "due TEXT"}
+
+func openDatabaseCache(filename string) (*sql.DB, error) {
+	// SQLite locks the database itself, a busy timeout makes the daemon
+	// and other commands wait for each other instead of failing.
+	db, err := sql.Open("sqlite3", "file:"+filename+"?_busy_timeout=5000&_journal_mode=WAL")
+	if err != nil {
+		return nil, err
+	}
+	statements := []string{"CREATE TABLE IF NOT EXISTS meta (key TEXT PRIMARY KEY, value TEXT NOT NULL)"}
+	for _, table := range cacheTables {
+		columns := "id TEXT PRIMARY KEY, project_id TEXT, position INTEGER NOT NULL, data TEXT NOT NULL"
+		if table == "items" {
+			columns += ", " + strings.Join(itemColumns, ", ")
+		}
+		statements = append(statements, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", table, columns))
+		statements = append(statements, fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_project_id ON %s (project_id)", table, table))
+	}
+	statements = append(statements, "CREATE TABLE IF NOT EXISTS item_labels (item_id TEXT NOT NULL, name TEXT NOT NULL, PRIMARY KEY (item_id, name))")
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	if err := migrateDatabaseCache(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// migrateDatabaseCache adds the columns and indexes of filters to the items
+// of caches written before there were any, filling them in from the data of
+// the items.
+func migrateDatabaseCache(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+	if version >= 1 {
+		return nil
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	existing := map[string]bool{}
+	info, err := tx.Query("SELECT name FROM pragma_table_info('items')")
+	if err != nil {
+		return err
+	}
+	for info.Next() {
+		var name string
+		if err := info.Scan(&name); err != nil {
+			info.Close()
+			return err
+		}
+		existing[name] = true
+	}
+	info.Close()
+	if err := info.Err(); err != nil {
+		return err
+	}
+	added := false
+	for _, column := range itemColumns {
+		if !existing[strings.Fields(column)[0]] {
+			if _, err := tx.Exec("ALTER TABLE items ADD COLUMN " + column); err != nil {
+				return err
+			}
+			added = true
+		}
+	}
+	if added {
+		rows, err := tx.Query("SELECT data FROM items")
+		if err != nil {
+			return err
+		}
+		items := []cacheRow{}
+		for rows.Next() {
+			var data string
+			if err := rows.Scan(&data); err != nil {
+				rows.Close()
+				return err
+			}
+			row, err := newCacheRow("items", json.RawMessage(data))
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			items = append(items, row)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		for _, row := range items {
+			if _, err := tx.Exec("UPDATE items SET parent_id = ?, checked = ?, priority = ?, due = ? WHERE id = ?", nullString(row.ParentID), row.Checked, row.Priority, nullString(row.Due), row.ID); err != nil {
+				return err
+			}
+			if err := writeItemLabels(tx, row); err != nil {
+				return err
+			}
+		}
+	}
+	for _, statement := range []string{
+		"CREATE INDEX IF NOT EXISTS items_parent_id ON items (parent_id)",
+		"CREATE INDEX IF NOT EXISTS items_checked_due ON items (checked, due)",
+		"CREATE INDEX IF NOT EXISTS items_checked_priority ON items (checked, priority)",
+		"CREATE INDEX IF NOT EXISTS item_labels_name ON item_labels (name)",
+		"PRAGMA user_version = 1",
+	} {
+		if _, err := tx.Exec(statement); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// writeItemLabels replaces the labels of the item of row.
+func writeItemLabels(tx *sql.Tx, row cacheRow) error {
+	if _, err := tx.Exec("DELETE FROM item_labels WHERE item_id = ?", row.ID); err != nil {
+		return err
+	}
+	for _, name := range row.Labels {
+		if _, err := tx.Exec("INSERT OR IGNORE INTO item_labels (item_id, name) VALUES (?, ?)", row.ID, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readTable reads the rows of table in order, only those of the ids selected
+// by the query with, like a common table expression, if not "".
+func readTable(db *sql.DB, table string, with string, args ...interface{}) ([]cacheRow, error) {
+	query := fmt.Sprintf("SELECT id, project_id, position, data FROM %s ORDER BY position", table)
+	if with != "" {
+		query = fmt.Sprintf("%sSELECT id, project_id, position, data FROM %s WHERE id IN (SELECT id FROM selected) ORDER BY position", with, table)
+	}
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := []cacheRow{}
+	for rows.Next() {
+		var row cacheRow
+		var projectID sql.NullString
+		if err := rows.Scan(&row.ID, &projectID, &row.Position, &row.Data); err != nil {
+			return nil, err
+		}
+		row.ProjectID = projectID.String
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// readDatabaseCache reads the cache at filename into s, leaving the items
+// out unless items is set.
+func readDatabaseCache(filename string, s *todoist.Store, items bool) error {
+	// Opening creates the database, which must not happen when reading.
+	if _, err := os.Stat(filename); err != nil {
+		return err
+	}
+	db, err := openDatabaseCache(filename)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tables := map[string][]cacheRow{}
+	for _, table := range cacheTables {
+		if table == "items" && !items {
+			continue
+		}
+		if tables[table], err = readTable(db, table, ""); err != nil {
+			return err
+		}
+	}
+	meta := map[string]string{}
+	rows, err := db.Query("SELECT key, value FROM meta")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return err
+		}
+		meta[key] = value
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return joinStore(tables, meta, s)
+}
+
+// readDatabaseItems returns the items of the cache at filename selected by
+// q, and their ancestors, which listing them needs too.
+func readDatabaseItems(filename string, q itemQuery) (todoist.Items, error) {
+	db, err := openDatabaseCache(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	where, args := q.where()
+	rows, err := readTable(db, "items", fmt.Sprintf(`WITH RECURSIVE selected(id, parent_id) AS (
+		SELECT id, parent_id FROM items WHERE %s
+		UNION SELECT items.id, items.parent_id FROM items JOIN selected ON items.id = selected.parent_id
+	) `, where), args...)
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]json.RawMessage, len(rows))
+	for i, row := range rows {
+		objects[i] = json.RawMessage(row.Data)
+	}
+	buf, err := json.Marshal(objects)
+	if err != nil {
+		return nil, err
+	}
+	items := todoist.Items{}
+	return items, json.Unmarshal(buf, &items)
+}
+
+func writeTable(tx *sql.Tx, table string, rows []cacheRow) error {
+	existing := map[string]cacheRow{}
+	old, err := tx.Query(fmt.Sprintf("SELECT id, position, data FROM %s", table))
+	if err != nil {
+		return err
+	}
+	for old.Next() {
+		var row cacheRow
+		if err := old.Scan(&row.ID, &row.Position, &row.Data); err != nil {
+			old.Close()
+			return err
+		}
+		existing[row.ID] = row
+	}
+	old.Close()
+	if err := old.Err(); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		previous, ok := existing[row.ID]
+		delete(existing, row.ID)
+		if ok && previous.Position == row.Position && previous.Data == row.Data {
+			continue
+		}
+		if table == "items" {
+			_, err = tx.Exec("INSERT OR REPLACE INTO items (id, project_id, position, data, parent_id, checked, priority, due) VALUES (?, ?, ?, ?, ?, ?, ?, ?)", row.ID, row.ProjectID, row.Position, row.Data, nullString(row.ParentID), row.Checked, row.Priority, nullString(row.Due))
+			if err == nil {
+				err = writeItemLabels(tx, row)
+			}
+		} else {
+			_, err = tx.Exec(fmt.Sprintf("INSERT OR REPLACE INTO %s (id, project_id, position, data) VALUES (?, ?, ?, ?)", table), row.ID, row.ProjectID, row.Position, row.Data)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for id := range existing {
+		if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE id = ?", table), id); err != nil {
+			return err
+		}
+		if table == "items" {
+			if _, err := tx.Exec("DELETE FROM item_labels WHERE item_id = ?", id); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writeDatabaseCache(filename string, s *todoist.Store) error {
+	tables, meta, err := splitStore(s)
+	if err != nil {
+		return err
+	}
+	db, err := openDatabaseCache(filename)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, table := range cacheTables {
+		if err := writeTable(tx, table, tables[table]); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec("DELETE FROM meta"); err != nil {
+		return err
+	}
+	for key, value := range meta {
+		if _, err := tx.Exec("INSERT INTO meta (key, value) VALUES (?, ?)", key, value); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
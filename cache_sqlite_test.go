@@ -0,0 +1,158 @@
+//go:build sqlite
+// +build sqlite
+
+package main
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/sachaos/todoist/lib/todoisttest"
+)
+
+func TestDatabaseCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "todoist")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "cache.db")
+
+	var store todoist.Store
+	assert.Error(t, ReadCache(filename, &store))
+
+	store = todoist.Store{SyncToken: "token", Items: todoist.Items{
+		todoist.Item{BaseItem: todoist.BaseItem{HaveID: todoist.HaveID{ID: "1"}, Content: "first"}},
+		todoist.Item{BaseItem: todoist.BaseItem{HaveID: todoist.HaveID{ID: "2"}, Content: "second"}},
+	}}
+	assert.NoError(t, WriteCache(filename, &store))
+
+	store.Items = store.Items[1:]
+	store.Items[0].Content = "changed"
+	assert.NoError(t, WriteCache(filename, &store))
+
+	var read todoist.Store
+	assert.NoError(t, ReadCache(filename, &read))
+	assert.Equal(t, "token", read.SyncToken, "they should be equal")
+	assert.Equal(t, 1, len(read.Items), "they should be equal")
+	assert.Equal(t, "changed", read.FindItem("2").Content, "they should be equal")
+}
+
+func TestDatabaseCacheItems(t *testing.T) {
+	dir, err := ioutil.TempDir("", "todoist")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "cache.db")
+
+	full := testStore(t, `{
+		"projects": [{"id": "1", "name": "Home"}, {"id": "2", "name": "Work"}, {"id": "3", "name": "Garden", "parent_id": "1"}],
+		"labels": [{"id": "5", "name": "errand"}],
+		"items": [
+			{"id": "10", "project_id": "1", "content": "Clean up", "priority": 1},
+			{"id": "11", "project_id": "1", "parent_id": "10", "content": "Buy soap", "priority": 4, "labels": ["errand"], "due": {"date": "2020-01-06"}},
+			{"id": "12", "project_id": "2", "content": "Write the report", "priority": 4, "due": {"date": "2020-01-07T09:00:00"}},
+			{"id": "13", "project_id": "3", "content": "Mow", "labels": ["errand"], "due": {"date": "2020-01-20"}},
+			{"id": "14", "project_id": "2", "content": "Done", "priority": 4, "checked": true}
+		]
+	}`)
+	assert.NoError(t, WriteCache(filename, full))
+	defer func() { partialStore = nil }()
+
+	for _, filter := range []string{"", "#Home", "##Home", "#Home & @errand", "p1", "p1 & #Work", "no date", "due before: 7/1/2020", "due after: 8/1/2020", "7/1/2020", "@errand | #Work", "!p1"} {
+		var store todoist.Store
+		assert.NoError(t, ReadCacheWithoutItems(filename, &store))
+		assert.Equal(t, 0, len(store.Items), filter)
+		assert.NoError(t, ReadCacheItems(filename, &store, Filter(filter)))
+
+		ids := func(store *todoist.Store) []string {
+			ids := []string{}
+			for _, item := range FilterItems(store, Filter(filter)) {
+				ids = append(ids, item.ID)
+			}
+			return ids
+		}
+		assert.Equal(t, ids(full), ids(&store), filter)
+	}
+
+	// The indexes narrow the tasks down, with their parents.
+	var store todoist.Store
+	assert.NoError(t, ReadCacheWithoutItems(filename, &store))
+	assert.NoError(t, ReadCacheItems(filename, &store, Filter("p1 & @errand")))
+	assert.Equal(t, 2, len(store.Items), "they should be equal")
+	assert.NotNil(t, store.FindItem("10"))
+	assert.Error(t, WriteCache(filename, &store))
+
+	assert.NoError(t, ReadCacheItems(filename, &store, nil))
+	assert.Equal(t, 5, len(store.Items), "they should be equal")
+	assert.NoError(t, WriteCache(filename, &store))
+}
+
+func TestDatabaseCacheMigration(t *testing.T) {
+	dir, err := ioutil.TempDir("", "todoist")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "cache.db")
+
+	// A cache written before items had the columns of filters.
+	db, err := sql.Open("sqlite3", filename)
+	assert.NoError(t, err)
+	for _, statement := range []string{
+		"CREATE TABLE items (id TEXT PRIMARY KEY, project_id TEXT, position INTEGER NOT NULL, data TEXT NOT NULL)",
+		`INSERT INTO items VALUES ('10', '1', 0, '{"id": "10", "project_id": "1", "content": "Buy soap", "priority": 4, "labels": ["errand"]}')`,
+		`INSERT INTO items VALUES ('11', '1', 1, '{"id": "11", "project_id": "1", "content": "Clean up", "priority": 1}')`,
+	} {
+		_, err := db.Exec(statement)
+		assert.NoError(t, err)
+	}
+	db.Close()
+	defer func() { partialStore = nil }()
+
+	var store todoist.Store
+	assert.NoError(t, ReadCacheWithoutItems(filename, &store))
+	assert.NoError(t, ReadCacheItems(filename, &store, Filter("p1 & @errand")))
+	if assert.Equal(t, 1, len(store.Items), "they should be equal") {
+		assert.Equal(t, "Buy soap", store.Items[0].Content, "they should be equal")
+	}
+}
+
+func TestDatabaseCacheList(t *testing.T) {
+	server := todoisttest.NewServer(t, `{
+		"user": {"id": "1", "inbox_project_id": "1"},
+		"projects": [{"id": "1", "name": "Inbox", "inbox_project": true}, {"id": "2", "name": "Work"}],
+		"items": [
+			{"id": "10", "project_id": "2", "content": "Write the report", "priority": 4},
+			{"id": "11", "project_id": "2", "parent_id": "10", "content": "Add the figures"},
+			{"id": "12", "project_id": "1", "content": "Buy milk"}
+		]
+	}`)
+	run := runTodoist(t, server)
+	default_cache_path = filepath.Join(t.TempDir(), "cache.db")
+	defer func() { partialStore = nil }()
+
+	_, err := run("sync")
+	assert.NoError(t, err)
+	out, err := run("list")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(strings.Split(strings.TrimSpace(out), "\n")), "they should be equal")
+
+	out, err = run("--namespace", "list", "--filter", "#Work")
+	assert.NoError(t, err)
+	assert.Contains(t, out, "Write the report:Add the figures")
+	assert.NotContains(t, out, "Buy milk")
+	out, err = run("list", "--filter", "p1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(strings.Split(strings.TrimSpace(out), "\n")), "they should be equal")
+	assert.Contains(t, out, "Write the report")
+
+	// Commands changing tasks read all of them and write the cache.
+	_, err = run("close", "12")
+	assert.NoError(t, err)
+	out, err = run("list")
+	assert.NoError(t, err)
+	assert.NotContains(t, out, "Buy milk")
+}
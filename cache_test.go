@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sachaos/todoist/lib"
+)
+
+func TestCacheConcurrentAccess(t *testing.T) {
+	dir, err := ioutil.TempDir("", "todoist")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "cache.json")
+
+	store := &todoist.Store{SyncToken: "token"}
+	assert.NoError(t, WriteCache(filename, store))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, WriteCache(filename, store))
+		}()
+		go func() {
+			defer wg.Done()
+			var s todoist.Store
+			assert.NoError(t, ReadCache(filename, &s))
+			assert.Equal(t, "token", s.SyncToken, "they should be equal")
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLoadCacheOutdated(t *testing.T) {
+	dir, err := ioutil.TempDir("", "todoist")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "cache.json")
+
+	var store todoist.Store
+	outdated, err := LoadCache(filename, &store)
+	assert.NoError(t, err)
+	assert.Equal(t, false, outdated, "they should be equal")
+
+	old := `{"sync_token": "token", "items": [{"id": 1, "project_id": 2, "checked": 0}]}`
+	assert.NoError(t, ioutil.WriteFile(filename, []byte(old), 0600))
+	outdated, err = LoadCache(filename, &store)
+	assert.NoError(t, err)
+	assert.Equal(t, true, outdated, "they should be equal")
+	assert.Equal(t, "", store.SyncToken, "they should be equal")
+
+	outdated, err = LoadCache(filename, &store)
+	assert.NoError(t, err)
+	assert.Equal(t, false, outdated, "they should be equal")
+
+	// A cache which can't be read is not taken for an old one and replaced.
+	truncated := `{"cache_version": 2, "sync_token": "token", "items": [{"id": "1"`
+	assert.NoError(t, ioutil.WriteFile(filename, []byte(truncated), 0600))
+	_, err = LoadCache(filename, &store)
+	assert.Equal(t, "cache_error", AsError(err).Code, "they should be equal")
+	buf, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, truncated, string(buf), "they should be equal")
+}
+
+func TestSplitStore(t *testing.T) {
+	var store todoist.Store
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"sync_token": "token",
+		"projects": [{"id": "1", "name": "Inbox"}],
+		"items": [{"id": "10", "project_id": "1", "content": "first"}, {"id": "11", "project_id": "1", "content": "second"}]
+	}`), &store))
+
+	tables, meta, err := splitStore(&store)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(tables["items"]), "they should be equal")
+	assert.Equal(t, "11", tables["items"][1].ID, "they should be equal")
+	assert.Equal(t, "1", tables["items"][1].ProjectID, "they should be equal")
+	assert.Equal(t, `"token"`, meta["sync_token"], "they should be equal")
+
+	var joined todoist.Store
+	assert.NoError(t, joinStore(tables, meta, &joined))
+	assert.Equal(t, store.SyncToken, joined.SyncToken, "they should be equal")
+	assert.Equal(t, store.Items, joined.Items, "they should be equal")
+	assert.Equal(t, store.Projects, joined.Projects, "they should be equal")
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/urfave/cli"
+)
+
+// Capture adds a task with a single quick add request, to the Inbox unless
+// the text names a project. The cache isn't read nor written, so that it
+// returns as soon as Todoist answers; the next sync brings the task into it.
+func Capture(c *cli.Context) error {
+	client := GetClient(c)
+
+	text := strings.TrimSpace(strings.Join(c.Args(), " "))
+	if text == "" {
+		return ArgumentRequired
+	}
+	if err := client.QuickCommand(GetContext(c), text); err != nil {
+		return err
+	}
+	if c.GlobalBool("sandbox") {
+		// The sandbox has no account to keep the task but its cache.
+		return Sync(c)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/sachaos/todoist/lib/todoisttest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCapture(t *testing.T) {
+	server := todoisttest.NewServer(t, `{
+		"user": {"id": "1", "inbox_project_id": "1"},
+		"projects": [{"id": "1", "name": "Inbox", "inbox_project": true}]
+	}`)
+	run := runTodoist(t, server)
+
+	// A cache which can't be read shows that capture doesn't read it, and
+	// it is left as it is.
+	assert.NoError(t, ioutil.WriteFile(default_cache_path, []byte("not json"), 0600))
+	_, err := run("capture", "call", "the", "bank")
+	assert.NoError(t, err)
+	cache, err := ioutil.ReadFile(default_cache_path)
+	assert.NoError(t, err)
+	assert.Equal(t, "not json", string(cache), "they should be equal")
+
+	client := server.NewClient()
+	assert.NoError(t, client.Sync(context.Background()))
+	if assert.Len(t, client.Store.Items, 1) {
+		assert.Equal(t, "call the bank", client.Store.Items[0].Content, "they should be equal")
+		assert.Equal(t, "1", client.Store.Items[0].ProjectID, "they should be equal")
+	}
+
+	_, err = run("capture")
+	assert.Equal(t, ArgumentRequired, err, "they should be equal")
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/urfave/cli"
+)
+
+// The archive, like the history, is appended to as one JSON entry per line.
+var archivePath = filepath.Join(configPath, ".todoist.archive.jsonl")
+
+// ArchiveEntry is what one cleanup pruned from the cache and the history.
+type ArchiveEntry struct {
+	Time    time.Time      `json:"time"`
+	Items   todoist.Items  `json:"items"`
+	Notes   todoist.Notes  `json:"notes,omitempty"`
+	History []HistoryEntry `json:"history,omitempty"`
+}
+
+// parseDuration parses a number of days or weeks like 90d or 12w, or a
+// duration like 30m or 36h.
+func parseDuration(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, err := strconv.Atoi(strings.TrimSuffix(s, suffix)); strings.HasSuffix(s, suffix) && err == nil && n >= 0 {
+			return time.Duration(n) * unit, nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return d, nil
+	}
+	return 0, &Error{Code: "invalid_argument", Message: fmt.Sprintf("invalid duration %q", s), Hint: "use a number of minutes, hours, days or weeks like 30m, 2h, 90d or 12w"}
+}
+
+// completedBefore reports whether item was completed before cutoff. Tasks
+// without a completion time are judged by when they were last updated.
+func completedBefore(item *todoist.Item, cutoff time.Time) bool {
+	if !item.Checked {
+		return false
+	}
+	for _, at := range []string{item.CompletedAt, item.UpdatedAt} {
+		if t, err := time.Parse(time.RFC3339, at); err == nil {
+			return t.Before(cutoff)
+		}
+	}
+	return false
+}
+
+// pruneCompleted removes the tasks completed before cutoff from store, with
+// their comments, and returns them.
+func pruneCompleted(store *todoist.Store, cutoff time.Time) (todoist.Items, todoist.Notes) {
+	pruned := todoist.Items{}
+	kept := todoist.Items{}
+	ids := map[string]bool{}
+	for _, item := range store.Items {
+		if completedBefore(&item, cutoff) {
+			pruned = append(pruned, item)
+			ids[item.ID] = true
+		} else {
+			kept = append(kept, item)
+		}
+	}
+
+	prunedNotes := todoist.Notes{}
+	keptNotes := todoist.Notes{}
+	for _, note := range store.Notes {
+		if ids[note.ItemID] {
+			prunedNotes = append(prunedNotes, note)
+		} else {
+			keptNotes = append(keptNotes, note)
+		}
+	}
+
+	store.Items = kept
+	store.Notes = keptNotes
+	store.ConstructItemTree()
+	return pruned, prunedNotes
+}
+
+// pruneHistory splits entries into those made from cutoff on and those
+// before.
+func pruneHistory(entries []HistoryEntry, cutoff time.Time) ([]HistoryEntry, []HistoryEntry) {
+	kept := []HistoryEntry{}
+	pruned := []HistoryEntry{}
+	for _, entry := range entries {
+		if entry.Time.Before(cutoff) {
+			pruned = append(pruned, entry)
+		} else {
+			kept = append(kept, entry)
+		}
+	}
+	return kept, pruned
+}
+
+func CleanupCompleted(c *cli.Context) error {
+	client := GetClient(c)
+
+	age, err := parseDuration(c.String("older-than"))
+	if err != nil {
+		return err
+	}
+	cutoff := time.Now().Add(-age)
+
+	entries, err := readHistory()
+	if err != nil {
+		return err
+	}
+	keptHistory, prunedHistory := pruneHistory(entries, cutoff)
+	items, notes := pruneCompleted(client.Store, cutoff)
+
+	if len(items) == 0 && len(prunedHistory) == 0 {
+		fmt.Println("Nothing to clean up.")
+		return nil
+	}
+
+	// The archive is written first, so nothing is lost if pruning fails.
+	archive := c.String("archive")
+	if archive == "" {
+		archive = archivePath
+	}
+	entry := ArchiveEntry{Time: time.Now(), Items: items, Notes: notes, History: prunedHistory}
+	if err := appendJSONLine(archive, entry); err != nil {
+		return err
+	}
+	if len(items) > 0 {
+		if err := WriteCache(default_cache_path, client.Store); err != nil {
+			return err
+		}
+	}
+	if len(prunedHistory) > 0 {
+		if err := writeHistory(keptHistory); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Archived %d completed task(s) and %d history entries into %s\n", len(items), len(prunedHistory), archive)
+	return nil
+}
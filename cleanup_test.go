@@ -0,0 +1,56 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAge(t *testing.T) {
+	for s, expected := range map[string]time.Duration{
+		"90d": 90 * 24 * time.Hour,
+		"2w":  14 * 24 * time.Hour,
+		"36h": 36 * time.Hour,
+	} {
+		age, err := parseDuration(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, expected, age, "they should be equal")
+	}
+	_, err := parseDuration("soon")
+	assert.Error(t, err, "they should be equal")
+}
+
+func TestPruneCompleted(t *testing.T) {
+	store := testStore(t, `{
+		"items": [
+			{"id": "1", "content": "open", "checked": false},
+			{"id": "2", "content": "old", "checked": true, "completed_at": "2020-01-01T10:00:00Z"},
+			{"id": "3", "content": "recent", "checked": true, "completed_at": "2020-03-01T10:00:00Z"},
+			{"id": "4", "content": "old without completion time", "checked": true, "updated_at": "2019-12-01T10:00:00Z"}
+		],
+		"notes": [
+			{"id": "10", "item_id": "2", "content": "gone with its task"},
+			{"id": "11", "item_id": "3", "content": "kept"}
+		]
+	}`)
+	cutoff := time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	items, notes := pruneCompleted(store, cutoff)
+	ids := []string{}
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	assert.Equal(t, []string{"2", "4"}, ids, "they should be equal")
+	assert.Equal(t, 1, len(notes), "they should be equal")
+	assert.Equal(t, 2, len(store.Items), "they should be equal")
+	assert.Nil(t, store.FindItem("2"), "they should be equal")
+	assert.Equal(t, "11", store.Notes[0].ID, "they should be equal")
+
+	kept, pruned := pruneHistory([]HistoryEntry{
+		{Time: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), Command: "old"},
+		{Time: time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), Command: "recent"},
+	}, cutoff)
+	assert.Equal(t, "recent", kept[0].Command, "they should be equal")
+	assert.Equal(t, "old", pruned[0].Command, "they should be equal")
+}
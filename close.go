@@ -1,18 +1,15 @@
 package main
 
 import (
-	"context"
-	"strconv"
-
 	"github.com/urfave/cli"
 )
 
 func Close(c *cli.Context) error {
 	client := GetClient(c)
 
-	item_ids := []int{}
+	item_ids := []string{}
 	for _, arg := range c.Args() {
-		item_id, err := strconv.Atoi(arg)
+		item_id, err := ResolveItemID(client, arg)
 		if err != nil {
 			return err
 		}
@@ -20,10 +17,16 @@ func Close(c *cli.Context) error {
 	}
 
 	if len(item_ids) == 0 {
-		return CommandFailed
+		return ArgumentRequired
+	}
+
+	if len(item_ids) > 1 {
+		if err := confirmItems(client.Store, "Close", item_ids, c.Bool("yes")); err != nil {
+			return err
+		}
 	}
 
-	if err := client.CloseItem(context.Background(), item_ids); err != nil {
+	if err := client.CloseItem(GetContext(c), item_ids); err != nil {
 		return err
 	}
 
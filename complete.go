@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/urfave/cli"
+)
+
+// completionKinds are what `todoist _complete` completes.
+var completionKinds = []string{"tasks", "projects", "labels"}
+
+// Completion is a candidate of `todoist _complete` and what it is, like the
+// content of a task for its ID.
+type Completion struct {
+	Value       string
+	Description string
+}
+
+// oneLine joins the lines and runs of spaces of s, so that it can't break
+// the format of completions.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// completions returns the open tasks, projects or labels of store whose
+// value or description starts with prefix, ignoring case. Tasks keep the
+// order of the store, projects and labels are sorted by name.
+func completions(store *todoist.Store, kind string, prefix string) ([]Completion, error) {
+	candidates := []Completion{}
+	switch kind {
+	case "tasks":
+		for _, item := range store.Items {
+			if !item.Checked && !item.IsDeleted {
+				candidates = append(candidates, Completion{item.ID, oneLine(todoist.GetContentTitle(item))})
+			}
+		}
+	case "projects":
+		for i := range store.Projects {
+			project := &store.Projects[i]
+			if project.IsArchived || project.IsDeleted {
+				continue
+			}
+			names := []string{}
+			for _, parent := range todoist.SearchProjectParents(store, project) {
+				names = append(names, parent.Name)
+			}
+			candidates = append(candidates, Completion{oneLine(project.Name), oneLine(strings.Join(append(names, project.Name), "/"))})
+		}
+	case "labels":
+		for _, label := range store.Labels {
+			if !label.IsDeleted {
+				candidates = append(candidates, Completion{Value: oneLine(label.Name)})
+			}
+		}
+	default:
+		return nil, &Error{Code: "invalid_argument", Message: fmt.Sprintf("unknown completion %q", kind), Hint: "complete any of " + strings.Join(completionKinds, ", ")}
+	}
+	if kind != "tasks" {
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return strings.ToLower(candidates[i].Value) < strings.ToLower(candidates[j].Value)
+		})
+	}
+
+	prefix = strings.ToLower(prefix)
+	matches := []Completion{}
+	for _, candidate := range candidates {
+		if strings.HasPrefix(strings.ToLower(candidate.Value), prefix) || strings.HasPrefix(strings.ToLower(candidate.Description), prefix) {
+			matches = append(matches, candidate)
+		}
+	}
+	return matches, nil
+}
+
+// writeCompletions writes one completion per line, the value and the
+// description separated by a tab, as fish and launchers like rofi take them.
+// Completions without description are the value alone.
+func writeCompletions(w io.Writer, matches []Completion) {
+	for _, match := range matches {
+		if match.Description == "" {
+			fmt.Fprintln(w, match.Value)
+		} else {
+			fmt.Fprintf(w, "%s\t%s\n", match.Value, match.Description)
+		}
+	}
+}
+
+func Complete(c *cli.Context) error {
+	if !c.Args().Present() {
+		return ArgumentRequired
+	}
+	matches, err := completions(GetClient(c).Store, c.Args().First(), c.String("prefix"))
+	if err != nil {
+		return err
+	}
+	// The format is the same whatever --output is, for the tools reading it.
+	writeCompletions(c.App.Writer, matches)
+	return nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompletions(t *testing.T) {
+	store := testStore(t, `{
+		"projects": [
+			{"id": "1", "name": "Work"},
+			{"id": "2", "name": "ClientA", "parent_id": "1"},
+			{"id": "3", "name": "Old", "is_archived": true}
+		],
+		"labels": [{"id": "5", "name": "waiting"}, {"id": "6", "name": "Errand"}],
+		"items": [
+			{"id": "10", "project_id": "1", "content": "Water\nplants"},
+			{"id": "11", "project_id": "1", "content": "[Read](https://example.com) the docs"},
+			{"id": "12", "project_id": "1", "content": "Done", "checked": true}
+		]
+	}`)
+
+	matches, err := completions(store, "tasks", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []Completion{{"10", "Water plants"}, {"11", "Read the docs"}}, matches, "they should be equal")
+
+	matches, err = completions(store, "tasks", "wat")
+	assert.NoError(t, err)
+	assert.Equal(t, []Completion{{"10", "Water plants"}}, matches, "they should be equal")
+
+	matches, err = completions(store, "projects", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []Completion{{"ClientA", "Work/ClientA"}, {"Work", "Work"}}, matches, "they should be equal")
+
+	matches, err = completions(store, "projects", "work/")
+	assert.NoError(t, err)
+	assert.Equal(t, []Completion{{"ClientA", "Work/ClientA"}}, matches, "they should be equal")
+
+	matches, err = completions(store, "labels", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []Completion{{Value: "Errand"}, {Value: "waiting"}}, matches, "they should be equal")
+
+	_, err = completions(store, "filters", "")
+	assert.Error(t, err)
+
+	var out bytes.Buffer
+	writeCompletions(&out, []Completion{{"10", "Water plants"}, {Value: "waiting"}})
+	assert.Equal(t, "10\tWater plants\nwaiting\n", out.String(), "they should be equal")
+}
@@ -1,8 +1,6 @@
 package main
 
 import (
-	"context"
-
 	"github.com/sachaos/todoist/lib"
 
 	"github.com/urfave/cli"
@@ -12,25 +10,24 @@ func CompletedList(c *cli.Context) error {
 	client := GetClient(c)
 
 	colorList := ColorList()
-	var projectIds []int
+	var projectIds []string
 	for _, project := range client.Store.Projects {
 		projectIds = append(projectIds, project.GetID())
 	}
 	projectColorHash := GenerateColorHash(projectIds, colorList)
-	ex := Filter(c.String("filter"))
-
-	var completed todoist.Completed
-
-	if err := client.CompletedAll(context.Background(), &completed); err != nil {
+	filter, err := ApplyContext(c.String("filter"))
+	if err != nil {
 		return err
 	}
+	ex := Filter(filter)
 
-	defer writer.Flush()
+	var completed todoist.Completed
 
-	if c.GlobalBool("header") {
-		writer.Write([]string{"ID", "CompletedDate", "Project", "Content"})
+	if err := client.CompletedAll(GetContext(c), &completed); err != nil {
+		return err
 	}
 
+	itemList := [][]string{}
 	for _, item := range completed.Items {
 		result, err := Eval(ex, item, client.Store.Projects, client.Store.Labels)
 		if err != nil {
@@ -39,7 +36,7 @@ func CompletedList(c *cli.Context) error {
 		if !result {
 			continue
 		}
-		writer.Write([]string{
+		itemList = append(itemList, []string{
 			IdFormat(item),
 			CompletedDateFormat(item.DateTime()),
 			ProjectFormat(item.ProjectID, client.Store, projectColorHash, c),
@@ -47,5 +44,15 @@ func CompletedList(c *cli.Context) error {
 		})
 	}
 
+	TruncateColumn(itemList, 3, OutputWidth(c))
+
+	defer writer.Flush()
+
+	writer.WriteHeader([]string{"ID", "CompletedDate", "Project", "Content"})
+
+	for _, strings := range itemList {
+		writer.Write(strings)
+	}
+
 	return nil
 }
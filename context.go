@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/urfave/cli"
+)
+
+var contextPath = filepath.Join(configPath, ".todoist.context.json")
+
+// ContextState is the context currently in use.
+type ContextState struct {
+	Name string `json:"name"`
+}
+
+func contextFilters() map[string]string {
+	return viper.GetStringMapString("contexts")
+}
+
+func currentContext() (string, string, error) {
+	var state ContextState
+	if err := readJSONFile(contextPath, &state); err != nil {
+		return "", "", err
+	}
+	if state.Name == "" {
+		return "", "", nil
+	}
+	filter, ok := contextFilters()[state.Name]
+	if !ok {
+		return "", "", contextNotFound(state.Name)
+	}
+	return state.Name, filter, nil
+}
+
+// ApplyContext combines filter with the filter of the current context.
+func ApplyContext(filter string) (string, error) {
+	_, contextFilter, err := currentContext()
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case contextFilter == "":
+		return filter, nil
+	case filter == "":
+		return contextFilter, nil
+	default:
+		return fmt.Sprintf("(%s) & (%s)", contextFilter, filter), nil
+	}
+}
+
+func contextNotFound(name string) error {
+	names := []string{}
+	for name := range contextFilters() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return &Error{
+		Code:    "context_not_found",
+		Message: fmt.Sprintf("context %q not found", name),
+		Hint:    "define it in the \"contexts\" section of the config, known contexts: " + strings.Join(names, ", "),
+	}
+}
+
+func ShowContext(c *cli.Context) error {
+	name, filter, err := currentContext()
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		fmt.Println("No context is in use.")
+		return nil
+	}
+	defer writer.Flush()
+	writer.WriteHeader([]string{"Name", "Filter"})
+	writer.Write([]string{name, filter})
+	return nil
+}
+
+func ListContexts(c *cli.Context) error {
+	current, _, _ := currentContext()
+	filters := contextFilters()
+	names := []string{}
+	for name := range filters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	defer writer.Flush()
+	writer.WriteHeader([]string{"Name", "Filter", "Current"})
+	for _, name := range names {
+		mark := ""
+		if name == current {
+			mark = "*"
+		}
+		writer.Write([]string{name, filters[name], mark})
+	}
+	return nil
+}
+
+func UseContext(c *cli.Context) error {
+	if !c.Args().Present() {
+		return ArgumentRequired
+	}
+	name := c.Args().First()
+	if _, ok := contextFilters()[name]; !ok {
+		return contextNotFound(name)
+	}
+	return writeJSONFile(contextPath, ContextState{Name: name})
+}
+
+func ClearContext(c *cli.Context) error {
+	return writeJSONFile(contextPath, ContextState{})
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyContext(t *testing.T) {
+	dir, err := ioutil.TempDir("", "todoist")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	saved := contextPath
+	contextPath = filepath.Join(dir, "context.json")
+	defer func() { contextPath = saved }()
+	viper.Set("contexts", map[string]string{"work": "#Work | @office"})
+	defer viper.Set("contexts", nil)
+
+	filter, err := ApplyContext("p1")
+	assert.NoError(t, err)
+	assert.Equal(t, "p1", filter, "they should be equal")
+
+	assert.NoError(t, writeJSONFile(contextPath, ContextState{Name: "work"}))
+	filter, err = ApplyContext("")
+	assert.NoError(t, err)
+	assert.Equal(t, "#Work | @office", filter, "they should be equal")
+	filter, err = ApplyContext("p1")
+	assert.NoError(t, err)
+	assert.Equal(t, "(#Work | @office) & (p1)", filter, "they should be equal")
+
+	assert.NoError(t, writeJSONFile(contextPath, ContextState{Name: "gone"}))
+	_, err = ApplyContext("")
+	assert.Error(t, err)
+}
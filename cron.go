@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is a schedule in the format of cron: minute, hour, day of
+// month, month and day of week, each a set of values.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// Like cron, when both days are restricted either of them matches.
+	domAny, dowAny bool
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonths = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+var cronDays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// parseCron parses spec, five fields or one of the @daily like macros.
+// Fields are *, values, ranges like 1-5, lists of them like 1,15 and steps
+// like */10, and months and days of week can be given by name.
+func parseCron(spec string) (*cronSchedule, error) {
+	if macro, ok := cronMacros[strings.ToLower(strings.TrimSpace(spec))]; ok {
+		spec = macro
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields, got %d", len(fields))
+	}
+
+	s := &cronSchedule{domAny: fields[2] == "*", dowAny: fields[4] == "*"}
+	var err error
+	if s.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("minute: %s", err)
+	}
+	if s.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("hour: %s", err)
+	}
+	if s.dom, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("day of month: %s", err)
+	}
+	if s.month, err = parseCronField(fields[3], 1, 12, cronMonths); err != nil {
+		return nil, fmt.Errorf("month: %s", err)
+	}
+	// 7 is Sunday too.
+	if s.dow, err = parseCronField(fields[4], 0, 7, cronDays); err != nil {
+		return nil, fmt.Errorf("day of week: %s", err)
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+// parseCronField returns the set of values of field between min and max,
+// which are named by names from min on.
+func parseCronField(field string, min int, max int, names []string) (uint64, error) {
+	value := func(s string) (int, error) {
+		for i, name := range names {
+			if strings.EqualFold(s, name) {
+				return min + i, nil
+			}
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n < min || n > max {
+			return 0, fmt.Errorf("%q is not a value from %d to %d", s, min, max)
+		}
+		return n, nil
+	}
+
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			step = n
+			part = part[:i]
+		}
+		first, last := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if first, err = value(bounds[0]); err != nil {
+				return 0, err
+			}
+			if last, err = value(bounds[1]); err != nil {
+				return 0, err
+			}
+			if first > last {
+				return 0, fmt.Errorf("invalid range %q", part)
+			}
+		default:
+			n, err := value(part)
+			if err != nil {
+				return 0, err
+			}
+			first = n
+			if step == 1 {
+				last = n
+			}
+		}
+		for n := first; n <= last; n += step {
+			set |= 1 << uint(n)
+		}
+	}
+	return set, nil
+}
+
+func (s *cronSchedule) matchesDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// Matches reports whether the minute of t is scheduled.
+func (s *cronSchedule) Matches(t time.Time) bool {
+	return s.minute&(1<<uint(t.Minute())) != 0 &&
+		s.hour&(1<<uint(t.Hour())) != 0 &&
+		s.month&(1<<uint(t.Month())) != 0 &&
+		s.matchesDay(t)
+}
+
+// Next returns the first scheduled minute after t, or zero if there is
+// none in the next five years, like on February 30th.
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	for i := 0; i < 5*366; i++ {
+		if s.month&(1<<uint(day.Month())) != 0 && s.matchesDay(day) {
+			for hour := 0; hour < 24; hour++ {
+				if s.hour&(1<<uint(hour)) == 0 {
+					continue
+				}
+				for minute := 0; minute < 60; minute++ {
+					next := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location())
+					if s.minute&(1<<uint(minute)) != 0 && !next.Before(t) {
+						return next
+					}
+				}
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return time.Time{}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCron(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "* * * foo *"} {
+		_, err := parseCron(spec)
+		assert.Error(t, err, spec)
+	}
+
+	// 2020-01-06 is a Monday.
+	at := func(day int, hour int, minute int) time.Time {
+		return time.Date(2020, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+	schedule, err := parseCron("0 8 * * mon-fri")
+	assert.NoError(t, err)
+	assert.True(t, schedule.Matches(at(6, 8, 0)))
+	assert.False(t, schedule.Matches(at(6, 8, 1)))
+	assert.False(t, schedule.Matches(at(5, 8, 0)))
+	assert.Equal(t, at(6, 8, 0), schedule.Next(at(4, 8, 0)), "they should be equal")
+	assert.Equal(t, at(7, 8, 0), schedule.Next(at(6, 8, 0)), "they should be equal")
+
+	schedule, err = parseCron("*/15 9-17 * * *")
+	assert.NoError(t, err)
+	assert.Equal(t, at(6, 9, 45), schedule.Next(at(6, 9, 30)), "they should be equal")
+	assert.Equal(t, at(7, 9, 0), schedule.Next(at(6, 17, 45)), "they should be equal")
+
+	// Either day matches when both are given, and 7 is Sunday.
+	schedule, err = parseCron("30 7 1,15 * 7")
+	assert.NoError(t, err)
+	assert.True(t, schedule.Matches(at(1, 7, 30)))
+	assert.True(t, schedule.Matches(at(5, 7, 30)))
+	assert.False(t, schedule.Matches(at(6, 7, 30)))
+
+	schedule, err = parseCron("@monthly")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC), schedule.Next(at(6, 8, 0)), "they should be equal")
+
+	schedule, err = parseCron("0 0 30 feb *")
+	assert.NoError(t, err)
+	assert.True(t, schedule.Next(at(6, 8, 0)).IsZero())
+}
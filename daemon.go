@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/urfave/cli"
+)
+
+var DaemonRunning = errors.New("sync daemon is already running")
+
+func daemonPidPath(cachePath string) string {
+	return cachePath + ".pid"
+}
+
+func daemonStalePath(cachePath string) string {
+	return cachePath + ".stale"
+}
+
+// daemonRunning reports whether a sync daemon is keeping cachePath fresh.
+func daemonRunning(cachePath string) bool {
+	buf, err := ioutil.ReadFile(daemonPidPath(cachePath))
+	if err != nil {
+		return false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(buf)))
+	if err != nil {
+		return false
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return process.Signal(syscall.Signal(0)) == nil
+}
+
+// invalidateCache asks the running daemon to sync as soon as possible.
+func invalidateCache(cachePath string) error {
+	return ioutil.WriteFile(daemonStalePath(cachePath), []byte{}, 0600)
+}
+
+func SyncDaemon(c *cli.Context) error {
+	if interval := c.Duration("interval"); interval <= 0 {
+		return &Error{Code: "invalid_argument", Message: fmt.Sprintf("invalid interval %s", interval), Hint: "give how often to sync like --interval 5m"}
+	}
+	client := GetClient(c)
+	cachePath := default_cache_path
+
+	if daemonRunning(cachePath) {
+		return DaemonRunning
+	}
+
+	pidPath := daemonPidPath(cachePath)
+	if err := ioutil.WriteFile(pidPath, []byte(strconv.Itoa(os.Getpid())), 0600); err != nil {
+		return err
+	}
+	defer os.Remove(pidPath)
+
+	sync := func() {
+		if err := client.Sync(GetContext(c)); err != nil {
+			fmt.Fprintln(os.Stderr, "sync failed:", err)
+			return
+		}
+		if err := WriteCache(cachePath, client.Store); err != nil {
+			fmt.Fprintln(os.Stderr, "writing cache failed:", err)
+		}
+	}
+
+	interval := time.NewTicker(c.Duration("interval"))
+	defer interval.Stop()
+	poll := time.NewTicker(time.Second)
+	defer poll.Stop()
+
+	sync()
+	for {
+		select {
+		case <-interval.C:
+			sync()
+		case <-poll.C:
+			stalePath := daemonStalePath(cachePath)
+			if _, err := os.Stat(stalePath); err == nil {
+				os.Remove(stalePath)
+				sync()
+			}
+		case <-GetContext(c).Done():
+			return nil
+		}
+	}
+}
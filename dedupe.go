@@ -0,0 +1,246 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/urfave/cli"
+)
+
+// normalizeContent reduces the content of item to what tells tasks apart:
+// the title without links, in lower case, with only letters, digits and
+// single spaces.
+func normalizeContent(item *todoist.Item) string {
+	words := strings.FieldsFunc(strings.ToLower(todoist.GetContentTitle(item)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// nearDuplicate reports whether the normalized contents a and b are equal,
+// or differ by no more than one edit in ten characters.
+func nearDuplicate(a string, b string) bool {
+	if a == b {
+		return true
+	}
+	allowed := max(len(a), len(b)) / 10
+	if allowed == 0 || abs(len(a)-len(b)) > allowed {
+		return false
+	}
+	return editDistance(a, b) <= allowed
+}
+
+// DuplicateClusters groups the items whose contents are near duplicates of
+// one another, directly or through other items, oldest first. Items without
+// a duplicate are left out.
+func DuplicateClusters(items []*todoist.Item) [][]*todoist.Item {
+	normalized := make([]string, len(items))
+	for i, item := range items {
+		normalized[i] = normalizeContent(item)
+	}
+
+	parent := make([]int, len(items))
+	for i := range parent {
+		parent[i] = i
+	}
+	var root func(i int) int
+	root = func(i int) int {
+		if parent[i] != i {
+			parent[i] = root(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range items {
+		for j := i + 1; j < len(items); j++ {
+			if normalized[i] != "" && nearDuplicate(normalized[i], normalized[j]) {
+				parent[root(j)] = root(i)
+			}
+		}
+	}
+
+	groups := map[int][]*todoist.Item{}
+	roots := []int{}
+	for i, item := range items {
+		r := root(i)
+		if _, ok := groups[r]; !ok {
+			roots = append(roots, r)
+		}
+		groups[r] = append(groups[r], item)
+	}
+
+	clusters := [][]*todoist.Item{}
+	for _, r := range roots {
+		cluster := groups[r]
+		if len(cluster) < 2 {
+			continue
+		}
+		sort.SliceStable(cluster, func(i, j int) bool {
+			if cluster[i].AddedAt != cluster[j].AddedAt {
+				return cluster[i].AddedAt < cluster[j].AddedAt
+			}
+			return naturalCompare(cluster[i].ID, cluster[j].ID) < 0
+		})
+		clusters = append(clusters, cluster)
+	}
+	return clusters
+}
+
+// mergeCommands folds the comments, labels and earliest due date of
+// duplicates into keep. A recurring due date of keep is left as it is, so
+// that the recurrence isn't lost.
+func mergeCommands(store *todoist.Store, keep *todoist.Item, duplicates []*todoist.Item) todoist.Commands {
+	commands := todoist.Commands{}
+
+	update := todoist.Item{BaseItem: todoist.BaseItem{HaveID: todoist.HaveID{ID: keep.ID}}}
+	changed := false
+
+	labels := append([]string{}, keep.LabelNames...)
+	has := map[string]bool{}
+	for _, name := range labels {
+		has[name] = true
+	}
+	for _, duplicate := range duplicates {
+		for _, name := range duplicate.LabelNames {
+			if !has[name] {
+				labels = append(labels, name)
+				has[name] = true
+				changed = true
+			}
+		}
+	}
+	update.LabelNames = labels
+
+	if keep.Due == nil || !keep.Due.IsRecurring {
+		earliest := keep
+		for _, duplicate := range duplicates {
+			if duplicate.Due != nil && (earliest.Due == nil || duplicate.DateTime().Before(earliest.DateTime())) {
+				earliest = duplicate
+			}
+		}
+		if earliest != keep {
+			due := *earliest.Due
+			update.NewDue = &due
+			changed = true
+		}
+	}
+
+	if changed {
+		commands = append(commands, todoist.NewCommand("item_update", update.UpdateParam()))
+	}
+	for _, duplicate := range duplicates {
+		for _, note := range store.ItemNotes(duplicate.ID) {
+			note.ItemID = keep.ID
+			commands = append(commands, todoist.NewCommand("note_add", note.AddParam()))
+		}
+	}
+	return commands
+}
+
+func Dedupe(c *cli.Context) error {
+	client := GetClient(c)
+
+	if c.Bool("merge") && c.Bool("delete") {
+		return &Error{Code: "invalid_argument", Message: "--merge and --delete can't be used together", Hint: "--merge closes the duplicates after merging them"}
+	}
+
+	items := FilterItems(client.Store, Filter(""))
+	if name := c.String("project"); name != "" {
+		projectID := client.Store.Projects.GetIDByName(name)
+		if projectID == "" {
+			return ProjectNotFound(name)
+		}
+		inProject := []*todoist.Item{}
+		for _, item := range items {
+			if item.ProjectID == projectID {
+				inProject = append(inProject, item)
+			}
+		}
+		items = inProject
+	}
+
+	clusters := DuplicateClusters(items)
+	if len(clusters) == 0 {
+		fmt.Println("There are no duplicate tasks.")
+		return nil
+	}
+
+	// Without --yes, the task to keep of each cluster is asked for. The
+	// others are removed in one batch at the end.
+	kept := map[string][]*todoist.Item{}
+	keeps := []string{}
+	ids := []string{}
+	for _, cluster := range clusters {
+		keep := 0
+		if !c.Bool("yes") {
+			options := []string{}
+			for _, item := range cluster {
+				option := fmt.Sprintf("keep %s %s", item.ID, todoist.GetContentTitle(item))
+				if project := client.Store.FindProject(item.ProjectID); project != nil {
+					option += " #" + project.Name
+				}
+				options = append(options, option)
+			}
+			options = append(options, "keep all of them")
+			choice, err := promptChoice(fmt.Sprintf("%d similar tasks:", len(cluster)), options)
+			if err != nil {
+				return err
+			}
+			if choice == len(cluster) {
+				continue
+			}
+			keep = choice
+		}
+		keeps = append(keeps, cluster[keep].ID)
+		for i, item := range cluster {
+			if i != keep {
+				ids = append(ids, item.ID)
+				kept[cluster[keep].ID] = append(kept[cluster[keep].ID], item)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	ctx := GetContext(c)
+	switch {
+	case c.Bool("delete"):
+		if err := confirmItems(client.Store, "Delete", ids, c.Bool("yes")); err != nil {
+			return err
+		}
+		if err := client.DeleteItem(ctx, ids); err != nil {
+			return err
+		}
+	case c.Bool("merge"):
+		if err := confirmItems(client.Store, "Merge and close", ids, c.Bool("yes")); err != nil {
+			return err
+		}
+		client.Buffer()
+		for _, id := range keeps {
+			commands := mergeCommands(client.Store, client.Store.FindItem(id), kept[id])
+			if len(commands) == 0 {
+				continue
+			}
+			if err := client.ExecCommands(ctx, commands); err != nil {
+				return err
+			}
+		}
+		if err := client.CloseItem(ctx, ids); err != nil {
+			return err
+		}
+		if err := client.Flush(ctx); err != nil {
+			return err
+		}
+	default:
+		if err := confirmItems(client.Store, "Close", ids, c.Bool("yes")); err != nil {
+			return err
+		}
+		if err := client.CloseItem(ctx, ids); err != nil {
+			return err
+		}
+	}
+	return Sync(c)
+}
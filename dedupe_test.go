@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDuplicateClusters(t *testing.T) {
+	store := testStore(t, `{"items": [
+		{"id": "1", "content": "Buy milk", "added_at": "2020-01-02T00:00:00Z"},
+		{"id": "2", "content": "Call the plumber about the sink"},
+		{"id": "3", "content": "buy milk!", "added_at": "2020-01-01T00:00:00Z"},
+		{"id": "4", "content": "Call the plumbr about the sink"},
+		{"id": "5", "content": "Buy silk"},
+		{"id": "6", "content": "[Buy milk](https://example.com)", "added_at": "2020-01-03T00:00:00Z"}
+	]}`)
+
+	ids := [][]string{}
+	for _, cluster := range DuplicateClusters(FilterItems(store, Filter(""))) {
+		group := []string{}
+		for _, item := range cluster {
+			group = append(group, item.ID)
+		}
+		ids = append(ids, group)
+	}
+	assert.Equal(t, [][]string{{"3", "1", "6"}, {"2", "4"}}, ids, "they should be equal")
+}
+
+func TestMergeCommands(t *testing.T) {
+	store := testStore(t, `{
+		"items": [
+			{"id": "1", "content": "Buy milk", "labels": ["errand"], "due": {"date": "2020-01-05"}},
+			{"id": "2", "content": "buy milk", "labels": ["shop", "errand"], "due": {"date": "2020-01-03"}},
+			{"id": "3", "content": "Buy milk!", "due": {"date": "2020-01-01", "is_recurring": true, "string": "every day"}}
+		],
+		"notes": [
+			{"id": "10", "item_id": "2", "content": "2 litres"}
+		]
+	}`)
+
+	commands := mergeCommands(store, store.FindItem("1"), []*todoist.Item{store.FindItem("2")})
+	assert.Equal(t, 2, len(commands), "they should be equal")
+	assert.Equal(t, "item_update", commands[0].Type, "they should be equal")
+	update := commands[0].Args.(map[string]interface{})
+	assert.Equal(t, []string{"errand", "shop"}, update["labels"], "they should be equal")
+	assert.Equal(t, "2020-01-03", update["due"].(*todoist.Due).Date, "they should be equal")
+	assert.Equal(t, "note_add", commands[1].Type, "they should be equal")
+	assert.Equal(t, "1", commands[1].Args.(map[string]interface{})["item_id"], "they should be equal")
+
+	// The recurring date of the task kept stays.
+	commands = mergeCommands(store, store.FindItem("3"), []*todoist.Item{store.FindItem("2")})
+	assert.Nil(t, commands[0].Args.(map[string]interface{})["due"], "they should be equal")
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/urfave/cli"
+)
+
+// CommandDefaults inserts the default flags configured for the invoked
+// command into args, so that they are parsed as if typed before the flags
+// on the command line. Flags given explicitly therefore still win.
+func CommandDefaults(app *cli.App, args []string) []string {
+	config := viper.New()
+	setConfigFile(config)
+	if err := config.ReadInConfig(); err != nil {
+		// The config is created and validated later by app.Before.
+		return args
+	}
+	return insertCommandDefaults(app, args, func(name string) []string {
+		return config.GetStringSlice("command_defaults." + name)
+	})
+}
+
+func insertCommandDefaults(app *cli.App, args []string, defaults func(name string) []string) []string {
+	globalFlags := flagValues(app.Flags)
+
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return args
+		}
+		if strings.HasPrefix(arg, "-") && arg != "-" {
+			name := strings.TrimLeft(arg, "-")
+			if !strings.Contains(name, "=") && globalFlags[name] {
+				i++
+			}
+			continue
+		}
+
+		command := app.Command(arg)
+		if command == nil {
+			return args
+		}
+		commandFlags := flagValues(command.Flags)
+
+		var global, local []string
+		values := defaults(command.Name)
+		for j := 0; j < len(values); j++ {
+			value := values[j]
+			name := strings.SplitN(strings.TrimLeft(value, "-"), "=", 2)[0]
+			takesValue, isGlobal := globalFlags[name]
+			if _, isLocal := commandFlags[name]; isLocal || !isGlobal || !strings.HasPrefix(value, "-") {
+				local = append(local, value)
+				continue
+			}
+			global = append(global, value)
+			if takesValue && !strings.Contains(value, "=") && j+1 < len(values) {
+				j++
+				global = append(global, values[j])
+			}
+		}
+
+		result := append([]string{args[0]}, global...)
+		result = append(result, args[1:i+1]...)
+		result = append(result, local...)
+		return append(result, args[i+1:]...)
+	}
+	return args
+}
+
+// flagValues maps every name of flags to whether the flag takes a value.
+func flagValues(flags []cli.Flag) map[string]bool {
+	values := map[string]bool{}
+	for _, flag := range flags {
+		takesValue := true
+		switch flag.(type) {
+		case cli.BoolFlag, cli.BoolTFlag:
+			takesValue = false
+		}
+		for _, name := range strings.Split(flag.GetName(), ",") {
+			values[strings.TrimSpace(name)] = takesValue
+		}
+	}
+	return values
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli"
+)
+
+func TestInsertCommandDefaults(t *testing.T) {
+	app := cli.NewApp()
+	app.Flags = []cli.Flag{
+		cli.BoolFlag{Name: "indent"},
+		cli.StringFlag{Name: "output"},
+	}
+	app.Commands = []cli.Command{
+		{
+			Name:    "list",
+			Aliases: []string{"l"},
+			Flags:   []cli.Flag{cli.StringFlag{Name: "filter, f"}},
+		},
+		{Name: "karma"},
+	}
+	defaults := func(name string) []string {
+		if name == "list" {
+			return []string{"--indent", "--output", "table", "-f", "p1"}
+		}
+		return nil
+	}
+
+	assert.Equal(t,
+		[]string{"todoist", "--indent", "--output", "table", "--output", "json", "l", "-f", "p1", "-f", "p2"},
+		insertCommandDefaults(app, []string{"todoist", "--output", "json", "l", "-f", "p2"}, defaults),
+		"they should be equal")
+	assert.Equal(t,
+		[]string{"todoist", "karma"},
+		insertCommandDefaults(app, []string{"todoist", "karma"}, defaults),
+		"they should be equal")
+	assert.Equal(t,
+		[]string{"todoist", "unknown"},
+		insertCommandDefaults(app, []string{"todoist", "unknown"}, defaults),
+		"they should be equal")
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli"
+)
+
+// Delegate assigns a task of a shared project to a collaborator.
+func Delegate(c *cli.Context) error {
+	client := GetClient(c)
+	store := client.Store
+
+	if !c.Args().Present() || (len(c.Args()) < 2 && !c.Bool("unassign")) {
+		return ArgumentRequired
+	}
+	id, err := ResolveItemID(client, c.Args().First())
+	if err != nil {
+		return err
+	}
+	item := store.FindItem(id)
+	if item == nil {
+		return IdNotFound
+	}
+
+	uid := ""
+	if !c.Bool("unassign") {
+		name := c.Args().Get(1)
+		if uid, err = resolveAssignee(store, name); err != nil {
+			return err
+		}
+		if uid != "" && !store.IsShared(item.ProjectID, uid) {
+			return &Error{
+				Code:    "project_not_shared",
+				Message: fmt.Sprintf("%s is not shared with %s", projectName(store, item.ProjectID), name),
+				Hint:    "share the project in the app first",
+			}
+		}
+	}
+
+	if err := client.AssignItem(GetContext(c), item.ID, uid); err != nil {
+		return err
+	}
+
+	return Sync(c)
+}
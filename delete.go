@@ -1,17 +1,15 @@
 package main
 
 import (
-	"context"
-
 	"github.com/urfave/cli"
 )
 
 func Delete(c *cli.Context) error {
 	client := GetClient(c)
 
-	item_ids := []int{}
+	item_ids := []string{}
 	for _, arg := range c.Args() {
-		item_id, err := client.CompleteItemIDByPrefix(arg)
+		item_id, err := ResolveItemID(client, arg)
 		if err != nil {
 			return err
 		}
@@ -19,11 +17,22 @@ func Delete(c *cli.Context) error {
 	}
 
 	if len(item_ids) == 0 {
-		return CommandFailed
+		return ArgumentRequired
+	}
+
+	if c.Bool("to-trash") {
+		if err := confirmItems(client.Store, "Trash", item_ids, c.Bool("yes")); err != nil {
+			return err
+		}
+		return TrashItems(c, item_ids)
+	}
+
+	if err := confirmItems(client.Store, "Delete", item_ids, c.Bool("yes")); err != nil {
+		return err
 	}
 
-	if err := client.DeleteItem(context.Background(), item_ids); err != nil {
-		return CommandFailed
+	if err := client.DeleteItem(GetContext(c), item_ids); err != nil {
+		return err
 	}
 
 	return Sync(c)
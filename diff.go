@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/urfave/cli"
+)
+
+// TaskChange is a difference of a task between two stores.
+type TaskChange struct {
+	Change  string
+	Item    *todoist.Item
+	Details string
+}
+
+func dueString(item *todoist.Item) string {
+	if item.Due == nil {
+		return "no date"
+	}
+	return item.Due.Date
+}
+
+func projectName(store *todoist.Store, id string) string {
+	if project := store.FindProject(id); project != nil {
+		return "#" + project.Name
+	}
+	return "Unknown"
+}
+
+// diffItem returns how item of newer differs from old of older.
+func diffItem(older *todoist.Store, newer *todoist.Store, old *todoist.Item, item *todoist.Item) []TaskChange {
+	if item.Checked != old.Checked {
+		if item.Checked {
+			return []TaskChange{{Change: "completed", Item: item}}
+		}
+		return []TaskChange{{Change: "reopened", Item: item}}
+	}
+
+	changes := []TaskChange{}
+	if dueString(old) != dueString(item) {
+		changes = append(changes, TaskChange{Change: "rescheduled", Item: item, Details: dueString(old) + " -> " + dueString(item)})
+	}
+	details := []string{}
+	if old.Content != item.Content {
+		details = append(details, fmt.Sprintf("content was %q", old.Content))
+	}
+	if old.Priority != item.Priority {
+		details = append(details, fmt.Sprintf("p%d -> p%d", priorityMapping[old.Priority], priorityMapping[item.Priority]))
+	}
+	if old.ProjectID != item.ProjectID {
+		details = append(details, projectName(older, old.ProjectID)+" -> "+projectName(newer, item.ProjectID))
+	}
+	if strings.Join(old.LabelNames, ",") != strings.Join(item.LabelNames, ",") {
+		details = append(details, old.LabelsString(older)+" -> "+item.LabelsString(newer))
+	}
+	if len(details) > 0 {
+		changes = append(changes, TaskChange{Change: "changed", Item: item, Details: strings.Join(details, ", ")})
+	}
+	return changes
+}
+
+// DiffStores returns how the tasks of newer differ from those of older, in
+// the order of newer followed by the tasks which are gone.
+func DiffStores(older *todoist.Store, newer *todoist.Store) []TaskChange {
+	changes := []TaskChange{}
+	for i := range newer.Items {
+		item := &newer.Items[i]
+		old := older.FindItem(item.ID)
+		if old == nil {
+			if !item.Checked {
+				changes = append(changes, TaskChange{Change: "added", Item: item})
+			}
+			continue
+		}
+		changes = append(changes, diffItem(older, newer, old, item)...)
+	}
+	for i := range older.Items {
+		item := &older.Items[i]
+		// Full syncs leave out completed tasks, so a missing task may have
+		// been completed as well as deleted.
+		if newer.FindItem(item.ID) == nil && !item.Checked {
+			changes = append(changes, TaskChange{Change: "removed", Item: item, Details: "completed or deleted"})
+		}
+	}
+	return changes
+}
+
+func Diff(c *cli.Context) error {
+	client := GetClient(c)
+
+	remote, err := client.Fetch(GetContext(c))
+	if err != nil {
+		return err
+	}
+
+	changes := DiffStores(client.Store, remote)
+
+	if len(changes) > 0 {
+		writer.WriteHeader([]string{"Change", "ID", "Content", "Details"})
+		for _, change := range changes {
+			writer.Write([]string{change.Change, IdFormat(change.Item), ContentFormat(change.Item), change.Details})
+		}
+		writer.Flush()
+	}
+
+	if !c.Bool("accept") {
+		return nil
+	}
+	client.Store = remote
+	return WriteCache(default_cache_path, client.Store)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sachaos/todoist/lib"
+)
+
+func testStore(t *testing.T, data string) *todoist.Store {
+	var store todoist.Store
+	assert.NoError(t, json.Unmarshal([]byte(data), &store))
+	store.ConstructItemTree()
+	return &store
+}
+
+func TestDiffStores(t *testing.T) {
+	older := testStore(t, `{"items": [
+		{"id": "1", "content": "same", "priority": 1},
+		{"id": "2", "content": "moved", "priority": 1, "due": {"date": "2020-01-01"}},
+		{"id": "3", "content": "renamed", "priority": 1},
+		{"id": "4", "content": "gone", "priority": 1}
+	]}`)
+	newer := testStore(t, `{"items": [
+		{"id": "1", "content": "same", "priority": 1},
+		{"id": "2", "content": "moved", "priority": 1, "due": {"date": "2020-01-02"}},
+		{"id": "3", "content": "new name", "priority": 4},
+		{"id": "5", "content": "new", "priority": 1}
+	]}`)
+
+	changes := DiffStores(older, newer)
+	summary := []string{}
+	for _, change := range changes {
+		summary = append(summary, change.Change+" "+change.Item.ID+" "+change.Details)
+	}
+	assert.Equal(t, []string{
+		"rescheduled 2 2020-01-01 -> 2020-01-02",
+		`changed 3 content was "renamed", p4 -> p1`,
+		"added 5 ",
+		"removed 4 completed or deleted",
+	}, summary, "they should be equal")
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"github.com/sachaos/todoist/lib"
+	"github.com/urfave/cli"
+)
+
+func Duplicate(c *cli.Context) error {
+	client := GetClient(c)
+
+	if !c.Args().Present() {
+		return ArgumentRequired
+	}
+
+	id, err := ResolveItemID(client, c.Args().First())
+	if err != nil {
+		return err
+	}
+	item := client.Store.FindItem(id)
+	if item == nil {
+		return IdNotFound
+	}
+
+	opts := todoist.CopyOptions{}
+	if name := c.String("project"); name != "" {
+		projectID := client.Store.Projects.GetIDByName(name)
+		if projectID == "" {
+			return ProjectNotFound(name)
+		}
+		opts.ProjectID = projectID
+	} else if item.ParentID != nil {
+		opts.ParentID = *item.ParentID
+	}
+
+	var commands todoist.Commands
+	for i := 0; i < c.Int("count"); i++ {
+		commands = append(commands, client.Store.CopyItemCommands(item, opts)...)
+	}
+	if len(commands) == 0 {
+		return nil
+	}
+
+	if err := client.ExecCommands(GetContext(c), commands); err != nil {
+		return err
+	}
+
+	return Sync(c)
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sachaos/todoist/lib/todoisttest"
+	"github.com/stretchr/testify/assert"
+)
+
+// runTodoist returns a function running todoist with args against server,
+// keeping its files in a temporary directory, which returns the output.
+func runTodoist(t *testing.T, server *todoisttest.Server) func(args ...string) (string, error) {
+	dir, err := ioutil.TempDir("", "todoist")
+	assert.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	t.Setenv("TODOIST_CONFIG", filepath.Join(dir, "config.json"))
+	t.Setenv("TODOIST_TOKEN", todoisttest.Token)
+	t.Setenv("TODOIST_API_URL", server.APIURL())
+	paths := []*string{&default_cache_path, &contextPath, &schedulePath}
+	for _, path := range paths {
+		saved := *path
+		*path = filepath.Join(dir, filepath.Base(saved))
+		t.Cleanup(func() { *path = saved })
+	}
+
+	return func(args ...string) (string, error) {
+		var out bytes.Buffer
+		app := newApp()
+		app.Writer = &out
+		args = append([]string{"todoist", "--color=never", "--cache-path", default_cache_path}, args...)
+		err := app.Run(CommandDefaults(app, args))
+		return out.String(), err
+	}
+}
+
+func TestEndToEnd(t *testing.T) {
+	server := todoisttest.NewServer(t, `{
+		"user": {"id": "1", "inbox_project_id": "1"},
+		"projects": [{"id": "1", "name": "Inbox", "inbox_project": true}, {"id": "2", "name": "Work"}],
+		"items": [{"id": "10", "project_id": "2", "content": "Write the report", "priority": 4}]
+	}`)
+	run := runTodoist(t, server)
+
+	_, err := run("sync")
+	assert.NoError(t, err)
+	out, err := run("list")
+	assert.NoError(t, err)
+	assert.Contains(t, out, "Write the report")
+
+	_, err = run("add", "--project-name", "Work", "Buy milk")
+	assert.NoError(t, err)
+	out, err = run("list", "--filter", "#Work")
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	assert.Equal(t, 2, len(lines), "they should be equal")
+	assert.Contains(t, out, "Buy milk")
+
+	_, err = run("close", "10")
+	assert.NoError(t, err)
+	out, err = run("list")
+	assert.NoError(t, err)
+	assert.NotContains(t, out, "Write the report")
+	assert.Contains(t, out, "Buy milk")
+
+	// The changes are on the server, not only in the cache.
+	client := server.NewClient()
+	assert.NoError(t, client.Sync(context.Background()))
+	assert.True(t, client.Store.FindItem("10").Checked)
+	assert.Equal(t, 2, len(client.Store.Items), "they should be equal")
+
+	_, err = run("close", "99")
+	assert.Error(t, err)
+}
+
+func TestSyncAfterChange(t *testing.T) {
+	server := todoisttest.NewServer(t, `{
+		"user": {"id": "1", "inbox_project_id": "1"},
+		"projects": [{"id": "1", "name": "Inbox", "inbox_project": true}],
+		"items": [{"id": "10", "project_id": "1", "content": "Write the report", "priority": 1}]
+	}`)
+	run := runTodoist(t, server)
+	_, err := run("sync")
+	assert.NoError(t, err)
+
+	tokens := []string{}
+	failing := false
+	handler := server.Config.Handler
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("commands") == "" {
+			tokens = append(tokens, r.FormValue("sync_token"))
+			if failing {
+				http.Error(w, `{"error": "unavailable"}`, http.StatusServiceUnavailable)
+				return
+			}
+		}
+		handler.ServeHTTP(w, r)
+	})
+
+	// Only the changes since the last sync are asked for.
+	_, err = run("close", "10")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sandbox"}, tokens, "they should be equal")
+
+	// The change is made, but the cache may miss others.
+	failing = true
+	_, err = run("modify", "--force", "--content", "Write the summary", "10")
+	assert.Equal(t, "sync_failed", AsError(err).Code, "they should be equal")
+	out, err := run("--read-only", "show", "10")
+	assert.NoError(t, err)
+	assert.Contains(t, out, "Write the summary")
+}
+
+func TestStatsFlowUnavailable(t *testing.T) {
+	server := todoisttest.NewServer(t, `{
+		"user": {"id": "1", "inbox_project_id": "1"},
+		"projects": [{"id": "1", "name": "Inbox", "inbox_project": true}],
+		"items": [{"id": "10", "project_id": "1", "content": "Write the report", "added_at": "2020-01-01T00:00:00Z"}]
+	}`)
+	run := runTodoist(t, server)
+	_, err := run("sync")
+	assert.NoError(t, err)
+
+	handler := server.Config.Handler
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/activities") {
+			http.Error(w, `{"error": "premium only"}`, http.StatusForbidden)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+
+	out, err := run("--output", "csv", "stats", "flow", "--since", "1w")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, out)
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Split(line, ",")
+		assert.Equal(t, []string{"-", "0", "-", "-"}, fields[1:], "they should be equal")
+	}
+}
+
+func TestLabelsStatsCompletedUnavailable(t *testing.T) {
+	server := todoisttest.NewServer(t, `{
+		"user": {"id": "1", "inbox_project_id": "1"},
+		"projects": [{"id": "1", "name": "Inbox", "inbox_project": true}],
+		"labels": [{"id": "1", "name": "office"}],
+		"items": [{"id": "10", "project_id": "1", "content": "Write the report", "labels": ["office"]}]
+	}`)
+	run := runTodoist(t, server)
+	_, err := run("sync")
+	assert.NoError(t, err)
+
+	status := http.StatusForbidden
+	handler := server.Config.Handler
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/tasks/completed") {
+			http.Error(w, `{"error": "unavailable"}`, status)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+
+	// Without premium, the completed tasks are unknown.
+	out, err := run("--output", "csv", "labels", "stats")
+	assert.NoError(t, err)
+	assert.Equal(t, "@office,1,0,-\n", out, "they should be equal")
+
+	status = http.StatusInternalServerError
+	_, err = run("--output", "csv", "labels", "stats")
+	assert.Equal(t, http.StatusInternalServerError, AsError(err).HTTPStatus, "they should be equal")
+}
+
+func TestSyncDaemonInterval(t *testing.T) {
+	server := todoisttest.NewServer(t, `{"user": {"id": "1", "inbox_project_id": "1"}}`)
+	run := runTodoist(t, server)
+
+	for _, interval := range []string{"0", "-1m"} {
+		_, err := run("sync", "--daemon", "--interval", interval)
+		assert.Equal(t, "invalid_argument", AsError(err).Code, "they should be equal")
+	}
+}
+
+func TestServeInterval(t *testing.T) {
+	server := todoisttest.NewServer(t, `{"user": {"id": "1", "inbox_project_id": "1"}}`)
+	run := runTodoist(t, server)
+
+	for _, command := range []string{"serve", "serve-feed"} {
+		_, err := run(command, "--port", "0", "--interval", "0")
+		assert.Equal(t, "invalid_argument", AsError(err).Code, command)
+	}
+}
+
+func TestColorFlag(t *testing.T) {
+	server := todoisttest.NewServer(t, `{
+		"user": {"id": "1", "inbox_project_id": "1"},
+		"projects": [{"id": "1", "name": "Inbox", "inbox_project": true}],
+		"items": [{"id": "10", "project_id": "1", "content": "Write the report", "priority": 4}]
+	}`)
+	run := runTodoist(t, server)
+	_, err := run("sync")
+	assert.NoError(t, err)
+
+	// --color alone still means always, and doesn't take the command.
+	out, err := run("--color", "list")
+	assert.NoError(t, err)
+	assert.Contains(t, out, "\x1b[")
+	assert.Contains(t, out, "Write the report")
+
+	out, err = run("--color=never", "list")
+	assert.NoError(t, err)
+	assert.NotContains(t, out, "\x1b[")
+
+	_, err = run("--color=sometimes", "list")
+	assert.Error(t, err)
+}
+
+func TestTrashPerAccount(t *testing.T) {
+	server := todoisttest.NewServer(t, `{
+		"user": {"id": "1", "inbox_project_id": "1"},
+		"projects": [{"id": "1", "name": "Inbox", "inbox_project": true}, {"id": "2", "name": "Work"}],
+		"items": [{"id": "10", "project_id": "2", "content": "Write the report"}]
+	}`)
+	run := runTodoist(t, server)
+	_, err := run("sync")
+	assert.NoError(t, err)
+	_, err = run("delete", "--to-trash", "--yes", "10")
+	assert.NoError(t, err)
+
+	var trash Trash
+	assert.NoError(t, readJSONFile(trashPath(default_cache_path), &trash))
+	assert.Equal(t, "2", trash["10"].ProjectID, "they should be equal")
+
+	// Another cache, as of another account, has a trash of its own, so the
+	// task isn't known to come from Work.
+	other := filepath.Join(filepath.Dir(default_cache_path), "other.json")
+	_, err = run("--cache-path", other, "sync")
+	assert.NoError(t, err)
+	_, err = run("--cache-path", other, "restore", "10")
+	assert.NoError(t, err)
+	out, err := run("--cache-path", other, "list", "--filter", "#Inbox")
+	assert.NoError(t, err)
+	assert.Contains(t, out, "Write the report")
+}
+
+func TestShowWorkspaceOutput(t *testing.T) {
+	server := todoisttest.NewServer(t, `{
+		"user": {"id": "1", "inbox_project_id": "1"},
+		"projects": [{"id": "1", "name": "Inbox", "inbox_project": true}],
+		"workspaces": [{"id": "51", "name": "Acme"}]
+	}`)
+	run := runTodoist(t, server)
+	_, err := run("sync")
+	assert.NoError(t, err)
+
+	out, err := run("--output", "json", "workspace")
+	assert.NoError(t, err)
+	assert.Equal(t, "[]\n", out, "they should be equal")
+
+	_, err = run("workspace", "use", "Acme")
+	assert.NoError(t, err)
+	out, err = run("--output", "json", "workspace")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"ID": "51", "Name": "Acme"}]`, out)
+}
+
+func TestListWorkspacesProjects(t *testing.T) {
+	server := todoisttest.NewServer(t, `{
+		"user": {"id": "1", "inbox_project_id": "1"},
+		"projects": [
+			{"id": "1", "name": "Inbox", "inbox_project": true},
+			{"id": "2", "name": "Roadmap", "workspace_id": "51"},
+			{"id": "3", "name": "Gone", "workspace_id": "51", "is_deleted": true}
+		],
+		"workspaces": [{"id": "51", "name": "Acme"}]
+	}`)
+	run := runTodoist(t, server)
+	_, err := run("sync")
+	assert.NoError(t, err)
+
+	out, err := run("--output", "csv", "workspace", "list")
+	assert.NoError(t, err)
+	assert.Equal(t, "51,Acme,1,0,\n", out, "they should be equal")
+}
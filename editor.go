@@ -0,0 +1,52 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+func editorCommand() string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if editor := os.Getenv(name); editor != "" {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// editText opens the user's editor on initial and returns the saved text.
+func editText(initial string) (string, error) {
+	f, err := ioutil.TempFile("", "todoist-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(initial); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	fields := strings.Fields(editorCommand())
+	cmd := exec.Command(fields[0], append(fields[1:], f.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+
+	buf, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
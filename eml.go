@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// maxCommentLength is the most characters a comment can have.
+const maxCommentLength = 15000
+
+var htmlTagRegex = regexp.MustCompile(`(?s)<(?:style|script)[^>]*>.*?</(?:style|script)>|<[^>]*>`)
+
+// Email is what a task is made of from an email: the subject as its
+// content, the sender and date as its description, and the text as a
+// comment.
+type Email struct {
+	Subject     string
+	Description string
+	Body        string
+}
+
+// decodeCharset converts text in charset to UTF-8.
+func decodeCharset(text []byte, charset string) ([]byte, error) {
+	if charset == "" || strings.EqualFold(charset, "utf-8") || strings.EqualFold(charset, "us-ascii") {
+		return text, nil
+	}
+	encoding, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, err
+	}
+	return encoding.NewDecoder().Bytes(text)
+}
+
+var wordDecoder = &mime.WordDecoder{
+	CharsetReader: func(charset string, input io.Reader) (io.Reader, error) {
+		buf, err := ioutil.ReadAll(input)
+		if err != nil {
+			return nil, err
+		}
+		decoded, err := decodeCharset(buf, charset)
+		return bytes.NewReader(decoded), err
+	},
+}
+
+// emailText returns the text of a part with header and body, the plain
+// text alternative of a multipart one, or else the HTML one without its
+// tags. It returns "" and false when there is none.
+func emailText(header map[string][]string, body io.Reader) (string, bool, error) {
+	get := func(key string) string {
+		if values := header[key]; len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+	mediaType, params, err := mime.ParseMediaType(get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		html := ""
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", false, err
+			}
+			if strings.HasPrefix(part.Header.Get("Content-Disposition"), "attachment") {
+				continue
+			}
+			partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+			text, ok, err := emailText(part.Header, part)
+			if err != nil {
+				return "", false, err
+			}
+			if !ok {
+				continue
+			}
+			if partType != "text/html" {
+				return text, true, nil
+			}
+			if html == "" {
+				html = text
+			}
+		}
+		return html, html != "", nil
+	}
+	if mediaType != "text/plain" && mediaType != "text/html" {
+		return "", false, nil
+	}
+
+	switch strings.ToLower(get("Content-Transfer-Encoding")) {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+	buf, err := ioutil.ReadAll(body)
+	if err != nil {
+		return "", false, err
+	}
+	if buf, err = decodeCharset(buf, params["charset"]); err != nil {
+		return "", false, err
+	}
+	text := string(buf)
+	if mediaType == "text/html" {
+		text = html.UnescapeString(htmlTagRegex.ReplaceAllString(text, ""))
+	}
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	return text, text != "", nil
+}
+
+// parseEmail reads an email in the format of .eml files and maildirs.
+func parseEmail(r io.Reader) (Email, error) {
+	message, err := mail.ReadMessage(r)
+	if err != nil {
+		return Email{}, err
+	}
+
+	subject, err := wordDecoder.DecodeHeader(message.Header.Get("Subject"))
+	if err != nil {
+		subject = message.Header.Get("Subject")
+	}
+	email := Email{Subject: strings.TrimSpace(subject)}
+	if email.Subject == "" {
+		email.Subject = "(no subject)"
+	}
+
+	lines := []string{}
+	if from, err := wordDecoder.DecodeHeader(message.Header.Get("From")); err == nil && from != "" {
+		lines = append(lines, "From: "+from)
+	}
+	if date := message.Header.Get("Date"); date != "" {
+		lines = append(lines, "Date: "+date)
+	}
+	email.Description = strings.Join(lines, "\n")
+
+	body, _, err := emailText(message.Header, message.Body)
+	if err != nil {
+		return Email{}, err
+	}
+	if utf8.RuneCountInString(body) > maxCommentLength {
+		body = string([]rune(body)[:maxCommentLength-1]) + "…"
+	}
+	email.Body = body
+	return email, nil
+}
+
+// readEmailFile parses the email in path, or on stdin for -.
+func readEmailFile(path string) (Email, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return Email{}, err
+		}
+		defer f.Close()
+		r = f
+	}
+	email, err := parseEmail(r)
+	if err != nil {
+		return Email{}, &Error{Code: "invalid_argument", Message: fmt.Sprintf("cannot read the email %s: %s", path, err), Hint: "give a file with one message, as saved by mail clients as .eml"}
+	}
+	return email, nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEmail(t *testing.T) {
+	email, err := parseEmail(strings.NewReader("From: =?UTF-8?Q?Ren=C3=A9e?= <renee@example.com>\r\n" +
+		"Date: Thu, 15 Oct 2020 09:12:00 +0200\r\n" +
+		"Subject: =?ISO-8859-1?Q?R=E9union_budget?=\r\n" +
+		"Content-Type: multipart/mixed; boundary=outer\r\n\r\n" +
+		"--outer\r\n" +
+		"Content-Type: multipart/alternative; boundary=inner\r\n\r\n" +
+		"--inner\r\n" +
+		"Content-Type: text/html; charset=utf-8\r\n\r\n" +
+		"<p>Hello</p>\r\n" +
+		"--inner\r\n" +
+		"Content-Type: text/plain; charset=iso-8859-1\r\n" +
+		"Content-Transfer-Encoding: quoted-printable\r\n\r\n" +
+		"Numbers before the r=E9union?\r\n" +
+		"--inner--\r\n" +
+		"--outer\r\n" +
+		"Content-Type: text/plain\r\n" +
+		"Content-Disposition: attachment; filename=notes.txt\r\n\r\n" +
+		"attached\r\n" +
+		"--outer--\r\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Réunion budget", email.Subject, "they should be equal")
+	assert.Equal(t, "From: Renée <renee@example.com>\nDate: Thu, 15 Oct 2020 09:12:00 +0200", email.Description, "they should be equal")
+	assert.Equal(t, "Numbers before the réunion?", email.Body, "they should be equal")
+
+	email, err = parseEmail(strings.NewReader("Content-Type: text/html\r\n" +
+		"Content-Transfer-Encoding: base64\r\n\r\n" +
+		"PHN0eWxlPnB7fTwvc3R5bGU+PHA+RmlzaCAmYW1wOyBjaGlwczwvcD4=\r\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "(no subject)", email.Subject, "they should be equal")
+	assert.Equal(t, "", email.Description, "they should be equal")
+	assert.Equal(t, "Fish & chips", email.Body, "they should be equal")
+
+	email, err = parseEmail(strings.NewReader("Subject: long\r\n\r\n" + strings.Repeat("a", maxCommentLength+10)))
+	assert.NoError(t, err)
+	assert.Equal(t, maxCommentLength, utf8.RuneCountInString(email.Body), "they should be equal")
+
+	_, err = parseEmail(strings.NewReader("not an email"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/sachaos/todoist/lib"
+)
+
+// Error is an error which tells the user how to resolve it.
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+	// HTTPStatus is the status of the response of the API which failed, if
+	// any.
+	HTTPStatus int `json:"http_status,omitempty"`
+	// Retryable reports whether running the command again may succeed, like
+	// after a timeout or with too many requests.
+	Retryable bool `json:"retryable"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+var (
+	CommandFailed        = &Error{Code: "command_failed", Message: "command failed"}
+	IdNotFound           = &Error{Code: "id_not_found", Message: "specified id not found", Hint: "run `todoist sync` or check `todoist list`"}
+	ArgumentRequired     = &Error{Code: "argument_required", Message: "missing required argument", Hint: "see `todoist help <command>`"}
+	NotInteractive       = &Error{Code: "not_interactive", Message: "cannot prompt, stdin is not a terminal"}
+	ConfirmationRequired = &Error{Code: "confirmation_required", Message: "confirmation required, stdin is not a terminal", Hint: "pass --yes to skip the confirmation"}
+	Aborted              = &Error{Code: "aborted", Message: "aborted"}
+)
+
+func InvalidID(id string) *Error {
+	return &Error{Code: "invalid_id", Message: fmt.Sprintf("invalid task id %q", id), Hint: "task ids are letters and digits, see `todoist list`"}
+}
+
+func NoMatchingTask(query string) *Error {
+	return &Error{Code: "no_matching_task", Message: fmt.Sprintf("no task matches %q", query), Hint: "run `todoist sync` or check `todoist list`"}
+}
+
+func AmbiguousTask(query string, count int) *Error {
+	return &Error{Code: "ambiguous_task", Message: fmt.Sprintf("%d tasks match %q", count, query), Hint: "use a more specific text or the task id"}
+}
+
+func RecurrenceWouldBeLost(recurrence string) *Error {
+	return &Error{Code: "recurrence_lost", Message: fmt.Sprintf("task is recurring (%s), the new date would remove the recurrence", recurrence), Hint: "give a date like 2006-01-02 to reschedule it, a new \"every ...\" date, or pass --remove-recurrence"}
+}
+
+func InvalidPriority(priority string) *Error {
+	return &Error{Code: "invalid_priority", Message: fmt.Sprintf("invalid priority %q", priority), Hint: "use p1 (highest) to p4 (lowest)"}
+}
+
+func InvalidColor(name string) *Error {
+	return &Error{Code: "invalid_color", Message: fmt.Sprintf("unknown color %q", name), Hint: "use one of " + strings.Join(AppColorNames(), ", ")}
+}
+
+func InvalidFilter(problems int) *Error {
+	return &Error{Code: "invalid_filter", Message: fmt.Sprintf("the filter has %d problem(s)", problems), Hint: "see https://todoist.com/help/articles/introduction-to-filters-V98wIH"}
+}
+
+func ProjectNotFound(name string) *Error {
+	return &Error{Code: "project_not_found", Message: fmt.Sprintf("project %q not found", name), Hint: "run `todoist sync` or check `todoist projects`"}
+}
+
+func LabelNotFound(name string) *Error {
+	return &Error{Code: "label_not_found", Message: fmt.Sprintf("label %q not found", name), Hint: "run `todoist sync` or check `todoist labels`"}
+}
+
+func StaleTask(id string, change string) *Error {
+	return &Error{Code: "stale_task", Message: fmt.Sprintf("task %s was changed on Todoist since the last sync (%s)", id, change), Hint: "run `todoist sync` and check the task, or pass --force to overwrite it"}
+}
+
+func CollaboratorNotFound(name string) *Error {
+	return &Error{Code: "collaborator_not_found", Message: fmt.Sprintf("no collaborator %q", name), Hint: "use me, unassigned, or the name or email of someone sharing a project with you"}
+}
+
+func CacheError(err error) *Error {
+	return &Error{Code: "cache_error", Message: fmt.Sprintf("cache: %s", err), Hint: "run `todoist sync` to rebuild the cache"}
+}
+
+func CacheMigrationFailed(err error) *Error {
+	return &Error{Code: "cache_migration_failed", Message: fmt.Sprintf("could not rebuild the cache: %s", err), Hint: "run `todoist sync` once you are online"}
+}
+
+// SyncFailed is the error of the sync after a change, which was made all
+// the same, so the command must not be run again.
+func SyncFailed(err error) *Error {
+	return &Error{Code: "sync_failed", Message: fmt.Sprintf("the change was made, but the sync after it failed: %s", err), Hint: "run `todoist sync` to get the changes made elsewhere"}
+}
+
+// AsError converts any error into an *Error, deriving hints for API errors.
+func AsError(err error) *Error {
+	if err == todoist.ReadOnly {
+		return &Error{Code: "read_only", Message: err.Error(), Hint: "drop --read-only or set read_only to false in the config"}
+	}
+	if urlErr, ok := err.(*url.Error); ok {
+		switch {
+		case urlErr.Err == context.Canceled:
+			return &Error{Code: "interrupted", Message: "interrupted, the cache is unchanged"}
+		case urlErr.Timeout() || urlErr.Err == context.DeadlineExceeded:
+			return &Error{Code: "timeout", Message: urlErr.Error(), Hint: "retry, or give a longer --timeout", Retryable: true}
+		default:
+			return &Error{Code: "network_error", Message: urlErr.Error(), Hint: "check the connection and retry", Retryable: true}
+		}
+	}
+	switch err := err.(type) {
+	case *Error:
+		return err
+	case *todoist.APIError:
+		e := &Error{Code: err.Tag, Message: err.Error(), HTTPStatus: err.StatusCode}
+		if e.Code == "" {
+			e.Code = "api_error"
+		}
+		switch {
+		case err.StatusCode == http.StatusUnauthorized || err.StatusCode == http.StatusForbidden:
+			e.Hint = "check the token in your config file (" + configName + "." + configType + ")"
+		case err.StatusCode == http.StatusNotFound:
+			e.Hint = "run `todoist sync` to refresh the cache"
+		case err.StatusCode == http.StatusTooManyRequests:
+			e.Hint = "too many requests, wait a minute and retry (see `todoist api-status`)"
+			e.Retryable = true
+		case err.StatusCode >= http.StatusInternalServerError:
+			e.Hint = "Todoist is having trouble, retry later"
+			e.Retryable = true
+		}
+		return e
+	default:
+		return &Error{Code: "error", Message: err.Error()}
+	}
+}
+
+// PrintError writes err to w, as JSON when JSON output is selected.
+func PrintError(w io.Writer, err error) {
+	e := AsError(err)
+	if outputFormat == "json" {
+		json.NewEncoder(w).Encode(map[string]*Error{"error": e})
+		return
+	}
+	fmt.Fprintln(w, "Error:", e.Message)
+	if e.Hint != "" {
+		fmt.Fprintln(w, "Hint:", e.Hint)
+	}
+}
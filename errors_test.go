@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sachaos/todoist/lib"
+)
+
+func TestAsError(t *testing.T) {
+	e := AsError(&todoist.APIError{Prefix: "bad request", StatusCode: http.StatusForbidden, Status: "403 Forbidden", Tag: "AUTH_INVALID_TOKEN", Message: "Invalid token"})
+	assert.Equal(t, "AUTH_INVALID_TOKEN", e.Code, "they should be equal")
+	assert.Equal(t, "bad request: 403 Forbidden: Invalid token", e.Message, "they should be equal")
+	assert.NotEmpty(t, e.Hint)
+
+	assert.Equal(t, IdNotFound, AsError(IdNotFound), "they should be equal")
+	assert.Equal(t, "error", AsError(errors.New("boom")).Code, "they should be equal")
+	assert.Equal(t, "read_only", AsError(todoist.ReadOnly).Code, "they should be equal")
+	assert.Equal(t, "interrupted", AsError(&url.Error{Op: "Post", URL: "https://todoist.com/API/v8/sync", Err: context.Canceled}).Code, "they should be equal")
+	assert.Equal(t, "timeout", AsError(&url.Error{Op: "Post", URL: "https://todoist.com/API/v8/sync", Err: context.DeadlineExceeded}).Code, "they should be equal")
+
+	e = AsError(&url.Error{Op: "Post", URL: "https://todoist.com/API/v8/sync", Err: errors.New("connection refused")})
+	assert.Equal(t, "network_error", e.Code, "they should be equal")
+	assert.True(t, e.Retryable)
+}
+
+func TestAsErrorStatus(t *testing.T) {
+	for _, tc := range []struct {
+		status    int
+		retryable bool
+	}{
+		{http.StatusUnauthorized, false},
+		{http.StatusNotFound, false},
+		{http.StatusTooManyRequests, true},
+		{http.StatusServiceUnavailable, true},
+	} {
+		e := AsError(&todoist.APIError{Prefix: "bad request", StatusCode: tc.status, Status: http.StatusText(tc.status)})
+		assert.Equal(t, tc.status, e.HTTPStatus, "they should be equal")
+		assert.Equal(t, tc.retryable, e.Retryable, http.StatusText(tc.status))
+	}
+}
+
+func TestPrintErrorJSON(t *testing.T) {
+	defer func(format string) { outputFormat = format }(outputFormat)
+	outputFormat = "json"
+
+	var b bytes.Buffer
+	PrintError(&b, ProjectNotFound("Work"))
+	assert.Equal(t, `{"error":{"code":"project_not_found","message":"project \"Work\" not found","hint":"run `+"`todoist sync`"+` or check `+"`todoist projects`"+`","retryable":false}}`+"\n", b.String(), "they should be equal")
+}
+
+func TestPrintErrorJSONStatus(t *testing.T) {
+	defer func(format string) { outputFormat = format }(outputFormat)
+	outputFormat = "json"
+
+	var b bytes.Buffer
+	PrintError(&b, &todoist.APIError{Prefix: "bad request", StatusCode: http.StatusTooManyRequests, Status: "429 Too Many Requests"})
+	assert.Contains(t, b.String(), `"http_status":429,"retryable":true`)
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/urfave/cli"
+)
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #202020; }
+h1 { font-size: 1.4em; }
+h2 { font-size: 1.1em; margin-top: 2em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: 4px 8px; border-bottom: 1px solid #ddd; }
+th { cursor: pointer; background: #f5f5f5; }
+tr.overdue td { color: #d1453b; }
+.p1 { color: #d1453b; font-weight: bold; }
+.p2 { color: #eb8909; font-weight: bold; }
+.p3 { color: #246fe0; font-weight: bold; }
+footer { margin-top: 2em; color: #808080; font-size: 0.8em; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{range .Groups}}
+<h2>{{.Project}}</h2>
+<table class="sortable">
+<thead><tr><th>Priority</th><th>Due</th><th>Labels</th><th>Content</th></tr></thead>
+<tbody>
+{{range .Tasks}}<tr{{if .Overdue}} class="overdue"{{end}}>
+<td class="{{.Priority}}">{{.Priority}}</td>
+<td data-sort="{{.DueSort}}">{{.Due}}</td>
+<td>{{.Labels}}</td>
+<td>{{if .URL}}<a href="{{.URL}}">{{.Content}}</a>{{else}}{{.Content}}{{end}}</td>
+</tr>
+{{end}}</tbody>
+</table>
+{{end}}
+<footer>Generated by todoist CLI at {{.Generated}}</footer>
+<script>
+document.querySelectorAll("table.sortable th").forEach(function (th, column) {
+  th.addEventListener("click", function () {
+    var tbody = th.closest("table").querySelector("tbody");
+    var rows = Array.prototype.slice.call(tbody.rows);
+    var asc = th.dataset.order !== "asc";
+    th.dataset.order = asc ? "asc" : "desc";
+    rows.sort(function (a, b) {
+      var x = a.cells[column].dataset.sort || a.cells[column].textContent;
+      var y = b.cells[column].dataset.sort || b.cells[column].textContent;
+      return (x < y ? -1 : x > y ? 1 : 0) * (asc ? 1 : -1);
+    });
+    rows.forEach(function (row) { tbody.appendChild(row); });
+  });
+});
+</script>
+</body>
+</html>
+`))
+
+type htmlReportTask struct {
+	Priority string
+	Due      string
+	DueSort  string
+	Overdue  bool
+	Labels   string
+	Content  string
+	URL      string
+}
+
+type htmlReportGroup struct {
+	Project string
+	Tasks   []htmlReportTask
+}
+
+type htmlReport struct {
+	Title     string
+	Generated string
+	Groups    []*htmlReportGroup
+}
+
+func ExportHTML(c *cli.Context) error {
+	client := GetClient(c)
+	filter, err := ApplyContext(c.String("filter"))
+	if err != nil {
+		return err
+	}
+	ex := Filter(filter)
+
+	report := htmlReport{
+		Title:     c.String("title"),
+		Generated: time.Now().Format(ShortDateTimeFormat),
+	}
+	groups := map[string]*htmlReportGroup{}
+
+	for _, item := range FilterItems(client.Store, ex) {
+		group, ok := groups[item.ProjectID]
+		if !ok {
+			name := "Unknown"
+			if project := client.Store.FindProject(item.ProjectID); project != nil {
+				name = project.Name
+			}
+			group = &htmlReportGroup{Project: name}
+			groups[item.ProjectID] = group
+			report.Groups = append(report.Groups, group)
+		}
+
+		task := htmlReportTask{
+			Priority: fmt.Sprintf("p%d", priorityMapping[item.Priority]),
+			Due:      dueDateString(item.DateTime(), item.AllDay),
+			Labels:   item.LabelsString(client.Store),
+			Content:  todoist.GetContentTitle(item),
+		}
+		if due := item.DateTime(); (due != time.Time{}) {
+			task.DueSort = due.Format(time.RFC3339)
+			task.Overdue = due.Before(time.Now())
+		}
+		if urls := todoist.GetContentURL(item); len(urls) > 0 {
+			task.URL = urls[0]
+		}
+		group.Tasks = append(group.Tasks, task)
+	}
+
+	var out io.Writer = os.Stdout
+	if path := c.String("out"); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	return htmlReportTemplate.Execute(out, report)
+}
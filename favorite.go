@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli"
+)
+
+// Favorite toggles the favorite status of a project, label or filter.
+func Favorite(c *cli.Context) error {
+	client := GetClient(c)
+	store := client.Store
+	ctx := GetContext(c)
+
+	if len(c.Args()) != 2 {
+		return ArgumentRequired
+	}
+	kind, name := c.Args().Get(0), c.Args().Get(1)
+
+	var err error
+	switch kind {
+	case "project":
+		project := store.FindProject(store.Projects.GetIDByName(name))
+		if project == nil {
+			return ProjectNotFound(name)
+		}
+		updated := *project
+		updated.IsFavorite = !updated.IsFavorite
+		err = client.UpdateProject(ctx, updated)
+	case "label":
+		label := store.FindLabel(store.Labels.GetIDByName(name))
+		if label == nil {
+			return LabelNotFound(name)
+		}
+		updated := *label
+		updated.IsFavorite = !updated.IsFavorite
+		err = client.UpdateLabel(ctx, updated)
+	case "filter":
+		filter := store.Filters.FindByName(name)
+		if filter == nil {
+			return &Error{Code: "filter_not_found", Message: fmt.Sprintf("filter %q not found", name), Hint: "run `todoist sync`"}
+		}
+		updated := *filter
+		updated.IsFavorite = !updated.IsFavorite
+		err = client.UpdateFilter(ctx, updated)
+	default:
+		return &Error{Code: "invalid_argument", Message: fmt.Sprintf("cannot favorite %q", kind), Hint: "use project, label or filter"}
+	}
+	if err != nil {
+		return err
+	}
+
+	return Sync(c)
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/urfave/cli"
+)
+
+// AtomFeed is a feed in the Atom format, RFC 4287.
+type AtomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  AtomPerson  `xml:"author"`
+	Entries []AtomEntry `xml:"entry"`
+}
+
+type AtomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Link       AtomLink       `xml:"link"`
+	Author     *AtomPerson    `xml:"author,omitempty"`
+	Categories []AtomCategory `xml:"category"`
+	Summary    string         `xml:"summary,omitempty"`
+}
+
+type AtomPerson struct {
+	Name  string `xml:"name"`
+	Email string `xml:"email,omitempty"`
+}
+
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+type AtomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// feedTime returns the time of a timestamp of the API, or zero.
+func feedTime(timestamp string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// atomFeed returns the tasks of store matching ex as a feed, titled after
+// filter. An entry is updated when its task was, and the feed when the
+// latest of them was or else when store was synced.
+func atomFeed(store *todoist.Store, filter string, ex Expression) AtomFeed {
+	title := filter
+	if title == "" {
+		title = "all tasks"
+	}
+	feed := AtomFeed{
+		ID:      "urn:todoist:filter:" + filter,
+		Title:   "Todoist: " + title,
+		Author:  AtomPerson{Name: "Todoist"},
+		Entries: []AtomEntry{},
+	}
+	updated := store.LastSync
+
+	for _, item := range FilterItems(store, ex) {
+		entryUpdated := feedTime(item.UpdatedAt)
+		if entryUpdated.IsZero() {
+			entryUpdated = feedTime(item.AddedAt)
+		}
+		if entryUpdated.IsZero() {
+			entryUpdated = store.LastSync
+		}
+		if updated.IsZero() || entryUpdated.After(updated) {
+			updated = entryUpdated
+		}
+
+		lines := []string{}
+		details := []string{fmt.Sprintf("p%d", 5-item.Priority)}
+		if project := store.FindProject(item.ProjectID); project != nil {
+			details = append(details, "#"+project.Name)
+		}
+		if !item.DateTime().IsZero() {
+			due := item.DateTime().Format(ShortDateTimeFormat)
+			if item.AllDay {
+				due = item.DateTime().Format(ShortDateFormat)
+			}
+			details = append(details, "due "+due)
+		}
+		lines = append(lines, strings.Join(details, ", "))
+		if item.Description != "" {
+			lines = append(lines, item.Description)
+		}
+
+		entry := AtomEntry{
+			ID:         "urn:todoist:task:" + item.ID,
+			Title:      item.Content,
+			Updated:    entryUpdated.UTC().Format(time.RFC3339),
+			Link:       AtomLink{Href: taskURL(item.ID)},
+			Categories: []AtomCategory{},
+			Summary:    strings.Join(lines, "\n"),
+		}
+		if collaborator := store.FindCollaborator(item.ResponsibleID()); collaborator != nil {
+			entry.Author = &AtomPerson{Name: collaborator.FullName, Email: collaborator.Email}
+		}
+		for _, label := range item.LabelNames {
+			entry.Categories = append(entry.Categories, AtomCategory{Term: label})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+	feed.Updated = updated.UTC().Format(time.RFC3339)
+	return feed
+}
+
+// feedHandler serves the feed of the tasks of the store returned by store
+// matching ex.
+func feedHandler(store func() *todoist.Store, filter string, ex Expression) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		buf, err := xml.MarshalIndent(atomFeed(store(), filter, ex), "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+		w.Write([]byte(xml.Header))
+		w.Write(buf)
+	})
+}
+
+func ServeFeed(c *cli.Context) error {
+	client := GetClient(c)
+
+	filter, err := ApplyContext(c.String("filter"))
+	if err != nil {
+		return err
+	}
+	ex, err := ParseFilter(filter)
+	if err != nil {
+		return &Error{Code: "invalid_filter", Message: err.Error(), Hint: "run `todoist filters check` on it"}
+	}
+
+	server := &storeServer{client: client}
+	handler := feedHandler(func() *todoist.Store { return client.Store }, filter, ex)
+	return server.listen(c, handler, fmt.Sprintf("the feed of %q", filter))
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAtomFeed(t *testing.T) {
+	store := testStore(t, `{
+		"projects": [{"id": "1", "name": "Roadmap"}],
+		"items": [
+			{"id": "10", "project_id": "1", "content": "Ship <it>", "priority": 4, "labels": ["release"], "responsible_uid": "2",
+			 "description": "before Friday", "added_at": "2020-01-01T09:00:00Z", "updated_at": "2020-01-03T09:30:00.123456Z"},
+			{"id": "11", "project_id": "1", "content": "Write notes", "priority": 1, "added_at": "2020-01-02T09:00:00Z"},
+			{"id": "12", "project_id": "1", "content": "Done", "checked": true}
+		],
+		"collaborators": [{"id": "2", "full_name": "Alex Kim", "email": "alex@example.com"}]
+	}`)
+
+	feed := atomFeed(store, "#Roadmap", Filter("#Roadmap"))
+	assert.Equal(t, "Todoist: #Roadmap", feed.Title, "they should be equal")
+	assert.Equal(t, "2020-01-03T09:30:00Z", feed.Updated, "they should be equal")
+	assert.Equal(t, 2, len(feed.Entries), "they should be equal")
+
+	entry := feed.Entries[0]
+	assert.Equal(t, "Ship <it>", entry.Title, "they should be equal")
+	assert.Equal(t, taskURL("10"), entry.Link.Href, "they should be equal")
+	assert.Equal(t, "Alex Kim", entry.Author.Name, "they should be equal")
+	assert.Equal(t, []AtomCategory{{Term: "release"}}, entry.Categories, "they should be equal")
+	assert.Equal(t, "p1, #Roadmap\nbefore Friday", entry.Summary, "they should be equal")
+	assert.Nil(t, feed.Entries[1].Author)
+	assert.Equal(t, "2020-01-02T09:00:00Z", feed.Entries[1].Updated, "they should be equal")
+}
+
+func TestFeedHandler(t *testing.T) {
+	store := testStore(t, `{"items": [{"id": "10", "content": "a & b"}]}`)
+	handler := feedHandler(func() *todoist.Store { return store }, "", Filter(""))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code, "they should be equal")
+	assert.Equal(t, "application/atom+xml; charset=utf-8", recorder.Header().Get("Content-Type"), "they should be equal")
+	body := recorder.Body.String()
+	assert.True(t, strings.HasPrefix(body, "<?xml"))
+	assert.Contains(t, body, `<feed xmlns="http://www.w3.org/2005/Atom">`)
+	assert.Contains(t, body, "<title>a &amp; b</title>")
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/other", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code, "they should be equal")
+}
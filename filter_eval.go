@@ -3,6 +3,7 @@ package main
 import (
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/sachaos/todoist/lib"
@@ -31,7 +32,7 @@ func Eval(e Expression, item todoist.AbstractItem, projects todoist.Projects, la
 		return EvalProject(e, item.GetProjectID(), projects), err
 	case LabelExpr:
 		e := e.(LabelExpr)
-		return EvalLabel(e, item.GetLabelIDs(), labels), err
+		return EvalLabel(e, item.GetLabelNames(), labels), err
 	case StringExpr:
 		switch item.(type) {
 		case *todoist.Item:
@@ -44,6 +45,12 @@ func Eval(e Expression, item todoist.AbstractItem, projects todoist.Projects, la
 	case DateExpr:
 		e := e.(DateExpr)
 		return EvalDate(e, item.DateTime()), err
+	case SearchExpr:
+		e := e.(SearchExpr)
+		return EvalSearch(e, item), err
+	case RegexExpr:
+		e := e.(RegexExpr)
+		return EvalRegex(e, item), err
 	case NotOpExpr:
 		e := e.(NotOpExpr)
 		r, err := Eval(e.expr, item, projects, labels)
@@ -100,7 +107,54 @@ func EvalAsPriority(e StringExpr, item *todoist.Item) (result bool) {
 	return false
 }
 
-func EvalProject(e ProjectExpr, projectID int, projects todoist.Projects) bool {
+// EvalRegex reports whether the content or the description of item matches
+// the regular expression.
+func EvalRegex(e RegexExpr, item todoist.AbstractItem) bool {
+	if e.re == nil {
+		return false
+	}
+	if e.matches != nil {
+		if item, ok := item.(*todoist.Item); ok {
+			return e.matches[item.ID]
+		}
+	}
+	if item, ok := item.(*todoist.Item); ok && e.re.MatchString(item.Description) {
+		return true
+	}
+	carrier, ok := item.(todoist.ContentCarrier)
+	return ok && e.re.MatchString(carrier.GetContent())
+}
+
+// EvalSearch reports whether item was found by the search index, or for
+// expressions not resolved with it, whether its content contains every word
+// of the query.
+func EvalSearch(e SearchExpr, item todoist.AbstractItem) bool {
+	if e.matches != nil {
+		if item, ok := item.(*todoist.Item); ok {
+			return e.matches[item.ID]
+		}
+	}
+	carrier, ok := item.(todoist.ContentCarrier)
+	if !ok {
+		return false
+	}
+	content := todoist.Tokenize(carrier.GetContent())
+	for _, word := range todoist.Tokenize(e.query) {
+		found := false
+		for _, term := range content {
+			if strings.HasPrefix(term, word) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func EvalProject(e ProjectExpr, projectID string, projects todoist.Projects) bool {
 	for _, id := range projects.GetIDsByName(e.name, e.isAll) {
 		if id == projectID {
 			return true
@@ -109,18 +163,17 @@ func EvalProject(e ProjectExpr, projectID int, projects todoist.Projects) bool {
 	return false
 }
 
-func EvalLabel(e LabelExpr, labelIDs []int, labels todoist.Labels) bool {
+func EvalLabel(e LabelExpr, labelNames []string, labels todoist.Labels) bool {
 	if e.name == "" {
-		return len(labelIDs) == 0
+		return len(labelNames) == 0
 	}
 
-	labelID := labels.GetIDByName(e.name)
-	if labelID == 0 {
+	if labels.GetIDByName(e.name) == "" {
 		return false
 	}
 
-	for _, id := range labelIDs {
-		if id == labelID {
+	for _, name := range labelNames {
+		if name == e.name {
 			return true
 		}
 	}
@@ -49,20 +49,20 @@ func TestPriorityEval(t *testing.T) {
 func TestLabelEval(t *testing.T) {
 	labels := todoist.Labels{
 		todoist.Label{
-			HaveID: todoist.HaveID{ID: 1},
+			HaveID: todoist.HaveID{ID: "1"},
 			Name:   "must",
 		},
 		todoist.Label{
-			HaveID: todoist.HaveID{ID: 2},
+			HaveID: todoist.HaveID{ID: "2"},
 			Name:   "icebox",
 		}, todoist.Label{
-			HaveID: todoist.HaveID{ID: 3},
+			HaveID: todoist.HaveID{ID: "3"},
 			Name:   "another",
 		},
 	}
 
 	item1 := todoist.Item{}
-	item1.LabelIDs = []int{1, 2}
+	item1.LabelNames = []string{"must", "icebox"}
 
 	testFilterEvalWithLabel(t, "@must", item1, labels, true)
 	testFilterEvalWithLabel(t, "@icebox", item1, labels, true)
@@ -72,21 +72,21 @@ func TestLabelEval(t *testing.T) {
 func TestProjectEval(t *testing.T) {
 	projects := todoist.Projects{
 		todoist.Project{
-			HaveID: todoist.HaveID{ID: 1},
+			HaveID: todoist.HaveID{ID: "1"},
 			Name:   "private",
 		},
 		todoist.Project{
-			HaveID:       todoist.HaveID{ID: 2},
-			HaveParentID: todoist.HaveParentID{ParentID: &[]int{1}[0]},
+			HaveID:       todoist.HaveID{ID: "2"},
+			HaveParentID: todoist.HaveParentID{ParentID: &[]string{"1"}[0]},
 			Name:         "nested",
 		},
 	}
 
 	item1 := todoist.Item{}
-	item1.ProjectID = 1
+	item1.ProjectID = "1"
 
 	item2 := todoist.Item{}
-	item2.ProjectID = 2
+	item2.ProjectID = "2"
 
 	testFilterEvalWithProject(t, "#private", item1, projects, true)
 	testFilterEvalWithProject(t, "#hoge", item1, projects, false)
@@ -171,3 +171,30 @@ func TestDueAfterEval(t *testing.T) {
 
 	testFilterEval(t, "due after: 10/2/2017 13:00", todoist.Item{Due: nil}, false) // JST: Mon 2 Oct 2017 13:01:00
 }
+
+func TestSearchEval(t *testing.T) {
+	item := todoist.Item{BaseItem: todoist.BaseItem{HaveID: todoist.HaveID{ID: "1"}, Content: "Buy milk"}}
+	testFilterEval(t, "search: mil", item, true)
+	testFilterEval(t, "search: bread", item, false)
+
+	store := &todoist.Store{Items: todoist.Items{item}}
+	store.Items[0].Description = "from the farm"
+	store.ConstructItemTree()
+	assert.Equal(t, 1, len(FilterItems(store, Filter("search: farm"))), "they should be equal")
+	assert.Equal(t, 0, len(FilterItems(store, Filter("search: town"))), "they should be equal")
+}
+
+func TestRegexEval(t *testing.T) {
+	item := todoist.Item{BaseItem: todoist.BaseItem{HaveID: todoist.HaveID{ID: "1"}, Content: "Buy milk"}, Description: "from the farm"}
+	testFilterEval(t, `regex: "^Buy (milk|bread)$"`, item, true)
+	testFilterEval(t, `regex: "farm$"`, item, true)
+	testFilterEval(t, `regex: "^milk"`, item, false)
+
+	store := &todoist.Store{
+		Items: todoist.Items{item},
+		Notes: todoist.Notes{{ItemID: "1", Content: "ask for ticket #4521"}},
+	}
+	store.ConstructItemTree()
+	assert.Equal(t, 1, len(FilterItems(store, Filter(`regex: "#45[0-9]+"`))), "they should be equal")
+	assert.Equal(t, 0, len(FilterItems(store, Filter(`regex: "#46[0-9]+"`))), "they should be equal")
+}
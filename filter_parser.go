@@ -9,8 +9,8 @@ import __yyfmt__ "fmt"
 
 import (
 	"fmt"
-
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"text/scanner"
@@ -21,6 +21,8 @@ type Expression interface{}
 type Token struct {
 	token   int
 	literal string
+	// offset is the byte offset of the token in the filter.
+	offset int
 }
 
 type VoidExpr struct{}
@@ -48,6 +50,21 @@ type NotOpExpr struct {
 	expr Expression
 }
 
+type SearchExpr struct {
+	query string
+	// matches are the ids of the items found by the search index, nil when
+	// the index was not consulted.
+	matches map[string]bool
+}
+
+type RegexExpr struct {
+	pattern string
+	re      *regexp.Regexp
+	// matches are the ids of the items whose content, description or
+	// comments match, nil when the comments were not looked at.
+	matches map[string]bool
+}
+
 const (
 	DUE_ON int = iota
 	DUE_BEFORE
@@ -74,7 +91,7 @@ var timezone = func() *time.Location {
 	return now().Location()
 }
 
-//line filter_parser.y:73
+//line filter_parser.y:90
 type yySymType struct {
 	yys   int
 	token Token
@@ -96,6 +113,8 @@ const OVERDUE = 57357
 const NO = 57358
 const DATE = 57359
 const LABELS = 57360
+const SEARCH = 57361
+const REGEX = 57362
 
 var yyToknames = [...]string{
 	"$end",
@@ -116,14 +135,16 @@ var yyToknames = [...]string{
 	"NO",
 	"DATE",
 	"LABELS",
+	"SEARCH",
+	"REGEX",
 	"'#'",
 	"'@'",
 	"'&'",
 	"'|'",
+	"':'",
 	"'('",
 	"')'",
 	"'!'",
-	"':'",
 	"'/'",
 }
 var yyStatenames = [...]string{}
@@ -132,11 +153,32 @@ const yyEofCode = 1
 const yyErrCode = 2
 const yyInitialStackSize = 16
 
-//line filter_parser.y:295
+//line filter_parser.y:338
 
 type Lexer struct {
 	scanner.Scanner
 	result Expression
+	err    *FilterError
+	// words are the tokens taken as StringExpr, for checking them.
+	words []Token
+}
+
+// FilterError is a syntax error in a filter.
+type FilterError struct {
+	Message string
+	// Offset is the byte offset in the filter where the error was found.
+	Offset int
+	// Text is the text of the token at Offset.
+	Text string
+}
+
+func (e *FilterError) Error() string {
+	return e.Message
+}
+
+func init() {
+	// Say which token was unexpected and what could have come instead.
+	yyErrorVerbose = true
 }
 
 var MonthIdentHash = map[string]time.Month{
@@ -215,26 +257,69 @@ func (l *Lexer) Lex(lval *yySymType) int {
 			token = DATE
 		} else if lowerToken == "labels" {
 			token = LABELS
+		} else if lowerToken == "search" {
+			token = SEARCH
+		} else if lowerToken == "regex" {
+			token = REGEX
 		} else {
 			token = STRING
 		}
 	case scanner.Int:
 		token = NUMBER
+	case scanner.String:
+		// Quoted text, like "search: \"due today\"", is taken as it is.
+		literal, err := strconv.Unquote(l.TokenText())
+		if err != nil {
+			literal = l.TokenText()
+		}
+		lval.token = Token{token: STRING, literal: literal, offset: l.Position.Offset}
+		return STRING
 	}
-	lval.token = Token{token: token, literal: l.TokenText()}
+	lval.token = Token{token: token, literal: l.TokenText(), offset: l.Position.Offset}
 	return token
 }
 
 func (l *Lexer) Error(e string) {
-	fmt.Fprintf(os.Stderr, "Filter error: %s \nFor proper filter syntax see https://support.todoist.com/hc/en-us/articles/205248842-Filters\n", e)
-	os.Exit(1)
+	if l.err != nil {
+		return
+	}
+	l.err = &FilterError{Message: strings.Replace(e, "$end", "end of filter", -1), Offset: l.Position.Offset, Text: l.TokenText()}
+	if !l.Position.IsValid() {
+		// The end of the filter.
+		l.err.Offset = l.Pos().Offset
+	}
 }
 
-func Filter(f string) (e Expression) {
+func parseFilter(f string) *Lexer {
 	l := new(Lexer)
 	l.Init(strings.NewReader(f))
+	// Since Go 1.13 "3pm" is scanned as a float with a "p" exponent, which
+	// is an error without a hexadecimal mantissa, so numbers are integers.
+	l.Mode = l.Mode&^scanner.ScanFloats | scanner.ScanInts
+	l.Scanner.Error = func(s *scanner.Scanner, msg string) {
+		l.Error(msg)
+	}
 	yyParse(l)
-	return l.result
+	return l
+}
+
+// ParseFilter parses the filter f.
+func ParseFilter(f string) (Expression, error) {
+	l := parseFilter(f)
+	if l.err != nil {
+		return nil, l.err
+	}
+	return l.result, nil
+}
+
+// Filter parses the filter f, exiting on syntax errors.
+func Filter(f string) (e Expression) {
+	e, err := ParseFilter(f)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Filter error: %s \nFor proper filter syntax see https://support.todoist.com/hc/en-us/articles/205248842-Filters\n", err)
+		os.Exit(1)
+	}
+	return e
 }
 
 //line yacctab:1
@@ -246,90 +331,94 @@ var yyExca = [...]int{
 
 const yyPrivate = 57344
 
-const yyLast = 72
+const yyLast = 82
 
 var yyAct = [...]int{
 
-	13, 3, 21, 22, 60, 24, 25, 26, 12, 2,
-	46, 17, 18, 16, 44, 46, 14, 15, 32, 33,
-	8, 61, 9, 20, 52, 51, 36, 28, 27, 45,
-	50, 28, 27, 39, 45, 43, 53, 48, 49, 38,
-	37, 21, 22, 41, 24, 25, 26, 34, 35, 40,
-	63, 62, 58, 59, 57, 56, 55, 54, 47, 42,
-	31, 30, 29, 7, 6, 5, 4, 11, 10, 19,
-	23, 1,
+	15, 3, 23, 24, 70, 26, 27, 28, 14, 2,
+	71, 19, 20, 18, 48, 50, 8, 9, 16, 17,
+	36, 37, 22, 10, 50, 11, 30, 29, 73, 60,
+	58, 59, 35, 49, 30, 29, 40, 47, 34, 52,
+	53, 43, 49, 61, 45, 38, 39, 42, 41, 23,
+	24, 44, 26, 27, 28, 66, 67, 55, 56, 72,
+	68, 69, 65, 64, 63, 62, 51, 46, 57, 33,
+	32, 31, 54, 7, 6, 5, 4, 13, 12, 21,
+	25, 1,
 }
 var yyPact = [...]int{
 
-	-3, -1000, 10, -1000, 58, 57, 56, -1000, -3, -3,
-	-1000, -1000, 35, -1000, 7, -1000, 22, 38, -1000, 54,
-	-1000, 8, 53, -1000, -1000, -1000, -1000, -3, -3, -1000,
-	-1000, -1000, 6, 10, -1, -2, -1000, -1000, -1000, 19,
-	-1000, -1000, 3, 52, 51, 50, -1000, 49, -1000, -1000,
-	-1000, 36, 36, -1000, -23, -1000, -5, -1000, -1000, -1000,
-	46, 45, -1000, -1000,
+	-3, -1000, 11, -1000, 67, 66, 65, -1000, 13, 7,
+	-3, -3, -1000, -1000, 33, -1000, 15, -1000, 30, 40,
+	-1000, 62, -1000, 8, 61, -1000, -1000, -1000, -1000, -3,
+	-3, -1000, -1000, -1000, 53, 64, 3, 11, 6, 4,
+	-1000, -1000, -1000, 26, -1000, -1000, 17, 60, 59, 58,
+	-1000, 57, -1000, -1000, 51, -1000, -1000, -1000, -1000, 44,
+	44, -1000, -25, -1000, -15, -1000, -1000, -1000, -1000, -1000,
+	54, 23, -1000, -1000,
 }
 var yyPgo = [...]int{
 
-	0, 71, 9, 0, 70, 69, 68, 67, 66, 65,
-	64, 63, 23,
+	0, 81, 9, 0, 80, 79, 78, 77, 76, 75,
+	74, 73, 22, 72,
 }
 var yyR1 = [...]int{
 
 	0, 1, 1, 2, 2, 2, 2, 2, 2, 2,
-	2, 2, 2, 2, 2, 2, 2, 9, 8, 10,
-	11, 7, 7, 6, 6, 3, 3, 3, 5, 5,
-	5, 5, 5, 5, 5, 4, 4, 4, 12, 12,
-	12,
+	2, 2, 2, 2, 2, 2, 2, 2, 2, 9,
+	8, 10, 13, 13, 13, 13, 11, 7, 7, 6,
+	6, 3, 3, 3, 5, 5, 5, 5, 5, 5,
+	5, 4, 4, 4, 12, 12, 12,
 }
 var yyR2 = [...]int{
 
 	0, 0, 1, 3, 3, 1, 2, 2, 2, 1,
-	3, 2, 1, 1, 4, 4, 1, 2, 1, 1,
-	2, 2, 3, 2, 1, 2, 1, 1, 5, 3,
-	3, 1, 1, 1, 1, 2, 2, 3, 3, 5,
-	2,
+	3, 3, 3, 2, 1, 1, 4, 4, 1, 2,
+	1, 1, 1, 1, 2, 2, 2, 2, 3, 2,
+	1, 2, 1, 1, 5, 3, 3, 1, 1, 1,
+	1, 2, 2, 3, 3, 5, 2,
 }
 var yyChk = [...]int{
 
-	-1000, -1, -2, 4, -8, -9, -10, -11, 23, 25,
-	-6, -7, 11, -3, 19, 20, 16, 14, 15, -5,
-	-12, 5, 6, -4, 8, 9, 10, 22, 21, 4,
-	4, 4, -2, -2, 12, 13, 19, 18, 17, 11,
-	11, -12, 5, 27, 6, 26, 7, 5, -2, -2,
-	24, 26, 26, 17, 5, 5, 5, 5, -3, -3,
-	27, 26, 5, 5,
+	-1000, -1, -2, 4, -8, -9, -10, -11, 19, 20,
+	26, 28, -6, -7, 11, -3, 21, 22, 16, 14,
+	15, -5, -12, 5, 6, -4, 8, 9, 10, 24,
+	23, 4, 4, 4, 25, 25, -2, -2, 12, 13,
+	21, 18, 17, 11, 11, -12, 5, 29, 6, 25,
+	7, 5, -2, -2, -13, 4, 5, 4, 27, 25,
+	25, 17, 5, 5, 5, 5, 4, 5, -3, -3,
+	29, 25, 5, 5,
 }
 var yyDef = [...]int{
 
 	1, -2, 2, 5, 0, 0, 0, 9, 0, 0,
-	12, 13, 0, 16, 18, 19, 0, 0, 24, 26,
-	27, 0, 0, 31, 32, 33, 34, 0, 0, 6,
-	7, 8, 0, 11, 0, 0, 17, 20, 21, 0,
-	23, 25, 0, 0, 36, 0, 40, 35, 3, 4,
-	10, 0, 0, 22, 37, 30, 38, 29, 14, 15,
-	0, 0, 28, 39,
+	0, 0, 14, 15, 0, 18, 20, 21, 0, 0,
+	30, 32, 33, 0, 0, 37, 38, 39, 40, 0,
+	0, 6, 7, 8, 0, 0, 0, 13, 0, 0,
+	19, 26, 27, 0, 29, 31, 0, 0, 42, 0,
+	46, 41, 3, 4, 10, 22, 23, 11, 12, 0,
+	0, 28, 43, 36, 44, 35, 24, 25, 16, 17,
+	0, 0, 34, 45,
 }
 var yyTok1 = [...]int{
 
 	1, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 25, 3, 19, 3, 3, 21, 3,
-	23, 24, 3, 3, 3, 3, 3, 27, 3, 3,
-	3, 3, 3, 3, 3, 3, 3, 3, 26, 3,
-	3, 3, 3, 3, 20, 3, 3, 3, 3, 3,
+	3, 3, 3, 28, 3, 21, 3, 3, 23, 3,
+	26, 27, 3, 3, 3, 3, 3, 29, 3, 3,
+	3, 3, 3, 3, 3, 3, 3, 3, 25, 3,
+	3, 3, 3, 3, 22, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 22,
+	3, 3, 3, 3, 24,
 }
 var yyTok2 = [...]int{
 
 	2, 3, 4, 5, 6, 7, 8, 9, 10, 11,
-	12, 13, 14, 15, 16, 17, 18,
+	12, 13, 14, 15, 16, 17, 18, 19, 20,
 }
 var yyTok3 = [...]int{
 	0,
@@ -674,164 +763,193 @@ yydefault:
 
 	case 1:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line filter_parser.y:95
+//line filter_parser.y:113
 		{
 			yyVAL.expr = VoidExpr{}
 		}
 	case 2:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line filter_parser.y:99
+//line filter_parser.y:117
 		{
 			yyVAL.expr = yyDollar[1].expr
 			yylex.(*Lexer).result = yyVAL.expr
 		}
 	case 3:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line filter_parser.y:106
+//line filter_parser.y:124
 		{
 			yyVAL.expr = BoolInfixOpExpr{left: yyDollar[1].expr, operator: '|', right: yyDollar[3].expr}
 		}
 	case 4:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line filter_parser.y:110
+//line filter_parser.y:128
 		{
 			yyVAL.expr = BoolInfixOpExpr{left: yyDollar[1].expr, operator: '&', right: yyDollar[3].expr}
 		}
 	case 5:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line filter_parser.y:114
+//line filter_parser.y:132
 		{
 			yyVAL.expr = StringExpr{literal: yyDollar[1].token.literal}
+			yylex.(*Lexer).words = append(yylex.(*Lexer).words, yyDollar[1].token)
 		}
 	case 6:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line filter_parser.y:118
+//line filter_parser.y:137
 		{
 			yyVAL.expr = ProjectExpr{isAll: false, name: yyDollar[2].token.literal}
 		}
 	case 7:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line filter_parser.y:122
+//line filter_parser.y:141
 		{
 			yyVAL.expr = ProjectExpr{isAll: true, name: yyDollar[2].token.literal}
 		}
 	case 8:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line filter_parser.y:126
+//line filter_parser.y:145
 		{
 			yyVAL.expr = LabelExpr{name: yyDollar[2].token.literal}
 		}
 	case 9:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line filter_parser.y:130
+//line filter_parser.y:149
 		{
 			yyVAL.expr = LabelExpr{name: ""}
 		}
 	case 10:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line filter_parser.y:134
+//line filter_parser.y:153
 		{
-			yyVAL.expr = yyDollar[2].expr
+			yyVAL.expr = SearchExpr{query: yyDollar[3].token.literal}
 		}
 	case 11:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line filter_parser.y:157
+		{
+			re, err := regexp.Compile(yyDollar[3].token.literal)
+			if err != nil {
+				yylex.Error("invalid regex: " + err.Error())
+			}
+			yyVAL.expr = RegexExpr{pattern: yyDollar[3].token.literal, re: re}
+		}
+	case 12:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line filter_parser.y:165
+		{
+			yyVAL.expr = yyDollar[2].expr
+		}
+	case 13:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line filter_parser.y:138
+//line filter_parser.y:169
 		{
 			yyVAL.expr = NotOpExpr{expr: yyDollar[2].expr}
 		}
-	case 12:
+	case 14:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line filter_parser.y:142
+//line filter_parser.y:173
 		{
 			yyVAL.expr = DateExpr{allDay: false, datetime: now(), operation: DUE_BEFORE}
 		}
-	case 13:
+	case 15:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line filter_parser.y:146
+//line filter_parser.y:177
 		{
 			yyVAL.expr = DateExpr{operation: NO_DUE_DATE}
 		}
-	case 14:
+	case 16:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line filter_parser.y:150
+//line filter_parser.y:181
 		{
 			e := yyDollar[4].expr.(DateExpr)
 			e.operation = DUE_BEFORE
 			yyVAL.expr = e
 		}
-	case 15:
+	case 17:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line filter_parser.y:156
+//line filter_parser.y:187
 		{
 			e := yyDollar[4].expr.(DateExpr)
 			e.operation = DUE_AFTER
 			yyVAL.expr = e
 		}
-	case 17:
+	case 19:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line filter_parser.y:165
+//line filter_parser.y:196
 		{
 			yyVAL.expr = yyDollar[1].token
 		}
-	case 18:
+	case 20:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line filter_parser.y:171
+//line filter_parser.y:202
 		{
 			yyVAL.expr = yyDollar[1].token
 		}
-	case 19:
+	case 21:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line filter_parser.y:177
+//line filter_parser.y:208
 		{
 			yyVAL.expr = yyDollar[1].token
 		}
-	case 20:
+	case 24:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line filter_parser.y:216
+		{
+			yyVAL.token = Token{token: STRING, literal: yyDollar[1].token.literal + " " + yyDollar[2].token.literal}
+		}
+	case 25:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line filter_parser.y:220
+		{
+			yyVAL.token = Token{token: STRING, literal: yyDollar[1].token.literal + " " + yyDollar[2].token.literal}
+		}
+	case 26:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line filter_parser.y:183
+//line filter_parser.y:226
 		{
 			yyVAL.expr = yyDollar[1].token
 		}
-	case 21:
+	case 27:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line filter_parser.y:189
+//line filter_parser.y:232
 		{
 			yyVAL.expr = yyDollar[1].token
 		}
-	case 22:
+	case 28:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line filter_parser.y:193
+//line filter_parser.y:236
 		{
 			yyVAL.expr = yyDollar[1].token
 		}
-	case 23:
+	case 29:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line filter_parser.y:199
+//line filter_parser.y:242
 		{
 			yyVAL.expr = yyDollar[1].token
 		}
-	case 24:
+	case 30:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line filter_parser.y:203
+//line filter_parser.y:246
 		{
 			yyVAL.expr = yyDollar[1].token
 		}
-	case 25:
+	case 31:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line filter_parser.y:209
+//line filter_parser.y:252
 		{
 			date := yyDollar[1].expr.(time.Time)
 			time := yyDollar[2].expr.(time.Duration)
 			yyVAL.expr = DateExpr{allDay: false, datetime: date.Add(time)}
 		}
-	case 26:
+	case 32:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line filter_parser.y:215
+//line filter_parser.y:258
 		{
 			yyVAL.expr = DateExpr{allDay: true, datetime: yyDollar[1].expr.(time.Time)}
 		}
-	case 27:
+	case 33:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line filter_parser.y:219
+//line filter_parser.y:262
 		{
 			nd := now().Sub(today())
 			d := yyDollar[1].expr.(time.Duration)
@@ -840,27 +958,27 @@ yydefault:
 			}
 			yyVAL.expr = DateExpr{allDay: false, datetime: today().Add(d)}
 		}
-	case 28:
+	case 34:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line filter_parser.y:230
+//line filter_parser.y:273
 		{
 			yyVAL.expr = time.Date(atoi(yyDollar[5].token.literal), time.Month(atoi(yyDollar[1].token.literal)), atoi(yyDollar[3].token.literal), 0, 0, 0, 0, timezone())
 		}
-	case 29:
+	case 35:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line filter_parser.y:234
+//line filter_parser.y:277
 		{
 			yyVAL.expr = time.Date(atoi(yyDollar[3].token.literal), MonthIdentHash[strings.ToLower(yyDollar[1].token.literal)], atoi(yyDollar[2].token.literal), 0, 0, 0, 0, timezone())
 		}
-	case 30:
+	case 36:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line filter_parser.y:238
+//line filter_parser.y:281
 		{
 			yyVAL.expr = time.Date(atoi(yyDollar[3].token.literal), MonthIdentHash[strings.ToLower(yyDollar[2].token.literal)], atoi(yyDollar[1].token.literal), 0, 0, 0, 0, timezone())
 		}
-	case 31:
+	case 37:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line filter_parser.y:242
+//line filter_parser.y:285
 		{
 			tod := today()
 			date := yyDollar[1].expr.(time.Time)
@@ -869,57 +987,57 @@ yydefault:
 			}
 			yyVAL.expr = date
 		}
-	case 32:
+	case 38:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line filter_parser.y:251
+//line filter_parser.y:294
 		{
 			yyVAL.expr = today()
 		}
-	case 33:
+	case 39:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line filter_parser.y:255
+//line filter_parser.y:298
 		{
 			yyVAL.expr = today().AddDate(0, 0, 1)
 		}
-	case 34:
+	case 40:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line filter_parser.y:259
+//line filter_parser.y:302
 		{
 			yyVAL.expr = today().AddDate(0, 0, -1)
 		}
-	case 35:
+	case 41:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line filter_parser.y:265
+//line filter_parser.y:308
 		{
 			yyVAL.expr = time.Date(today().Year(), MonthIdentHash[strings.ToLower(yyDollar[1].token.literal)], atoi(yyDollar[2].token.literal), 0, 0, 0, 0, timezone())
 		}
-	case 36:
+	case 42:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line filter_parser.y:269
+//line filter_parser.y:312
 		{
 			yyVAL.expr = time.Date(today().Year(), MonthIdentHash[strings.ToLower(yyDollar[2].token.literal)], atoi(yyDollar[1].token.literal), 0, 0, 0, 0, timezone())
 		}
-	case 37:
+	case 43:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line filter_parser.y:273
+//line filter_parser.y:316
 		{
 			yyVAL.expr = time.Date(now().Year(), time.Month(atoi(yyDollar[3].token.literal)), atoi(yyDollar[1].token.literal), 0, 0, 0, 0, timezone())
 		}
-	case 38:
+	case 44:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line filter_parser.y:279
+//line filter_parser.y:322
 		{
 			yyVAL.expr = time.Duration(int64(time.Hour)*int64(atoi(yyDollar[1].token.literal)) + int64(time.Minute)*int64(atoi(yyDollar[3].token.literal)))
 		}
-	case 39:
+	case 45:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line filter_parser.y:283
+//line filter_parser.y:326
 		{
 			yyVAL.expr = time.Duration(int64(time.Hour)*int64(atoi(yyDollar[1].token.literal)) + int64(time.Minute)*int64(atoi(yyDollar[3].token.literal)) + int64(time.Second)*int64(atoi(yyDollar[5].token.literal)))
 		}
-	case 40:
+	case 46:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line filter_parser.y:287
+//line filter_parser.y:330
 		{
 			hour := atoi(yyDollar[1].token.literal)
 			if TwelveClockIdentHash[yyDollar[2].token.literal] {
@@ -4,6 +4,7 @@ package main
 import (
     "fmt"
     "os"
+    "regexp"
     "strconv"
     "strings"
     "text/scanner"
@@ -14,6 +15,8 @@ type Expression interface{}
 type Token struct {
     token int
     literal string
+    // offset is the byte offset of the token in the filter.
+    offset int
 }
 
 type VoidExpr struct {}
@@ -41,6 +44,21 @@ type NotOpExpr struct {
     expr Expression
 }
 
+type SearchExpr struct {
+    query string
+    // matches are the ids of the items found by the search index, nil when
+    // the index was not consulted.
+    matches map[string]bool
+}
+
+type RegexExpr struct {
+    pattern string
+    re *regexp.Regexp
+    // matches are the ids of the items whose content, description or
+    // comments match, nil when the comments were not looked at.
+    matches map[string]bool
+}
+
 const (
     DUE_ON int = iota
     DUE_BEFORE
@@ -81,10 +99,11 @@ var timezone = func() *time.Location {
 %type<expr> s_date_year
 %type<expr> s_overdue s_nodate s_project_key s_project_all_key s_label_key s_no_labels
 %type<expr> s_time
+%type<token> s_search_words
 %token<token> STRING NUMBER
 %token<token> MONTH_IDENT TWELVE_CLOCK_IDENT
 %token<token> TODAY_IDENT TOMORROW_IDENT YESTERDAY_IDENT
-%token<token> DUE BEFORE AFTER OVER OVERDUE NO DATE LABELS '#' '@'
+%token<token> DUE BEFORE AFTER OVER OVERDUE NO DATE LABELS SEARCH REGEX '#' '@'
 %left '&' '|'
 
 %%
@@ -112,6 +131,7 @@ expr
     | STRING
     {
         $$ = StringExpr{literal: $1.literal}
+        yylex.(*Lexer).words = append(yylex.(*Lexer).words, $1)
     }
     | s_project_key STRING
     {
@@ -129,6 +149,18 @@ expr
     {
         $$ = LabelExpr{name: ""}
     }
+    | SEARCH ':' s_search_words
+    {
+        $$ = SearchExpr{query: $3.literal}
+    }
+    | REGEX ':' STRING
+    {
+        re, err := regexp.Compile($3.literal)
+        if err != nil {
+            yylex.Error("invalid regex: " + err.Error())
+        }
+        $$ = RegexExpr{pattern: $3.literal, re: re}
+    }
     | '(' expr ')'
     {
         $$ = $2
@@ -177,6 +209,18 @@ s_label_key
         $$ = $1
     }
 
+s_search_words
+    : STRING
+    | NUMBER
+    | s_search_words STRING
+    {
+        $$ = Token{token: STRING, literal: $1.literal + " " + $2.literal}
+    }
+    | s_search_words NUMBER
+    {
+        $$ = Token{token: STRING, literal: $1.literal + " " + $2.literal}
+    }
+
 s_no_labels
     : NO LABELS
     {
@@ -296,6 +340,27 @@ s_time
 type Lexer struct {
     scanner.Scanner
     result Expression
+    err *FilterError
+    // words are the tokens taken as StringExpr, for checking them.
+    words []Token
+}
+
+// FilterError is a syntax error in a filter.
+type FilterError struct {
+    Message string
+    // Offset is the byte offset in the filter where the error was found.
+    Offset int
+    // Text is the text of the token at Offset.
+    Text string
+}
+
+func (e *FilterError) Error() string {
+    return e.Message
+}
+
+func init() {
+    // Say which token was unexpected and what could have come instead.
+    yyErrorVerbose = true
 }
 
 var MonthIdentHash = map[string]time.Month{
@@ -375,24 +440,67 @@ func (l *Lexer) Lex(lval *yySymType) int {
                 token = DATE
             } else if lowerToken == "labels" {
                 token = LABELS
+            } else if lowerToken == "search" {
+                token = SEARCH
+            } else if lowerToken == "regex" {
+                token = REGEX
             } else {
                 token = STRING
             }
         case scanner.Int:
             token = NUMBER
+        case scanner.String:
+            // Quoted text, like "search: \"due today\"", is taken as it is.
+            literal, err := strconv.Unquote(l.TokenText())
+            if err != nil {
+                literal = l.TokenText()
+            }
+            lval.token = Token{token: STRING, literal: literal, offset: l.Position.Offset}
+            return STRING
     }
-    lval.token = Token{token: token, literal: l.TokenText()}
+    lval.token = Token{token: token, literal: l.TokenText(), offset: l.Position.Offset}
     return token
 }
 
 func (l *Lexer) Error(e string) {
-    fmt.Fprintf(os.Stderr, "Filter error: %s \nFor proper filter syntax see https://support.todoist.com/hc/en-us/articles/205248842-Filters\n", e)
-    os.Exit(1)
+    if l.err != nil {
+        return
+    }
+    l.err = &FilterError{Message: strings.Replace(e, "$end", "end of filter", -1), Offset: l.Position.Offset, Text: l.TokenText()}
+    if !l.Position.IsValid() {
+        // The end of the filter.
+        l.err.Offset = l.Pos().Offset
+    }
 }
 
-func Filter(f string) (e Expression) {
+func parseFilter(f string) *Lexer {
     l := new(Lexer)
     l.Init(strings.NewReader(f))
+    // Since Go 1.13 "3pm" is scanned as a float with a "p" exponent, which
+    // is an error without a hexadecimal mantissa, so numbers are integers.
+    l.Mode = l.Mode&^scanner.ScanFloats | scanner.ScanInts
+    l.Scanner.Error = func(s *scanner.Scanner, msg string) {
+        l.Error(msg)
+    }
     yyParse(l)
-    return l.result
+    return l
+}
+
+// ParseFilter parses the filter f.
+func ParseFilter(f string) (Expression, error) {
+    l := parseFilter(f)
+    if l.err != nil {
+        return nil, l.err
+    }
+    return l.result, nil
+}
+
+// Filter parses the filter f, exiting on syntax errors.
+func Filter(f string) (e Expression) {
+    e, err := ParseFilter(f)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "Filter error: %s \nFor proper filter syntax see https://support.todoist.com/hc/en-us/articles/205248842-Filters\n", err)
+        os.Exit(1)
+    }
+    return e
 }
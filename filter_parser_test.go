@@ -1,6 +1,7 @@
 package main
 
 import (
+	"regexp"
 	"testing"
 
 	"time"
@@ -176,3 +177,35 @@ func TestDateTimeElapsedFilter(t *testing.T) {
 		DateExpr{operation: DUE_ON, datetime: time.Date(timeNow.Year()+1, time.May, 16, 0, 0, 0, 0, testTimeZone), allDay: true},
 		Filter("16/05"), "they should be equal")
 }
+
+func TestSearchFilter(t *testing.T) {
+	assert.Equal(t, SearchExpr{query: "milk"}, Filter("search: milk"), "they should be equal")
+	assert.Equal(t, SearchExpr{query: "buy 2 milk"}, Filter("search: buy 2 milk"), "they should be equal")
+	assert.Equal(t,
+		BoolInfixOpExpr{
+			left:     ProjectExpr{name: "Work"},
+			operator: '&',
+			right:    SearchExpr{query: "due today"},
+		},
+		Filter(`#Work & search: "due today"`), "they should be equal")
+}
+
+func TestRegexFilter(t *testing.T) {
+	assert.Equal(t, RegexExpr{pattern: "^buy", re: regexp.MustCompile("^buy")}, Filter(`regex: "^buy"`), "they should be equal")
+	assert.Equal(t, RegexExpr{pattern: "milk", re: regexp.MustCompile("milk")}, Filter("regex: milk"), "they should be equal")
+
+	_, err := ParseFilter(`regex: "a(b"`)
+	assert.Error(t, err)
+}
+
+func TestTimeAfterNumberFilter(t *testing.T) {
+	setNow(time.Date(2017, time.January, 2, 1, 0, 0, 0, testTimeZone))
+	threePM := time.Date(2017, time.January, 2, 15, 0, 0, 0, testTimeZone)
+	assert.Equal(t,
+		DateExpr{operation: DUE_BEFORE, datetime: threePM, allDay: false},
+		Filter("due before: 3pm"), "they should be equal")
+
+	assert.Equal(t,
+		BoolInfixOpExpr{left: StringExpr{literal: "p1"}, operator: '&', right: DateExpr{operation: DUE_ON, datetime: threePM, allDay: false}},
+		Filter("p1 & 3pm"), "they should be equal")
+}
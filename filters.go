@@ -0,0 +1,238 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/urfave/cli"
+)
+
+// filterKeywords are the words of the filter syntax, which misspelled words
+// are compared with.
+var filterKeywords = []string{
+	"today", "tomorrow", "yesterday", "overdue", "due", "before", "after",
+	"over", "no", "date", "labels", "search", "regex", "p1", "p2", "p3", "p4",
+}
+
+// filterOperators are words people write for the operators.
+var filterOperators = map[string]string{
+	"and": "&",
+	"or":  "|",
+	"not": "!",
+}
+
+// FilterProblem is a mistake in a filter.
+type FilterProblem struct {
+	// Offset is the byte offset of the mistake in the filter.
+	Offset  int
+	Message string
+	Hint    string
+}
+
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+// suggestFilterWord returns what word was probably meant, or "".
+func suggestFilterWord(word string) string {
+	word = strings.ToLower(word)
+	if operator, ok := filterOperators[word]; ok {
+		return operator
+	}
+	best, bestDistance := "", 3
+	for _, keyword := range filterKeywords {
+		if distance := editDistance(word, keyword); distance < bestDistance && distance < len(keyword) {
+			best, bestDistance = keyword, distance
+		}
+	}
+	return best
+}
+
+// CheckFilter returns the syntax errors of the filter f, and the words of
+// it which match no task.
+func CheckFilter(f string) []FilterProblem {
+	l := parseFilter(f)
+	if l.err != nil {
+		problem := FilterProblem{Offset: l.err.Offset, Message: l.err.Message}
+		if suggestion := suggestFilterWord(l.err.Text); suggestion != "" {
+			problem.Hint = fmt.Sprintf("did you mean %q?", suggestion)
+		}
+		return []FilterProblem{problem}
+	}
+
+	problems := []FilterProblem{}
+	for _, word := range l.words {
+		if priorityRegex.MatchString(word.literal) {
+			continue
+		}
+		problem := FilterProblem{Offset: word.offset, Message: fmt.Sprintf("%q matches no task", word.literal)}
+		if suggestion := suggestFilterWord(word.literal); suggestion != "" {
+			problem.Hint = fmt.Sprintf("did you mean %q?", suggestion)
+		} else {
+			problem.Hint = fmt.Sprintf("use search: %q to find it in tasks", word.literal)
+		}
+		problems = append(problems, problem)
+	}
+	return problems
+}
+
+// FilterClause is a part of a filter with the number of tasks it matches.
+type FilterClause struct {
+	Description string
+	Tasks       int
+	Clauses     []FilterClause
+}
+
+func describeFilter(e Expression) (string, []Expression) {
+	switch e := e.(type) {
+	case BoolInfixOpExpr:
+		if e.operator == '&' {
+			return "AND", []Expression{e.left, e.right}
+		}
+		return "OR", []Expression{e.left, e.right}
+	case NotOpExpr:
+		return "NOT", []Expression{e.expr}
+	case StringExpr:
+		if priorityRegex.MatchString(e.literal) {
+			return "priority " + e.literal, nil
+		}
+		return fmt.Sprintf("word %q, which matches no task", e.literal), nil
+	case ProjectExpr:
+		if e.isAll {
+			return "project #" + e.name + " and its subprojects", nil
+		}
+		return "project #" + e.name, nil
+	case LabelExpr:
+		if e.name == "" {
+			return "no labels", nil
+		}
+		return "label @" + e.name, nil
+	case SearchExpr:
+		return fmt.Sprintf("search %q", e.query), nil
+	case RegexExpr:
+		return fmt.Sprintf("regex %q", e.pattern), nil
+	case DateExpr:
+		date := e.datetime.Format(ShortDateTimeFormat)
+		if e.allDay {
+			date = e.datetime.Format(ShortDateFormat)
+		}
+		switch e.operation {
+		case DUE_ON:
+			return "due on " + date, nil
+		case DUE_BEFORE:
+			return "due before " + date, nil
+		case DUE_AFTER:
+			return "due after " + date, nil
+		case NO_DUE_DATE:
+			return "no due date", nil
+		}
+	}
+	return "every task", nil
+}
+
+// ExplainFilter returns the clauses of e with the number of open tasks of
+// store each of them matches.
+func ExplainFilter(e Expression, store *todoist.Store) FilterClause {
+	description, children := describeFilter(e)
+	clause := FilterClause{Description: description}
+	for i := range store.Items {
+		item := &store.Items[i]
+		if item.Checked {
+			continue
+		}
+		if r, err := Eval(e, item, store.Projects, store.Labels); err == nil && r {
+			clause.Tasks++
+		}
+	}
+	for _, child := range children {
+		clause.Clauses = append(clause.Clauses, ExplainFilter(child, store))
+	}
+	return clause
+}
+
+func writeFilterClause(clause FilterClause, indent string, branch string, next string) {
+	writer.Write([]string{indent + branch + clause.Description, fmt.Sprintf("%d", clause.Tasks)})
+	for i, child := range clause.Clauses {
+		if i == len(clause.Clauses)-1 {
+			writeFilterClause(child, indent+next, "└─ ", "   ")
+		} else {
+			writeFilterClause(child, indent+next, "├─ ", "│  ")
+		}
+	}
+}
+
+func ExplainFilterCommand(c *cli.Context) error {
+	client := GetClient(c)
+
+	if !c.Args().Present() {
+		return ArgumentRequired
+	}
+	ex, err := ParseFilter(strings.Join(c.Args(), " "))
+	if err != nil {
+		return &Error{Code: "invalid_filter", Message: err.Error(), Hint: "run `todoist filters check` on it"}
+	}
+	ex = resolveSearch(ex, client.Store)
+
+	defer writer.Flush()
+
+	writer.WriteHeader([]string{"Clause", "Tasks"})
+	writeFilterClause(ExplainFilter(ex, client.Store), "", "", "")
+	return nil
+}
+
+func Filters(c *cli.Context) error {
+	client := GetClient(c)
+
+	defer writer.Flush()
+
+	writer.WriteHeader([]string{"ID", "Name", "Query"})
+
+	for _, filter := range client.Store.Filters {
+		if c.Bool("favorites") && !filter.IsFavorite {
+			continue
+		}
+		writer.Write([]string{IdFormat(filter), filter.Name + FavoriteFormat(filter.IsFavorite), filter.Query})
+	}
+
+	return nil
+}
+
+func CheckFilterCommand(c *cli.Context) error {
+	if !c.Args().Present() {
+		return ArgumentRequired
+	}
+	f := strings.Join(c.Args(), " ")
+
+	problems := CheckFilter(f)
+	if len(problems) == 0 {
+		fmt.Println("ok")
+		return nil
+	}
+	for _, problem := range problems {
+		fmt.Println(f)
+		fmt.Println(strings.Repeat(" ", len([]rune(f[:problem.Offset]))) + "^")
+		message := fmt.Sprintf("column %d: %s", len([]rune(f[:problem.Offset]))+1, problem.Message)
+		if problem.Hint != "" {
+			message += ", " + problem.Hint
+		}
+		fmt.Println(message)
+	}
+	return InvalidFilter(len(problems))
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFilterError(t *testing.T) {
+	_, err := ParseFilter("(p1 | p2")
+	assert.Equal(t, &FilterError{Message: "syntax error: unexpected end of filter, expecting '&' or '|' or ')'", Offset: 8, Text: ""}, err, "they should be equal")
+
+	e, err := ParseFilter("p1 & today")
+	assert.NoError(t, err)
+	assert.NotNil(t, e)
+}
+
+func TestCheckFilter(t *testing.T) {
+	assert.Equal(t, []FilterProblem{}, CheckFilter("p1 & (today | overdue)"), "they should be equal")
+	assert.Equal(t, []FilterProblem{
+		{Offset: 5, Message: `"tody" matches no task`, Hint: `did you mean "today"?`},
+		{Offset: 12, Message: `"urgent" matches no task`, Hint: `use search: "urgent" to find it in tasks`},
+	}, CheckFilter("p1 & tody & urgent"), "they should be equal")
+	assert.Equal(t, []FilterProblem{
+		{Offset: 3, Message: "syntax error: unexpected STRING", Hint: `did you mean "&"?`},
+	}, CheckFilter("p1 and today"), "they should be equal")
+}
+
+func TestExplainFilter(t *testing.T) {
+	store := testStore(t, `{
+		"projects": [{"id": "1", "name": "Work", "parent_id": null}],
+		"labels": [{"id": "2", "name": "office"}],
+		"items": [{"id": "10", "project_id": "1", "content": "a", "priority": 4, "labels": ["office"]},
+		          {"id": "11", "project_id": "1", "content": "b", "priority": 1, "labels": []},
+		          {"id": "12", "project_id": "1", "content": "c", "priority": 4, "labels": [], "checked": true}]
+	}`)
+	ex, err := ParseFilter("p1 & !@office")
+	assert.NoError(t, err)
+
+	assert.Equal(t, FilterClause{Description: "AND", Tasks: 0, Clauses: []FilterClause{
+		{Description: "priority p1", Tasks: 1},
+		{Description: "NOT", Tasks: 1, Clauses: []FilterClause{
+			{Description: "label @office", Tasks: 1},
+		}},
+	}}, ExplainFilter(ex, store), "they should be equal")
+}
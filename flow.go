@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/urfave/cli"
+)
+
+// FlowWeek is the tasks added and completed in the week from Start, and how
+// much the backlog grew from the first week to its end.
+type FlowWeek struct {
+	Start     time.Time
+	Added     int
+	Completed int
+	Backlog   int
+}
+
+// Net is how much the backlog grew in the week, shrinking when negative.
+func (w *FlowWeek) Net() int {
+	return w.Added - w.Completed
+}
+
+// weekStart is the Monday starting the week of t.
+func weekStart(t time.Time) time.Time {
+	day := startOfDay(t)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// Flow counts the tasks added and completed at the given times by the week,
+// weeks starting on Monday, from the week of since to that of until. Times
+// out of since to until are left out.
+func Flow(added []time.Time, completed []time.Time, since time.Time, until time.Time) []*FlowWeek {
+	weeks := []*FlowWeek{}
+	for start := weekStart(since); !start.After(until); start = start.AddDate(0, 0, 7) {
+		weeks = append(weeks, &FlowWeek{Start: start})
+	}
+	week := func(t time.Time) *FlowWeek {
+		if t.Before(since) || t.After(until) {
+			return nil
+		}
+		start := weekStart(t.Local())
+		for _, w := range weeks {
+			if w.Start.Equal(start) {
+				return w
+			}
+		}
+		return nil
+	}
+	for _, t := range added {
+		if w := week(t); w != nil {
+			w.Added++
+		}
+	}
+	for _, t := range completed {
+		if w := week(t); w != nil {
+			w.Completed++
+		}
+	}
+	backlog := 0
+	for _, w := range weeks {
+		backlog += w.Net()
+		w.Backlog = backlog
+	}
+	return weeks
+}
+
+// sparkline draws values as a line of bars, from the lowest to the highest.
+func sparkline(values []int) string {
+	bars := []rune("▁▂▃▄▅▆▇█")
+	if len(values) == 0 {
+		return ""
+	}
+	min, max := values[0], values[0]
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if max > min {
+			i = (v - min) * (len(bars) - 1) / (max - min)
+		}
+		b.WriteRune(bars[i])
+	}
+	return b.String()
+}
+
+// flowTrend sums up where the backlog of weeks went.
+func flowTrend(weeks []*FlowWeek) string {
+	if len(weeks) == 0 {
+		return ""
+	}
+	net := weeks[len(weeks)-1].Backlog
+	var trend string
+	switch {
+	case net > 0:
+		trend = fmt.Sprintf("Backlog grew by %d in %d weeks", net, len(weeks))
+	case net < 0:
+		trend = fmt.Sprintf("Backlog shrank by %d in %d weeks", -net, len(weeks))
+	default:
+		trend = fmt.Sprintf("Backlog held steady in %d weeks", len(weeks))
+	}
+	if a11y {
+		return trend
+	}
+	backlogs := []int{}
+	for _, w := range weeks {
+		backlogs = append(backlogs, w.Backlog)
+	}
+	return sparkline(backlogs) + " " + trend
+}
+
+// flowCompleted returns when the tasks completed from since until until
+// were, a request per CompletedDays as the API allows no more.
+func flowCompleted(c *cli.Context, client *todoist.Client, since time.Time, until time.Time) ([]time.Time, error) {
+	times := []time.Time{}
+	for from := since; from.Before(until); from = from.AddDate(0, 0, todoist.CompletedDays) {
+		to := from.AddDate(0, 0, todoist.CompletedDays)
+		if to.After(until) {
+			to = until
+		}
+		var completed todoist.Completed
+		if err := client.CompletedBetween(GetContext(c), from, to, &completed); err != nil {
+			return nil, err
+		}
+		for _, item := range completed.Items {
+			times = append(times, item.DateTime())
+		}
+	}
+	return times, nil
+}
+
+func StatsFlow(c *cli.Context) error {
+	client := GetClient(c)
+
+	age, err := parseDuration(c.String("since"))
+	if err != nil {
+		return err
+	}
+	until := time.Now()
+	since := until.Add(-age)
+
+	// The activity log and the completed tasks are only available to premium
+	// users. The cache has neither the tasks completed nor those deleted, so
+	// counting its tasks instead would be wrong, and what is unknown is shown
+	// as "-".
+	var added []time.Time
+	var activity todoist.Activity
+	if err := client.ActivityBetween(GetContext(c), "item", "added", since, until, &activity); err != nil {
+		fmt.Fprintf(os.Stderr, "The added tasks are unknown, the activity log is unavailable: %s\n", err)
+	} else {
+		added = []time.Time{}
+		for _, event := range activity.Events {
+			added = append(added, event.DateTime())
+		}
+	}
+	completed, err := flowCompleted(c, client, since, until)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "The completed tasks are unknown, they are unavailable: %s\n", err)
+	}
+
+	weeks := Flow(added, completed, since, until)
+	count := func(known bool, n int, format string) string {
+		if !known {
+			return "-"
+		}
+		return fmt.Sprintf(format, n)
+	}
+	writer.WriteHeader([]string{"Week", "Added", "Completed", "Net", "Backlog"})
+	for _, w := range weeks {
+		writer.Write([]string{
+			w.Start.Format(todoist.RFC3339Date),
+			count(added != nil, w.Added, "%d"),
+			count(completed != nil, w.Completed, "%d"),
+			count(added != nil && completed != nil, w.Net(), "%+d"),
+			count(added != nil && completed != nil, w.Backlog, "%+d"),
+		})
+	}
+	writer.Flush()
+
+	if added == nil || completed == nil {
+		return nil
+	}
+	fmt.Fprintln(os.Stderr, flowTrend(weeks))
+	return nil
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlow(t *testing.T) {
+	// 2020-01-06 is a Monday.
+	day := func(month time.Month, day int, hour int) time.Time {
+		return time.Date(2020, month, day, hour, 0, 0, 0, time.Local)
+	}
+	added := []time.Time{day(1, 1, 12), day(1, 3, 12), day(1, 7, 12), day(1, 8, 12), day(1, 9, 12), day(1, 15, 12), day(1, 17, 12)}
+	completed := []time.Time{day(1, 3, 18), day(1, 12, 23), day(1, 13, 9), day(1, 14, 9), day(1, 15, 9), day(1, 16, 9), day(1, 20, 9)}
+
+	weeks := []FlowWeek{}
+	for _, w := range Flow(added, completed, day(1, 2, 0), day(1, 17, 18)) {
+		weeks = append(weeks, *w)
+	}
+	assert.Equal(t, []FlowWeek{
+		{Start: day(12, 30, 0).AddDate(-1, 0, 0), Added: 1, Completed: 1, Backlog: 0},
+		{Start: day(1, 6, 0), Added: 3, Completed: 1, Backlog: 2},
+		{Start: day(1, 13, 0), Added: 2, Completed: 4, Backlog: 0},
+	}, weeks, "they should be equal")
+}
+
+func TestFlowTrend(t *testing.T) {
+	weeks := []*FlowWeek{{Backlog: 0}, {Backlog: 7}, {Backlog: 3}}
+	assert.Equal(t, "▁█▄ Backlog grew by 3 in 3 weeks", flowTrend(weeks), "they should be equal")
+
+	weeks = append(weeks, &FlowWeek{Backlog: -2})
+	assert.Equal(t, "▂█▄▁ Backlog shrank by 2 in 4 weeks", flowTrend(weeks), "they should be equal")
+}
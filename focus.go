@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/spf13/viper"
+	"github.com/urfave/cli"
+)
+
+// focusLabel is the label of the task in focus, focus_label in the config,
+// none for "".
+func focusLabel() string {
+	if viper.IsSet("focus_label") {
+		return strings.TrimPrefix(viper.GetString("focus_label"), "@")
+	}
+	return "in-progress"
+}
+
+// runFocusHook runs focus_hook from the config, like a command turning on
+// do not disturb, with $TODOIST_FOCUS set to state, "start" or "stop", and
+// $TODOIST_FOCUS_TASK to the task. A failing hook doesn't stop the session.
+func runFocusHook(state string, item *todoist.Item) {
+	command := viper.GetString("focus_hook")
+	if command == "" {
+		return
+	}
+	cmd := shellCommand(command)
+	cmd.Env = append(os.Environ(), "TODOIST_FOCUS="+state, "TODOIST_FOCUS_TASK="+todoist.GetContentTitle(item))
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "focus_hook %q failed: %s\n", command, err)
+	}
+}
+
+// remainingFormat is d like a timer, "4:05" or "1:02:03".
+func remainingFormat(d time.Duration) string {
+	seconds := int((d + time.Second - 1) / time.Second)
+	if seconds >= 60*60 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+// focusCountdown waits until end, showing the time left on out if it is not
+// nil. It returns false if ctx is done before, like on ^C.
+func focusCountdown(ctx context.Context, out io.Writer, title string, end time.Time) bool {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	width := 0
+	defer func() {
+		if width > 0 {
+			fmt.Fprint(out, "\r"+strings.Repeat(" ", width)+"\r")
+		}
+	}()
+	for {
+		left := time.Until(end)
+		if left <= 0 {
+			return true
+		}
+		if out != nil {
+			line := fmt.Sprintf("%s %s left", title, remainingFormat(left))
+			padding := ""
+			if w := visibleWidth(line); w < width {
+				padding = strings.Repeat(" ", width-w)
+			} else {
+				width = w
+			}
+			fmt.Fprint(out, "\r"+line+padding)
+		}
+		select {
+		case <-ticker.C:
+		case <-time.After(left):
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+func Focus(c *cli.Context) error {
+	client := GetClient(c)
+
+	if !c.Args().Present() {
+		return ArgumentRequired
+	}
+	id, err := ResolveItemID(client, c.Args().First())
+	if err != nil {
+		return err
+	}
+	item := client.Store.FindItem(id)
+	if item == nil {
+		return IdNotFound
+	}
+	duration := c.Duration("for")
+	if duration <= 0 {
+		return &Error{Code: "invalid_argument", Message: fmt.Sprintf("invalid duration %s", duration), Hint: "give how long to focus like --for 50m"}
+	}
+
+	// The label tells other devices the task is in progress.
+	labels := append([]string{}, item.LabelNames...)
+	label := focusLabel()
+	labeled := false
+	for _, name := range labels {
+		if name == label {
+			// Already in progress, it is left so.
+			label = ""
+		}
+	}
+	if label != "" {
+		if err := client.SetItemLabels(GetContext(c), id, append(labels, label)); err != nil {
+			return err
+		}
+		if err := WriteCache(default_cache_path, client.Store); err != nil {
+			return err
+		}
+		labeled = true
+	}
+
+	title := todoist.GetContentTitle(item)
+	fmt.Fprintf(os.Stderr, "Focusing on %s for %s, ^C to stop early\n", title, duration)
+	runFocusHook("start", item)
+	var out io.Writer
+	if isTerminal(os.Stderr) {
+		out = os.Stderr
+	}
+	done := focusCountdown(GetContext(c), out, title, time.Now().Add(duration))
+	runFocusHook("stop", item)
+	if !done {
+		// ^C only ends the session, the task is still to be wrapped up and
+		// another ^C aborts that.
+		ctx, cancel := interruptContext()
+		defer cancel()
+		c.App.Metadata["context"] = ctx
+	}
+
+	question := fmt.Sprintf("Time's up for %s.", title)
+	if !done {
+		question = fmt.Sprintf("Stopped focusing on %s.", title)
+	}
+	choice, promptErr := promptChoice(question, []string{"close it", "postpone it to tomorrow", "keep it as it is"})
+	if promptErr != nil {
+		// The task is kept, without the label all the same.
+		choice = 2
+	}
+
+	client.Buffer()
+	if labeled {
+		client.SetItemLabels(GetContext(c), id, labels)
+	}
+	switch choice {
+	case 0:
+		client.CloseItem(GetContext(c), []string{id})
+	case 1:
+		updated := todoist.Item{Due: item.Due}
+		updated.ID = id
+		// A date rather than "tomorrow" keeps the recurrence of the task.
+		updated.Reschedule(time.Now().AddDate(0, 0, 1).Format(todoist.RFC3339Date), false)
+		client.UpdateItem(GetContext(c), updated)
+	}
+	if err := client.Flush(GetContext(c)); err != nil {
+		return err
+	}
+	if err := Sync(c); err != nil {
+		return err
+	}
+	if promptErr != NotInteractive {
+		return promptErr
+	}
+	return nil
+}
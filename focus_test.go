@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sachaos/todoist/lib/todoisttest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemainingFormat(t *testing.T) {
+	assert.Equal(t, "50:00", remainingFormat(50*time.Minute), "they should be equal")
+	assert.Equal(t, "0:01", remainingFormat(300*time.Millisecond), "they should be equal")
+	assert.Equal(t, "1:02:03", remainingFormat(time.Hour+2*time.Minute+3*time.Second), "they should be equal")
+}
+
+func TestFocus(t *testing.T) {
+	server := todoisttest.NewServer(t, `{
+		"projects": [{"id": "1", "name": "Inbox", "inbox_project": true}],
+		"items": [{"id": "10", "project_id": "1", "content": "Write the report", "priority": 1, "labels": ["office"]}]
+	}`)
+	run := runTodoist(t, server)
+	hookOutput := filepath.Join(filepath.Dir(os.Getenv("TODOIST_CONFIG")), "hook")
+	config := `{"focus_hook": "echo $TODOIST_FOCUS $TODOIST_FOCUS_TASK >> ` + hookOutput + `"}`
+	assert.NoError(t, ioutil.WriteFile(os.Getenv("TODOIST_CONFIG"), []byte(config), 0600))
+
+	_, err := run("sync")
+	assert.NoError(t, err)
+	_, err = run("focus", "--for", "10ms", "10")
+	assert.NoError(t, err)
+
+	hook, err := ioutil.ReadFile(hookOutput)
+	assert.NoError(t, err)
+	assert.Equal(t, "start Write the report\nstop Write the report\n", string(hook), "they should be equal")
+
+	// Without a terminal to ask, the task is kept without the label.
+	client := server.NewClient()
+	assert.NoError(t, client.Sync(context.Background()))
+	item := client.Store.FindItem("10")
+	assert.False(t, item.Checked)
+	assert.Equal(t, []string{"office"}, item.LabelNames, "they should be equal")
+
+	_, err = run("focus", "--for", "0s", "10")
+	assert.Error(t, err)
+}
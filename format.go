@@ -2,28 +2,97 @@ package main
 
 import (
 	"fmt"
-	"strconv"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/fatih/color"
 	"github.com/sachaos/todoist/lib"
 	"github.com/urfave/cli"
+	"golang.org/x/text/width"
 )
 
+const (
+	ellipsis        = "…"
+	minContentWidth = 10
+)
+
+var ansiRegex = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
 func ColorList() []color.Attribute {
-	return []color.Attribute{
-		color.FgHiRed,
-		color.FgHiGreen,
-		color.FgHiYellow,
-		color.FgHiBlue,
-		color.FgHiMagenta,
-		color.FgHiCyan,
+	return theme.Projects
+}
+
+// appColors are the colors of projects and labels in the app.
+var appColors = map[string]string{
+	"berry_red":   "b8256f",
+	"red":         "db4035",
+	"orange":      "ff9933",
+	"yellow":      "fad000",
+	"olive_green": "afb83b",
+	"lime_green":  "7ecc49",
+	"green":       "299438",
+	"mint_green":  "6accbc",
+	"teal":        "158fad",
+	"sky_blue":    "14aaf5",
+	"light_blue":  "96c3eb",
+	"blue":        "4073ff",
+	"grape":       "884dff",
+	"violet":      "af38eb",
+	"lavender":    "eb96eb",
+	"magenta":     "e05194",
+	"salmon":      "ff8d85",
+	"charcoal":    "808080",
+	"grey":        "b8b8b8",
+	"taupe":       "ccac93",
+}
+
+// AppColorNames returns the names of the app colors in alphabetical order.
+func AppColorNames() []string {
+	names := make([]string, 0, len(appColors))
+	for name := range appColors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AppColor returns the attributes of the closest of the 256 terminal colors
+// to the app color name, or nil for an unknown name.
+func AppColor(name string) []color.Attribute {
+	hex, ok := appColors[name]
+	if !ok {
+		return nil
+	}
+	var r, g, b int
+	fmt.Sscanf(hex, "%02x%02x%02x", &r, &g, &b)
+	// The 6x6x6 color cube of the 256 colors.
+	levels := []int{0, 95, 135, 175, 215, 255}
+	nearest := func(v int) int {
+		best := 0
+		for i, level := range levels {
+			if abs(v-level) < abs(v-levels[best]) {
+				best = i
+			}
+		}
+		return best
 	}
+	index := 16 + 36*nearest(r) + 6*nearest(g) + nearest(b)
+	return []color.Attribute{38, 5, color.Attribute(index)}
 }
 
-func GenerateColorHash(ids []int, colorList []color.Attribute) map[int]color.Attribute {
-	colorHash := map[int]color.Attribute{}
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func GenerateColorHash(ids []string, colorList []color.Attribute) map[string]color.Attribute {
+	colorHash := map[string]color.Attribute{}
 	colorNum := 0
 	for _, id := range ids {
 		var colorAttribute color.Attribute
@@ -43,13 +112,16 @@ func GenerateColorHash(ids []int, colorList []color.Attribute) map[int]color.Att
 }
 
 func IdFormat(carrier todoist.IDCarrier) string {
-	return color.BlueString(strconv.Itoa(carrier.GetID()))
+	return color.New(theme.ID...).SprintFunc()(carrier.GetID())
+}
+
+// WorkspaceFormat is the heading of the projects of a workspace.
+func WorkspaceFormat(workspace *todoist.Workspace) string {
+	return color.New(color.Bold).Sprint(workspace.Name)
 }
 
 func ContentPrefix(store *todoist.Store, item *todoist.Item, depth int, c *cli.Context) (prefix string) {
-	if c.GlobalBool("indent") {
-		prefix = prefix + strings.Repeat("    ", depth)
-	}
+	prefix = strings.Repeat("    ", depth)
 	if c.GlobalBool("namespace") {
 		parents := todoist.SearchItemParents(store, item)
 		for _, parent := range parents {
@@ -61,38 +133,34 @@ func ContentPrefix(store *todoist.Store, item *todoist.Item, depth int, c *cli.C
 
 func ContentFormat(item todoist.ContentCarrier) string {
 	if todoist.HasURL(item) {
+		if a11y {
+			return todoist.GetContentTitle(item) + " (link)"
+		}
 		return color.New(color.Underline).SprintFunc()(todoist.GetContentTitle(item))
 	}
 	return todoist.GetContentTitle(item)
 }
 
 func PriorityFormat(priority int) string {
+	if a11y {
+		return a11yPriority(priority)
+	}
 	priorityColor := color.New(color.Bold)
 	var p int
-	switch priority {
-	case 1:
-		p = 4
-		priorityColor.Add(color.FgBlue).Add(color.BgBlack)
-	case 2:
-		p = 3
-		priorityColor.Add(color.FgHiYellow).Add(color.BgBlack)
-	case 3:
-		p = 2
-		priorityColor.Add(color.FgHiRed).Add(color.BgBlack)
-	case 4:
-		p = 1
-		priorityColor.Add(color.FgWhite).Add(color.BgRed)
+	if priority >= 1 && priority <= 4 {
+		p = 5 - priority
+		priorityColor.Add(theme.Priorities[priority]...)
 	}
 	return priorityColor.SprintFunc()(fmt.Sprintf("p%d", p))
 }
 
-func ProjectFormat(id int, store *todoist.Store, projectColorHash map[int]color.Attribute, c *cli.Context) string {
+func ProjectFormat(id string, store *todoist.Store, projectColorHash map[string]color.Attribute, c *cli.Context) string {
 	var prefix string
 	var namePrefix string
 	project := store.FindProject(id)
 	if project == nil {
 		// Accept unknown project ID
-		return color.New(color.FgCyan).SprintFunc()("Unknown")
+		return color.New(theme.Unknown...).SprintFunc()("Unknown")
 	}
 
 	projectName := project.Name
@@ -102,7 +170,85 @@ func ProjectFormat(id int, store *todoist.Store, projectColorHash map[int]color.
 			namePrefix = namePrefix + project.Name + ":"
 		}
 	}
-	return prefix + color.New(projectColorHash[project.GetID()]).SprintFunc()("#"+namePrefix+projectName)
+	return prefix + projectColor(project, projectColorHash)("#"+namePrefix+projectName)
+}
+
+// projectColor returns the function coloring project, in its color in the
+// app or else the one of projectColorHash.
+func projectColor(project *todoist.Project, projectColorHash map[string]color.Attribute) func(a ...interface{}) string {
+	attributes := AppColor(project.Color)
+	if attributes == nil {
+		attributes = []color.Attribute{projectColorHash[project.GetID()]}
+	}
+	return color.New(attributes...).SprintFunc()
+}
+
+// ProjectPathFormat returns the project with the names of its ancestors,
+// like #Work/ClientA.
+func ProjectPathFormat(id string, store *todoist.Store, projectColorHash map[string]color.Attribute) string {
+	project := store.FindProject(id)
+	if project == nil {
+		return color.New(theme.Unknown...).SprintFunc()("Unknown")
+	}
+	names := []string{}
+	for _, parent := range todoist.SearchProjectParents(store, project) {
+		names = append(names, parent.Name)
+	}
+	names = append(names, project.Name)
+	return projectColor(project, projectColorHash)("#" + strings.Join(names, "/"))
+}
+
+// ParentsFormat returns the breadcrumb of the parent tasks of item, the
+// topmost first, or "" if it has none.
+func ParentsFormat(item *todoist.Item, store *todoist.Store) string {
+	titles := []string{}
+	for _, parent := range todoist.SearchItemParents(store, item) {
+		titles = append(titles, todoist.GetContentTitle(parent))
+	}
+	return strings.Join(titles, " › ")
+}
+
+// LabelsFormat returns the labels of item in the colors of the app.
+func LabelsFormat(item *todoist.Item, store *todoist.Store) string {
+	labels := make([]string, len(item.LabelNames))
+	for i, name := range item.LabelNames {
+		labels[i] = "@" + name
+		if label := store.FindLabel(store.Labels.GetIDByName(name)); label != nil {
+			if attributes := AppColor(label.Color); attributes != nil {
+				labels[i] = color.New(attributes...).SprintFunc()(labels[i])
+			}
+		}
+	}
+	return strings.Join(labels, ",")
+}
+
+func FavoriteFormat(favorite bool) string {
+	if !favorite {
+		return ""
+	}
+	if a11y {
+		return " (favorite)"
+	}
+	return color.New(theme.Favorite...).SprintFunc()(" ★")
+}
+
+// CommentCountFormat marks a task with count comments, or nothing without
+// any.
+func CommentCountFormat(count int) string {
+	if count == 0 {
+		return ""
+	}
+	if a11y {
+		return a11yComments(count)
+	}
+	return color.New(theme.Unknown...).SprintFunc()(fmt.Sprintf("✎ %d", count))
+}
+
+func ArchivedFormat(archived bool) string {
+	if !archived {
+		return ""
+	}
+	return color.New(theme.Unknown...).SprintFunc()(" (archived)")
 }
 
 func dueDateString(dueDate time.Time, allDay bool) string {
@@ -116,20 +262,50 @@ func dueDateString(dueDate time.Time, allDay bool) string {
 	return dueDate.Format(ShortDateFormat)
 }
 
+// dueClass returns how urgent a due date is at now, comparing days in local
+// time: "overdue", "today", "tomorrow" or "later". A task due all day is only
+// overdue from the next day on.
+func dueClass(dueDate time.Time, allDay bool, now time.Time) string {
+	now = now.Local()
+	dueDate = dueDate.Local()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	day := time.Date(dueDate.Year(), dueDate.Month(), dueDate.Day(), 0, 0, 0, 0, time.Local)
+	switch {
+	case day.Before(today), !allDay && dueDate.Before(now):
+		return "overdue"
+	case day.Equal(today):
+		return "today"
+	case day.Equal(today.AddDate(0, 0, 1)):
+		return "tomorrow"
+	default:
+		return "later"
+	}
+}
+
+// dueDateColors returns the colors of a due date by its dueClass.
+func dueDateColors(dueDate time.Time, allDay bool, now time.Time) []color.Attribute {
+	switch dueClass(dueDate, allDay, now) {
+	case "overdue":
+		return theme.Overdue
+	case "today":
+		return theme.DueToday
+	case "tomorrow":
+		return theme.DueSoon
+	default:
+		return theme.DueLater
+	}
+}
+
 func DueDateFormat(dueDate time.Time, allDay bool) string {
-	dueDateString := dueDateString(dueDate, allDay)
-	duration := time.Since(dueDate)
-	dueDateColor := color.New(color.Bold)
-	if duration > 0 {
-		dueDateColor.Add(color.FgWhite).Add(color.BgRed)
-	} else if duration > -12*time.Hour {
-		dueDateColor.Add(color.FgHiRed).Add(color.BgBlack)
-	} else if duration > -24*time.Hour {
-		dueDateColor.Add(color.FgHiYellow).Add(color.BgBlack)
-	} else {
-		dueDateColor.Add(color.FgHiBlue).Add(color.BgBlack)
+	if (dueDate == time.Time{}) {
+		return ""
+	}
+	if a11y {
+		return a11yDueDate(dueDate, allDay, time.Now())
 	}
-	return dueDateColor.SprintFunc()(dueDateString)
+	dueDateColor := color.New(color.Bold)
+	dueDateColor.Add(dueDateColors(dueDate, allDay, time.Now())...)
+	return dueDateColor.SprintFunc()(dueDateString(dueDate, allDay))
 }
 
 func completedDateString(completedDate time.Time) string {
@@ -143,3 +319,110 @@ func completedDateString(completedDate time.Time) string {
 func CompletedDateFormat(completedDate time.Time) string {
 	return completedDateString(completedDate)
 }
+
+// runeWidth returns the columns r takes in a terminal, 2 for wide runes like
+// CJK characters and emoji, 0 for combining marks and joiners.
+func runeWidth(r rune) int {
+	if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf, unicode.Variation_Selector) {
+		return 0
+	}
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+// visibleWidth returns the columns s takes in a terminal, without its color
+// escape sequences.
+func visibleWidth(s string) int {
+	n := 0
+	for _, r := range ansiRegex.ReplaceAllString(s, "") {
+		n += runeWidth(r)
+	}
+	return n
+}
+
+// Truncate shortens s to at most width visible characters, ending it with an
+// ellipsis. Color escape sequences are kept and do not count towards width.
+func Truncate(s string, width int) string {
+	if width <= 0 || visibleWidth(s) <= width {
+		return s
+	}
+
+	var b strings.Builder
+	colored := false
+	n := 0
+	for i := 0; i < len(s); {
+		if loc := ansiRegex.FindStringIndex(s[i:]); loc != nil && loc[0] == 0 {
+			b.WriteString(s[i : i+loc[1]])
+			i += loc[1]
+			colored = true
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if n+runeWidth(r) > width-1 {
+			break
+		}
+		b.WriteRune(r)
+		i += size
+		n += runeWidth(r)
+	}
+	b.WriteString(ellipsis)
+	if colored {
+		b.WriteString("\x1b[0m")
+	}
+	return b.String()
+}
+
+// OutputWidth returns the width the output should fit in, or 0 when it
+// should not be truncated.
+func OutputWidth(c *cli.Context) int {
+	if c.GlobalBool("no-truncate") || (outputFormat != "tsv" && outputFormat != "table") {
+		return 0
+	}
+	if width := c.GlobalInt("max-width"); width > 0 {
+		return width
+	}
+	if isTerminal(stdout) {
+		return terminalWidth()
+	}
+	return 0
+}
+
+// TruncateColumn truncates column col of records so that the table written by
+// TSVWriter or TableWriter fits in width.
+func TruncateColumn(records [][]string, col int, width int) {
+	if width <= 0 {
+		return
+	}
+
+	widths := map[int]int{}
+	for _, record := range records {
+		for i, field := range record {
+			if w := visibleWidth(field); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	available := width
+	if outputFormat == "table" {
+		// Borders and cell padding of TableWriter.
+		available -= 2*len(widths) + 2
+	}
+	for i, w := range widths {
+		if i != col {
+			available -= w + 1
+		}
+	}
+	if available < minContentWidth {
+		available = minContentWidth
+	}
+
+	for _, record := range records {
+		if col < len(record) {
+			record[col] = Truncate(record[col], available)
+		}
+	}
+}
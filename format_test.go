@@ -0,0 +1,83 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "short", Truncate("short", 10), "they should be equal")
+	assert.Equal(t, "long co…", Truncate("long content", 8), "they should be equal")
+	assert.Equal(t, "long content", Truncate("long content", 0), "they should be equal")
+	assert.Equal(t, "\x1b[4mlong co…\x1b[0m", Truncate("\x1b[4mlong content\x1b[0m", 8), "they should be equal")
+}
+
+func TestTruncateColumn(t *testing.T) {
+	records := [][]string{
+		{"1", "p1", "a very long task content"},
+		{"22", "p2", "short"},
+	}
+	TruncateColumn(records, 2, 20)
+	assert.Equal(t, "a very long t…", records[0][2], "they should be equal")
+	assert.Equal(t, "short", records[1][2], "they should be equal")
+}
+
+func TestAppColor(t *testing.T) {
+	assert.Equal(t, []color.Attribute{38, 5, 63}, AppColor("blue"), "they should be equal")
+	assert.Equal(t, []color.Attribute{38, 5, 167}, AppColor("red"), "they should be equal")
+	assert.Nil(t, AppColor("unknown"))
+}
+
+func TestProjectPathFormat(t *testing.T) {
+	store := testStore(t, `{"projects": [
+		{"id": "1", "name": "Work", "color": "blue"},
+		{"id": "2", "name": "ClientA", "parent_id": "1", "color": "red"}
+	]}`)
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+	assert.Equal(t, "#Work/ClientA", ProjectPathFormat("2", store, nil), "they should be equal")
+	assert.Equal(t, "#Work", ProjectPathFormat("1", store, nil), "they should be equal")
+	assert.Equal(t, "Unknown", ProjectPathFormat("3", store, nil), "they should be equal")
+}
+
+func TestParentsFormat(t *testing.T) {
+	store := testStore(t, `{"items": [
+		{"id": "1", "content": "Launch"},
+		{"id": "2", "content": "[Draft](https://example.com/draft)", "parent_id": "1"},
+		{"id": "3", "content": "Outline", "parent_id": "2"}
+	]}`)
+	assert.Equal(t, "Launch › Draft", ParentsFormat(store.FindItem("3"), store), "they should be equal")
+	assert.Equal(t, "", ParentsFormat(store.FindItem("1"), store), "they should be equal")
+}
+
+func TestDueDateColors(t *testing.T) {
+	now := time.Date(2020, 3, 3, 14, 0, 0, 0, time.Local)
+	for _, tc := range []struct {
+		due      time.Time
+		allDay   bool
+		expected []color.Attribute
+	}{
+		{time.Date(2020, 3, 2, 0, 0, 0, 0, time.Local), true, theme.Overdue},
+		{time.Date(2020, 3, 3, 0, 0, 0, 0, time.Local), true, theme.DueToday},
+		{time.Date(2020, 3, 3, 9, 0, 0, 0, time.Local), false, theme.Overdue},
+		{time.Date(2020, 3, 3, 18, 0, 0, 0, time.Local), false, theme.DueToday},
+		{time.Date(2020, 3, 4, 9, 0, 0, 0, time.Local), false, theme.DueSoon},
+		{time.Date(2020, 3, 5, 0, 0, 0, 0, time.Local), true, theme.DueLater},
+	} {
+		assert.Equal(t, tc.expected, dueDateColors(tc.due, tc.allDay, now), tc.due.String())
+	}
+}
+
+func TestVisibleWidth(t *testing.T) {
+	assert.Equal(t, 5, visibleWidth("\x1b[31mplain\x1b[0m"), "they should be equal")
+	assert.Equal(t, 4, visibleWidth("日本"), "they should be equal")
+	assert.Equal(t, 5, visibleWidth("🔁 💬"), "they should be equal")
+	assert.Equal(t, 2, visibleWidth("e\u0301e"), "they should be equal")
+	assert.Equal(t, "日本…", Truncate("日本語のタスク", 6), "they should be equal")
+	assert.Equal(t, "日本語…", Truncate("日本語のタスク", 7), "they should be equal")
+	assert.Equal(t, "cafe\u0301 a…", Truncate("cafe\u0301 au lait", 7), "they should be equal")
+}
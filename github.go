@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/urfave/cli"
+)
+
+// githubAPI is where the GitHub API is, changed by the tests.
+var githubAPI = "https://api.github.com"
+
+// githubMarker starts the comment linking a task back to its issue, by
+// which issues already imported are recognized.
+const githubMarker = "Imported from GitHub: "
+
+var (
+	githubRepoRegex     = regexp.MustCompile(`^[\w.-]+/[\w.-]+$`)
+	githubNextLinkRegex = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+	githubImportedRegex = regexp.MustCompile(regexp.QuoteMeta(githubMarker) + `(\S+)`)
+)
+
+type GitHubIssue struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	HTMLURL string `json:"html_url"`
+	// PullRequest is only set for pull requests, which the API lists as
+	// issues too.
+	PullRequest *struct{} `json:"pull_request"`
+}
+
+// githubIssues fetches the open issues of repo with label, all of them for
+// "", page by page.
+func githubIssues(ctx context.Context, repo string, label string, token string) ([]GitHubIssue, error) {
+	query := url.Values{"state": {"open"}, "per_page": {"100"}}
+	if label != "" {
+		query.Set("labels", label)
+	}
+	next := githubAPI + "/repos/" + repo + "/issues?" + query.Encode()
+
+	issues := []GitHubIssue{}
+	for next != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, next, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/vnd.github+json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, &Error{Code: "github_error", Message: fmt.Sprintf("GitHub answered %s for %s", resp.Status, repo), Hint: "check the repository, and set GITHUB_TOKEN to a token which can read it"}
+		}
+		var page []GitHubIssue
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range page {
+			if issue.PullRequest == nil {
+				issues = append(issues, issue)
+			}
+		}
+
+		next = ""
+		if match := githubNextLinkRegex.FindStringSubmatch(resp.Header.Get("Link")); match != nil {
+			next = match[1]
+		}
+	}
+	return issues, nil
+}
+
+// githubImportedPath is where the issues imported into the account of the
+// cache at cachePath are kept, as the comments saying so are left out of
+// syncs once their tasks are completed.
+func githubImportedPath(cachePath string) string {
+	return cachePath + ".github.json"
+}
+
+// importedIssueURLs returns the URLs of the issues the comments in store
+// say tasks were imported from.
+func importedIssueURLs(store *todoist.Store) map[string]bool {
+	urls := map[string]bool{}
+	for _, note := range store.Notes {
+		if note.IsDeleted {
+			continue
+		}
+		for _, match := range githubImportedRegex.FindAllStringSubmatch(note.Content, -1) {
+			urls[match[1]] = true
+		}
+	}
+	return urls
+}
+
+// githubCommands adds a task for each issue not in imported to the project
+// with projectID, the inbox for "", with a comment linking back to the
+// issue.
+func githubCommands(imported map[string]bool, issues []GitHubIssue, projectID string) (todoist.Commands, int) {
+	commands := todoist.Commands{}
+	skipped := 0
+	for _, issue := range issues {
+		if imported[issue.HTMLURL] {
+			skipped++
+			continue
+		}
+		add := todoist.NewCommand("item_add", todoist.Item{BaseItem: todoist.BaseItem{Content: issue.Title, HaveProjectID: todoist.HaveProjectID{ProjectID: projectID}}}.AddParam())
+		note := todoist.Note{ItemID: add.TempID, Content: githubMarker + issue.HTMLURL}
+		commands = append(commands, add, todoist.NewCommand("note_add", note.AddParam()))
+	}
+	return commands, skipped
+}
+
+func ImportGitHub(c *cli.Context) error {
+	client := GetClient(c)
+
+	repo := strings.TrimSuffix(c.String("repo"), "/")
+	if !githubRepoRegex.MatchString(repo) {
+		return &Error{Code: "invalid_argument", Message: fmt.Sprintf("invalid repository %q", repo), Hint: "give it with --repo as owner/name"}
+	}
+	projectID := ""
+	if name := c.String("project"); name != "" {
+		if projectID = client.Store.Projects.GetIDByName(name); projectID == "" {
+			return ProjectNotFound(name)
+		}
+	}
+
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		token = os.Getenv("GH_TOKEN")
+	}
+	issues, err := githubIssues(GetContext(c), repo, c.String("label"), token)
+	if err != nil {
+		return err
+	}
+
+	// Imports from other devices are known by their comments.
+	path := githubImportedPath(default_cache_path)
+	imported := importedIssueURLs(client.Store)
+	if err := readJSONFile(path, &imported); err != nil {
+		return err
+	}
+	commands, skipped := githubCommands(imported, issues, projectID)
+	if len(commands) > 0 {
+		if err := client.ExecCommands(GetContext(c), commands); err != nil {
+			return err
+		}
+	}
+	for _, issue := range issues {
+		imported[issue.HTMLURL] = true
+	}
+	if err := writeJSONFile(path, imported); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Imported %d issue(s), skipped %d imported before\n", len(commands)/2, skipped)
+	if len(commands) == 0 {
+		return nil
+	}
+	return Sync(c)
+}
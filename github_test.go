@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/sachaos/todoist/lib/todoisttest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitHubIssues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/name/issues" {
+			http.NotFound(w, r)
+			return
+		}
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"), "they should be equal")
+		assert.Equal(t, "bug", r.URL.Query().Get("labels"), "they should be equal")
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/repos/owner/name/issues?labels=bug&page=2>; rel="next", <http://%s/x>; rel="last"`, r.Host, r.Host))
+			fmt.Fprint(w, `[{"number": 1, "title": "Crash", "html_url": "https://github.com/owner/name/issues/1"},
+				{"number": 2, "title": "Fix crash", "html_url": "https://github.com/owner/name/pull/2", "pull_request": {}}]`)
+			return
+		}
+		fmt.Fprint(w, `[{"number": 3, "title": "Typo", "html_url": "https://github.com/owner/name/issues/3"}]`)
+	}))
+	defer server.Close()
+
+	saved := githubAPI
+	githubAPI = server.URL
+	defer func() { githubAPI = saved }()
+
+	issues, err := githubIssues(context.Background(), "owner/name", "bug", "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(issues), "they should be equal")
+	assert.Equal(t, "Typo", issues[1].Title, "they should be equal")
+
+	_, err = githubIssues(context.Background(), "owner/missing", "bug", "secret")
+	assert.Error(t, err)
+}
+
+func TestGitHubCommands(t *testing.T) {
+	store := testStore(t, `{
+		"items": [{"id": "10", "content": "Crash"}],
+		"notes": [
+			{"id": "20", "item_id": "10", "content": "Imported from GitHub: https://github.com/owner/name/issues/1"},
+			{"id": "21", "item_id": "10", "content": "Imported from GitHub: https://github.com/owner/name/issues/2", "is_deleted": true}
+		]
+	}`)
+	issues := []GitHubIssue{
+		{Number: 1, Title: "Crash", HTMLURL: "https://github.com/owner/name/issues/1"},
+		{Number: 12, Title: "Hang", HTMLURL: "https://github.com/owner/name/issues/12"},
+	}
+
+	commands, skipped := githubCommands(importedIssueURLs(store), issues, "2")
+	assert.Equal(t, 1, skipped, "they should be equal")
+	assert.Equal(t, 2, len(commands), "they should be equal")
+	add := commands[0].Args.(map[string]interface{})
+	assert.Equal(t, "Hang", add["content"], "they should be equal")
+	assert.Equal(t, "2", add["project_id"], "they should be equal")
+	note := commands[1].Args.(map[string]interface{})
+	assert.Equal(t, commands[0].TempID, note["item_id"], "they should be equal")
+	assert.Equal(t, "Imported from GitHub: https://github.com/owner/name/issues/12", note["content"], "they should be equal")
+}
+
+func TestImportGitHubAfterClose(t *testing.T) {
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"number": 1, "title": "Crash", "html_url": "https://github.com/owner/name/issues/1"}]`)
+	}))
+	defer github.Close()
+	saved := githubAPI
+	githubAPI = github.URL
+	defer func() { githubAPI = saved }()
+
+	server := todoisttest.NewServer(t, `{
+		"user": {"id": "1", "inbox_project_id": "1"},
+		"projects": [{"id": "1", "name": "Inbox", "inbox_project": true}]
+	}`)
+	run := runTodoist(t, server)
+	_, err := run("sync")
+	assert.NoError(t, err)
+	_, err = run("import", "github", "--repo", "owner/name")
+	assert.NoError(t, err)
+
+	var store todoist.Store
+	assert.NoError(t, ReadCache(default_cache_path, &store))
+	if !assert.Equal(t, 1, len(store.Items), "they should be equal") {
+		return
+	}
+	_, err = run("close", store.Items[0].ID)
+	assert.NoError(t, err)
+
+	// Full syncs leave completed tasks and their comments out.
+	assert.NoError(t, ReadCache(default_cache_path, &store))
+	store.Items, store.Notes = todoist.Items{}, todoist.Notes{}
+	assert.NoError(t, WriteCache(default_cache_path, &store))
+
+	_, err = run("import", "github", "--repo", "owner/name")
+	assert.NoError(t, err)
+	client := server.NewClient()
+	assert.NoError(t, client.Sync(context.Background()))
+	crashes := 0
+	for _, item := range client.Store.Items {
+		if strings.Contains(item.Content, "Crash") {
+			crashes++
+		}
+	}
+	assert.Equal(t, 1, crashes, "they should be equal")
+}
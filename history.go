@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/urfave/cli"
+)
+
+// historyPath returns the path of the history of the account of the cache
+// at cachePath, kept next to it as ids differ between accounts. The history
+// only grows, so it is kept as one JSON entry per line which can be appended
+// to without reading it first.
+func historyPath(cachePath string) string {
+	return cachePath + ".history.jsonl"
+}
+
+// HistoryChange is a change made by one command sent to the API.
+type HistoryChange struct {
+	Type   string `json:"type"`
+	ID     string `json:"id"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// HistoryEntry is a batch of changes made by one invocation of todoist.
+type HistoryEntry struct {
+	Time    time.Time       `json:"time"`
+	Command string          `json:"command"`
+	Changes []HistoryChange `json:"changes"`
+}
+
+// summarize describes the object of a command type with id in store.
+func summarize(store *todoist.Store, commandType string, id string) string {
+	switch {
+	case strings.HasPrefix(commandType, "item_"):
+		item := store.FindItem(id)
+		if item == nil {
+			return ""
+		}
+		summary := item.Content
+		if item.Due != nil {
+			summary += " due " + item.Due.Date
+		}
+		if project := store.FindProject(item.ProjectID); project != nil {
+			summary += " #" + project.Name
+		}
+		summary += fmt.Sprintf(" p%d", priorityMapping[item.Priority])
+		if item.Checked {
+			summary += " (done)"
+		}
+		return summary
+	case strings.HasPrefix(commandType, "project_"):
+		if project := store.FindProject(id); project != nil {
+			return "#" + project.Name
+		}
+	case strings.HasPrefix(commandType, "label_"):
+		if label := store.FindLabel(id); label != nil {
+			return "@" + label.Name
+		}
+	}
+	return ""
+}
+
+func commandIDs(command todoist.Command, tempIDs map[string]string) []string {
+	if id, ok := tempIDs[command.TempID]; ok && strings.HasSuffix(command.Type, "_add") {
+		return []string{id}
+	}
+	buf, err := json.Marshal(command.Args)
+	if err != nil {
+		return nil
+	}
+	var args struct {
+		ID  string   `json:"id"`
+		IDs []string `json:"ids"`
+	}
+	json.Unmarshal(buf, &args)
+	if args.ID != "" {
+		return []string{args.ID}
+	}
+	return args.IDs
+}
+
+// recordHistory returns a todoist.Client.Executed hook of client appending
+// the changes to the history, with args as the command.
+func recordHistory(client *todoist.Client, args []string) func(todoist.Commands, map[string]string, todoist.Store) {
+	return func(commands todoist.Commands, tempIDs map[string]string, before todoist.Store) {
+		entry := HistoryEntry{Time: time.Now(), Command: strings.Join(args, " ")}
+		for _, command := range commands {
+			for _, id := range commandIDs(command, tempIDs) {
+				entry.Changes = append(entry.Changes, HistoryChange{
+					Type:   command.Type,
+					ID:     id,
+					Before: summarize(&before, command.Type, id),
+					After:  summarize(client.Store, command.Type, id),
+				})
+			}
+		}
+		if err := appendHistory(entry); err != nil {
+			fmt.Fprintln(os.Stderr, "writing history failed:", err)
+		}
+	}
+}
+
+func appendHistory(entry HistoryEntry) error {
+	return appendJSONLine(historyPath(default_cache_path), entry)
+}
+
+// writeHistory replaces the history with entries.
+func writeHistory(entries []HistoryEntry) error {
+	buf := []byte{}
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		buf = append(append(buf, line...), '\n')
+	}
+	return writeFileAtomic(historyPath(default_cache_path), buf)
+}
+
+func readHistory() ([]HistoryEntry, error) {
+	f, err := os.Open(historyPath(default_cache_path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entries := []HistoryEntry{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// Skip a line cut short by a crash.
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+func History(c *cli.Context) error {
+	entries, err := readHistory()
+	if err != nil {
+		return err
+	}
+	if limit := c.Int("limit"); limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+
+	defer writer.Flush()
+
+	writer.WriteHeader([]string{"Time", "Command", "Change", "ID", "Before", "After"})
+
+	for _, entry := range entries {
+		for _, change := range entry.Changes {
+			writer.Write([]string{
+				entry.Time.Local().Format(ShortDateTimeFormat),
+				entry.Command,
+				change.Type,
+				change.ID,
+				change.Before,
+				change.After,
+			})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sachaos/todoist/lib"
+)
+
+func TestHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "todoist")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	defer func(path string) { default_cache_path = path }(default_cache_path)
+	default_cache_path = filepath.Join(dir, "cache.json")
+
+	sandbox, err := todoist.NewSandbox([]byte(`{
+		"projects": [{"id": "1", "name": "Inbox", "parent_id": null}],
+		"items": [{"id": "10", "project_id": "1", "content": "old", "priority": 1, "parent_id": null, "checked": false}]
+	}`))
+	assert.NoError(t, err)
+	client := todoist.NewClient(&todoist.Config{})
+	client.Transport = sandbox
+	client.Store = &todoist.Store{}
+	ctx := context.Background()
+	assert.NoError(t, client.Sync(ctx))
+	client.Executed = recordHistory(client, []string{"todoist", "modify"})
+
+	add := todoist.NewCommand("item_add", map[string]interface{}{"content": "new", "project_id": "1"})
+	update := todoist.NewCommand("item_update", map[string]interface{}{"id": "10", "content": "renamed"})
+	assert.NoError(t, client.ExecCommands(ctx, todoist.Commands{add, update}))
+
+	entries, err := readHistory()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(entries), "they should be equal")
+	assert.Equal(t, "todoist modify", entries[0].Command, "they should be equal")
+	assert.Equal(t, []HistoryChange{
+		{Type: "item_add", ID: "11", After: "new #Inbox p4"},
+		{Type: "item_update", ID: "10", Before: "old #Inbox p4", After: "renamed #Inbox p4"},
+	}, entries[0].Changes, "they should be equal")
+
+	// Another account, with a cache of its own, has a history of its own.
+	default_cache_path = filepath.Join(dir, "other.json")
+	entries, err = readHistory()
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/sachaos/todoist/lib"
+)
+
+// IconSet is the glyphs marking what a task has, for the icons column.
+type IconSet struct {
+	// Priorities are indexed by API priority, 4 being p1. p4 has none.
+	Priorities  [5]string
+	Recurring   string
+	Reminder    string
+	Comments    string
+	Attachments string
+}
+
+// nerdIcons need a patched font from https://www.nerdfonts.com.
+var nerdIcons = &IconSet{
+	// nf-fa-flag, nf-fa-refresh, nf-fa-bell, nf-fa-comment and
+	// nf-fa-paperclip.
+	Priorities:  [5]string{2: "\uf024", 3: "\uf024", 4: "\uf024"},
+	Recurring:   "\uf021",
+	Reminder:    "\uf0f3",
+	Comments:    "\uf075",
+	Attachments: "\uf0c6",
+}
+
+var emojiIcons = &IconSet{
+	Priorities:  [5]string{2: "🔵", 3: "🟠", 4: "🔴"},
+	Recurring:   "🔁",
+	Reminder:    "⏰",
+	Comments:    "💬",
+	Attachments: "📎",
+}
+
+var asciiIcons = &IconSet{
+	Priorities:  [5]string{2: "!", 3: "!!", 4: "!!!"},
+	Recurring:   "~",
+	Reminder:    "^",
+	Comments:    "=",
+	Attachments: "+",
+}
+
+// icons is the icon set of this invocation, nil without icons.
+var icons *IconSet
+
+// utf8Locale reports whether the locale of the environment is UTF-8, taking
+// it to be when none is set, like on Windows.
+func utf8Locale() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := os.Getenv(name); locale != "" {
+			locale = strings.ToLower(locale)
+			return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+		}
+	}
+	return true
+}
+
+// IconSetFor picks the icon set, nil unless show is set or a set is given
+// by flag. The set of the flag wins over that of the config file, and
+// without either emoji are used. The glyphs fall back to ASCII in a locale
+// which is not UTF-8.
+func IconSetFor(show bool, flag string, config string) (*IconSet, error) {
+	if !show && flag == "" {
+		return nil, nil
+	}
+	set := flag
+	if set == "" {
+		set = config
+	}
+
+	switch set {
+	case "nerd", "emoji", "":
+		if !utf8Locale() {
+			return asciiIcons, nil
+		}
+		if set == "nerd" {
+			return nerdIcons, nil
+		}
+		return emojiIcons, nil
+	case "ascii":
+		return asciiIcons, nil
+	default:
+		return nil, &Error{Code: "invalid_argument", Message: fmt.Sprintf("invalid icon set %q", set), Hint: "use nerd, emoji or ascii"}
+	}
+}
+
+// IconsFormat returns the glyphs of what item has, among its priority,
+// recurrence, reminders, comments and attachments.
+func IconsFormat(item *todoist.Item, reminders int, comments int, attachments int) string {
+	if icons == nil {
+		return ""
+	}
+	glyphs := []string{}
+	if glyph := icons.Priorities[item.Priority]; glyph != "" {
+		glyphs = append(glyphs, color.New(theme.Priorities[item.Priority]...).SprintFunc()(glyph))
+	}
+	if item.Due != nil && item.Due.IsRecurring {
+		glyphs = append(glyphs, icons.Recurring)
+	}
+	if reminders > 0 {
+		glyphs = append(glyphs, icons.Reminder)
+	}
+	if comments > 0 {
+		glyphs = append(glyphs, icons.Comments)
+	}
+	if attachments > 0 {
+		glyphs = append(glyphs, icons.Attachments)
+	}
+	return strings.Join(glyphs, " ")
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/sachaos/todoist/lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIconSetFor(t *testing.T) {
+	os.Setenv("LC_ALL", "en_US.UTF-8")
+	defer os.Unsetenv("LC_ALL")
+
+	set, err := IconSetFor(false, "", "nerd")
+	assert.NoError(t, err)
+	assert.Nil(t, set)
+
+	set, err = IconSetFor(true, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, emojiIcons, set, "they should be equal")
+
+	set, err = IconSetFor(true, "", "nerd")
+	assert.NoError(t, err)
+	assert.Equal(t, nerdIcons, set, "they should be equal")
+
+	set, err = IconSetFor(false, "emoji", "nerd")
+	assert.NoError(t, err)
+	assert.Equal(t, emojiIcons, set, "they should be equal")
+
+	os.Setenv("LC_ALL", "C")
+	set, err = IconSetFor(true, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, asciiIcons, set, "they should be equal")
+
+	_, err = IconSetFor(false, "fancy", "")
+	assert.Equal(t, "invalid_argument", AsError(err).Code, "they should be equal")
+}
+
+func TestIconsFormat(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	saved := icons
+	icons = asciiIcons
+	defer func() { color.NoColor, icons = noColor, saved }()
+
+	item := &todoist.Item{Priority: 4, Due: &todoist.Due{Date: "2020-03-02", IsRecurring: true}}
+	assert.Equal(t, "!!! ~ ^ = +", IconsFormat(item, 1, 2, 1), "they should be equal")
+	assert.Equal(t, "", IconsFormat(&todoist.Item{Priority: 1}, 0, 0, 0), "they should be equal")
+	assert.Equal(t, "! =", IconsFormat(&todoist.Item{Priority: 2}, 0, 1, 0), "they should be equal")
+}
@@ -12,3 +12,32 @@ func Karma(c *cli.Context) error {
 	fmt.Println(client.Store.User.Karma)
 	return nil
 }
+
+// KarmaVacation shows or switches vacation mode, which keeps the karma
+// streaks while away.
+func KarmaVacation(c *cli.Context) error {
+	client := GetClient(c)
+
+	var on bool
+	switch c.Args().First() {
+	case "":
+		if client.Store.User.VacationMode() {
+			fmt.Println("on")
+		} else {
+			fmt.Println("off")
+		}
+		return nil
+	case "on":
+		on = true
+	case "off":
+		on = false
+	default:
+		return &Error{Code: "invalid_argument", Message: fmt.Sprintf("unknown vacation mode %q", c.Args().First()), Hint: "use on or off"}
+	}
+
+	if err := client.SetVacationMode(GetContext(c), on); err != nil {
+		return err
+	}
+
+	return Sync(c)
+}
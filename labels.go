@@ -1,9 +1,16 @@
 package main
 
 import (
+	"fmt"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"text/tabwriter"
+	"time"
 
+	"github.com/fatih/color"
+	"github.com/sachaos/todoist/lib"
 	"github.com/urfave/cli"
 )
 
@@ -15,13 +22,188 @@ func Labels(c *cli.Context) error {
 
 	defer writer.Flush()
 
-	if c.GlobalBool("header") {
-		writer.Write([]string{"ID", "Name"})
-	}
+	writer.WriteHeader([]string{"ID", "Name"})
 
 	for _, label := range client.Store.Labels {
-		writer.Write([]string{IdFormat(label), "@" + label.Name})
+		if c.Bool("favorites") && !label.IsFavorite {
+			continue
+		}
+		name := "@" + label.Name
+		if attributes := AppColor(label.Color); attributes != nil {
+			name = color.New(attributes...).SprintFunc()(name)
+		}
+		writer.Write([]string{IdFormat(label), name + FavoriteFormat(label.IsFavorite)})
+	}
+
+	return nil
+}
+
+func SetLabelColor(c *cli.Context) error {
+	client := GetClient(c)
+
+	if len(c.Args()) != 2 {
+		return ArgumentRequired
+	}
+	name, colorName := strings.TrimPrefix(c.Args().Get(0), "@"), c.Args().Get(1)
+
+	if AppColor(colorName) == nil {
+		return InvalidColor(colorName)
+	}
+	label := client.Store.FindLabel(client.Store.Labels.GetIDByName(name))
+	if label == nil {
+		return LabelNotFound(name)
+	}
+
+	updated := *label
+	updated.Color = colorName
+	if err := client.UpdateLabel(GetContext(c), updated); err != nil {
+		return err
+	}
+
+	return Sync(c)
+}
+
+// unusedLabels returns the labels which no open task has. Labels a saved
+// filter refers to are kept, as deleting them would break the filter.
+func unusedLabels(store *todoist.Store) todoist.Labels {
+	used := map[string]bool{}
+	for _, item := range store.Items {
+		if item.Checked || item.IsDeleted {
+			continue
+		}
+		for _, name := range item.LabelNames {
+			used[name] = true
+		}
+	}
+
+	unused := todoist.Labels{}
+	for _, label := range store.Labels {
+		if used[label.Name] || label.IsDeleted {
+			continue
+		}
+		inFilter := false
+		for _, filter := range store.Filters {
+			if strings.Contains(strings.ToLower(filter.Query), "@"+strings.ToLower(label.Name)) {
+				inFilter = true
+			}
+		}
+		if !inFilter {
+			unused = append(unused, label)
+		}
+	}
+	return unused
+}
+
+func PruneLabels(c *cli.Context) error {
+	client := GetClient(c)
+
+	unused := unusedLabels(client.Store)
+	if len(unused) == 0 {
+		fmt.Println("Every label is used by an open task.")
+		return nil
 	}
 
+	ids := []string{}
+	for _, label := range unused {
+		fmt.Fprintf(os.Stderr, "  @%s\n", label.Name)
+		ids = append(ids, label.ID)
+	}
+	if err := confirm(fmt.Sprintf("Delete %d label(s) no open task has?", len(ids)), c.Bool("yes")); err != nil {
+		return err
+	}
+
+	if err := client.DeleteLabel(GetContext(c), ids); err != nil {
+		return err
+	}
+	return Sync(c)
+}
+
+// labelStatsDays is how far back labels stats counts completed tasks.
+const labelStatsDays = 30
+
+// LabelStat is how much a label is used: its open and overdue tasks, and the
+// tasks completed with it.
+type LabelStat struct {
+	Name      string
+	Open      int
+	Overdue   int
+	Completed int
+}
+
+// LabelStats returns the use of every label, those of the store and those
+// only tasks have, at now. Labels completed with most come first, then those
+// with most open tasks.
+func LabelStats(store *todoist.Store, completed todoist.CompletedItems, now time.Time) []*LabelStat {
+	stats := map[string]*LabelStat{}
+	stat := func(name string) *LabelStat {
+		if _, ok := stats[name]; !ok {
+			stats[name] = &LabelStat{Name: name}
+		}
+		return stats[name]
+	}
+	for _, label := range store.Labels {
+		if !label.IsDeleted {
+			stat(label.Name)
+		}
+	}
+	for _, item := range store.Items {
+		if item.Checked || item.IsDeleted {
+			continue
+		}
+		// Due dates without time are all day, whatever all_day says.
+		overdue := item.Due != nil && dueClass(item.DateTime(), !strings.Contains(item.Due.Date, "T"), now) == "overdue"
+		for _, name := range item.LabelNames {
+			stat(name).Open++
+			if overdue {
+				stat(name).Overdue++
+			}
+		}
+	}
+	for _, item := range completed {
+		for _, name := range item.LabelNames {
+			stat(name).Completed++
+		}
+	}
+
+	sorted := []*LabelStat{}
+	for _, s := range stats {
+		sorted = append(sorted, s)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Completed != b.Completed {
+			return a.Completed > b.Completed
+		}
+		if a.Open != b.Open {
+			return a.Open > b.Open
+		}
+		return a.Name < b.Name
+	})
+	return sorted
+}
+
+func LabelsStats(c *cli.Context) error {
+	client := GetClient(c)
+
+	now := time.Now()
+	var completed todoist.Completed
+	err := client.CompletedBetween(GetContext(c), now.AddDate(0, 0, -labelStatsDays), now, &completed)
+	// Completed tasks are only available to premium users, the others are
+	// shown the rest.
+	premiumOnly := todoist.IsPremiumOnly(err)
+	if err != nil && !premiumOnly {
+		return err
+	}
+
+	defer writer.Flush()
+
+	writer.WriteHeader([]string{"Name", "Open", "Overdue", "Completed"})
+	for _, stat := range LabelStats(client.Store, completed.Items, now) {
+		done := strconv.Itoa(stat.Completed)
+		if premiumOnly {
+			done = "-"
+		}
+		writer.Write([]string{"@" + stat.Name, strconv.Itoa(stat.Open), strconv.Itoa(stat.Overdue), done})
+	}
 	return nil
 }
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnusedLabels(t *testing.T) {
+	store := testStore(t, `{
+		"items": [
+			{"id": "1", "content": "open", "labels": ["office"]},
+			{"id": "2", "content": "done", "labels": ["waiting"], "checked": true}
+		],
+		"labels": [
+			{"id": "11", "name": "office"},
+			{"id": "12", "name": "waiting"},
+			{"id": "13", "name": "someday"},
+			{"id": "14", "name": "errand"}
+		],
+		"filters": [
+			{"id": "21", "name": "Errands", "query": "@Errand & today"}
+		]
+	}`)
+
+	names := []string{}
+	for _, label := range unusedLabels(store) {
+		names = append(names, label.Name)
+	}
+	assert.Equal(t, []string{"waiting", "someday"}, names, "they should be equal")
+}
+
+func TestLabelStats(t *testing.T) {
+	store := testStore(t, `{
+		"items": [
+			{"id": "1", "content": "late", "labels": ["office"], "due": {"date": "2020-01-05"}},
+			{"id": "2", "content": "later today", "labels": ["office", "call"], "due": {"date": "2020-01-06T15:00:00"}},
+			{"id": "3", "content": "earlier today", "labels": ["call"], "due": {"date": "2020-01-06T09:00:00"}},
+			{"id": "4", "content": "shared", "labels": ["team"]},
+			{"id": "5", "content": "done", "labels": ["someday"], "checked": true}
+		],
+		"labels": [
+			{"id": "11", "name": "office"},
+			{"id": "12", "name": "call"},
+			{"id": "13", "name": "someday"},
+			{"id": "14", "name": "gone", "is_deleted": true}
+		]
+	}`)
+	completed := todoist.CompletedItems{
+		{LabelNames: []string{"call"}},
+		{LabelNames: []string{"call", "someday"}},
+	}
+
+	stats := []LabelStat{}
+	for _, stat := range LabelStats(store, completed, time.Date(2020, 1, 6, 12, 0, 0, 0, time.Local)) {
+		stats = append(stats, *stat)
+	}
+	assert.Equal(t, []LabelStat{
+		{Name: "call", Open: 2, Overdue: 1, Completed: 2},
+		{Name: "someday", Completed: 1},
+		{Name: "office", Open: 2, Overdue: 1},
+		{Name: "team", Open: 1},
+	}, stats, "they should be equal")
+}
@@ -0,0 +1,51 @@
+package todoist
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Event is an entry of the activity log, like a task added or completed.
+type Event struct {
+	ID              string `json:"id"`
+	ObjectType      string `json:"object_type"`
+	ObjectID        string `json:"object_id"`
+	EventType       string `json:"event_type"`
+	EventDate       string `json:"event_date"`
+	ParentProjectID string `json:"parent_project_id"`
+}
+
+func (event Event) DateTime() time.Time {
+	t, _ := time.Parse(time.RFC3339, event.EventDate)
+	return t
+}
+
+type Activity struct {
+	Events     []Event `json:"results"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+// ActivityBetween fetches every page of the events of objectType, like
+// "item", and eventType, like "added", from since until until.
+func (c *Client) ActivityBetween(ctx context.Context, objectType string, eventType string, since time.Time, until time.Time, r *Activity) error {
+	params := url.Values{
+		"object_type": {objectType},
+		"event_type":  {eventType},
+		"date_from":   {since.UTC().Format(RFC3339DateTime)},
+		"date_to":     {until.UTC().Format(RFC3339DateTime)},
+		"limit":       {"100"},
+	}
+	for n := 1; ; n++ {
+		var page Activity
+		if err := c.doApiPage(ctx, http.MethodGet, "activities", params, n, &page); err != nil {
+			return err
+		}
+		r.Events = append(r.Events, page.Events...)
+		if page.NextCursor == nil || *page.NextCursor == "" {
+			return nil
+		}
+		params.Set("cursor", *page.NextCursor)
+	}
+}
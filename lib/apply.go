@@ -0,0 +1,403 @@
+package todoist
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// commandResources maps the prefix of a command type to the resource it
+// changes.
+var commandResources = map[string]string{
+	"item":     "items",
+	"project":  "projects",
+	"label":    "labels",
+	"filter":   "filters",
+	"note":     "notes",
+	"reminder": "reminders",
+	"section":  "sections",
+}
+
+// commandState applies commands to a sync response decoded into generic
+// JSON values. It backs both the sandbox and the local updates of the store.
+type commandState struct {
+	data    map[string]interface{}
+	tempIDs map[string]string
+	// newID returns the id of the object added by the command with tempID.
+	newID func(tempID string) string
+	// guessDates makes date strings which can't be understood locally due
+	// today, like Todoist does for most phrases, instead of leaving the due
+	// date as it is.
+	guessDates bool
+}
+
+func decodeJSON(r *bytes.Reader, v interface{}) error {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+func jsonID(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+func (s *commandState) objects(resource string) []map[string]interface{} {
+	list, _ := s.data[resource].([]interface{})
+	objects := []map[string]interface{}{}
+	for _, v := range list {
+		if object, ok := v.(map[string]interface{}); ok {
+			objects = append(objects, object)
+		}
+	}
+	return objects
+}
+
+func (s *commandState) setObjects(resource string, objects []map[string]interface{}) {
+	list := make([]interface{}, len(objects))
+	for i, object := range objects {
+		list[i] = object
+	}
+	s.data[resource] = list
+}
+
+func (s *commandState) find(resource string, id string) map[string]interface{} {
+	for _, object := range s.objects(resource) {
+		if jsonID(object["id"]) == id {
+			return object
+		}
+	}
+	return nil
+}
+
+// resolve replaces temp ids in args with the ids they were given.
+func (s *commandState) resolve(args map[string]interface{}) {
+	for key, value := range args {
+		if str, ok := value.(string); ok {
+			if id, ok := s.tempIDs[str]; ok {
+				args[key] = id
+			}
+		}
+	}
+}
+
+// localDate returns the date, or date and time, dateString stands for if it
+// is one of the few forms which can be understood without the API.
+func localDate(dateString string) (string, bool) {
+	now := time.Now()
+	switch strings.ToLower(dateString) {
+	case "today", "tod":
+		return now.Format(RFC3339Date), true
+	case "tomorrow", "tom":
+		return now.AddDate(0, 0, 1).Format(RFC3339Date), true
+	}
+	for _, layout := range []string{RFC3339Date, RFC3339DateTime, "2006/01/02", "2006/01/02 15:04", "2006-01-02 15:04"} {
+		if t, err := time.ParseInLocation(layout, dateString, time.Local); err == nil {
+			if strings.Contains(layout, "15") {
+				return t.Format(RFC3339DateTime), true
+			}
+			return t.Format(RFC3339Date), true
+		}
+	}
+	return "", false
+}
+
+func (s *commandState) update(object map[string]interface{}, args map[string]interface{}) {
+	for key, value := range args {
+		if key == "id" || key == "ids" {
+			continue
+		}
+		// A due date given only by its string has its date worked out
+		// here, as far as that can be done without the API.
+		if due, ok := value.(map[string]interface{}); ok && key == "due" && due["date"] == nil {
+			dateString := fmt.Sprint(due["string"])
+			date, ok := localDate(dateString)
+			if !ok && !s.guessDates {
+				continue
+			}
+			if !ok {
+				date = time.Now().Format(RFC3339Date)
+			}
+			lang, ok := due["lang"]
+			if !ok {
+				lang = "en"
+			}
+			object["due"] = map[string]interface{}{
+				"date":         date,
+				"string":       dateString,
+				"is_recurring": IsRecurringDateString(dateString),
+				"lang":         lang,
+			}
+			continue
+		}
+		object[key] = value
+	}
+}
+
+func (s *commandState) remove(resource string, id string) {
+	removed := map[string]bool{id: true}
+	objects := s.objects(resource)
+	// Children of removed items are removed as well.
+	for changed := true; changed; {
+		changed = false
+		for _, object := range objects {
+			objectID := jsonID(object["id"])
+			if !removed[objectID] && removed[jsonID(object["parent_id"])] {
+				removed[objectID] = true
+				changed = true
+			}
+		}
+	}
+	kept := []map[string]interface{}{}
+	for _, object := range objects {
+		if !removed[jsonID(object["id"])] {
+			kept = append(kept, object)
+		}
+	}
+	s.setObjects(resource, kept)
+}
+
+func (s *commandState) add(resource string, tempID string, args map[string]interface{}) map[string]interface{} {
+	id := s.newID(tempID)
+	object := map[string]interface{}{"id": id, "parent_id": nil, "is_deleted": false}
+	if resource == "items" {
+		object["checked"] = false
+		object["priority"] = 1
+		object["labels"] = []interface{}{}
+		object["added_at"] = time.Now().UTC().Format(time.RFC3339)
+	}
+	s.update(object, args)
+	if resource == "items" && object["project_id"] == nil {
+		if user, ok := s.data["user"].(map[string]interface{}); ok {
+			object["project_id"] = user["inbox_project_id"]
+		}
+	}
+	if tempID != "" {
+		s.tempIDs[tempID] = id
+	}
+	s.setObjects(resource, append(s.objects(resource), object))
+	return object
+}
+
+func (s *commandState) exec(command Command) error {
+	args, _ := command.Args.(map[string]interface{})
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	s.resolve(args)
+
+	// The v1 API takes due dates as due objects only.
+	if _, ok := args["date_string"]; ok {
+		return fmt.Errorf("date_string is not an argument of %s, due is", command.Type)
+	}
+
+	if command.Type == "update_goals" {
+		user, _ := s.data["user"].(map[string]interface{})
+		if user == nil {
+			return fmt.Errorf("user not found")
+		}
+		features, _ := user["features"].(map[string]interface{})
+		if features == nil {
+			features = map[string]interface{}{}
+			user["features"] = features
+		}
+		if mode, ok := args["vacation_mode"]; ok {
+			features["karma_vacation"] = jsonID(mode) == "1"
+		}
+		return nil
+	}
+
+	i := strings.Index(command.Type, "_")
+	if i < 0 {
+		return fmt.Errorf("unknown command %s", command.Type)
+	}
+	resource, ok := commandResources[command.Type[:i]]
+	if !ok {
+		return fmt.Errorf("unknown command %s", command.Type)
+	}
+	action := command.Type[i+1:]
+
+	if action == "add" {
+		s.add(resource, command.TempID, args)
+		return nil
+	}
+
+	ids := []string{}
+	if list, ok := args["ids"].([]interface{}); ok {
+		for _, id := range list {
+			ids = append(ids, jsonID(id))
+		}
+	} else {
+		ids = append(ids, jsonID(args["id"]))
+	}
+
+	for _, id := range ids {
+		object := s.find(resource, id)
+		if object == nil {
+			return fmt.Errorf("%s %s not found", resource, id)
+		}
+		switch action {
+		case "update":
+			s.update(object, args)
+		case "delete":
+			s.remove(resource, id)
+		case "close", "complete":
+			object["checked"] = true
+			object["completed_at"] = time.Now().UTC().Format(time.RFC3339)
+		case "uncomplete":
+			object["checked"] = false
+		case "move":
+			if projectID, ok := args["project_id"]; ok {
+				if s.find("projects", jsonID(projectID)) == nil {
+					return fmt.Errorf("project %v not found", projectID)
+				}
+				object["project_id"] = projectID
+				object["parent_id"] = nil
+			}
+			if parentID, ok := args["parent_id"]; ok {
+				object["parent_id"] = parentID
+			}
+			if sectionID, ok := args["section_id"]; ok {
+				object["section_id"] = sectionID
+			}
+		default:
+			return fmt.Errorf("unknown command %s", command.Type)
+		}
+	}
+	return nil
+}
+
+// Apply makes the changes of commands, which the API has accepted, to the
+// store, so that it is up to date without syncing. tempIDs maps the temp ids
+// of the commands to the ids the API gave the added objects. What can't be
+// known locally, like the next date of a recurring task or the date of most
+// date strings, is left for the next sync.
+func (s *Store) Apply(commands Commands, tempIDs map[string]string) error {
+	buf, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	state := &commandState{data: map[string]interface{}{}, tempIDs: map[string]string{}}
+	for tempID, id := range tempIDs {
+		state.tempIDs[tempID] = id
+	}
+	state.newID = func(tempID string) string {
+		if id, ok := tempIDs[tempID]; ok {
+			return id
+		}
+		return tempID
+	}
+	if err := decodeJSON(bytes.NewReader(buf), &state.data); err != nil {
+		return err
+	}
+
+	for _, command := range commands {
+		// The arguments are changed by resolving temp ids, so work on a copy.
+		var args interface{}
+		buf, err := json.Marshal(command.Args)
+		if err != nil {
+			return err
+		}
+		if err := decodeJSON(bytes.NewReader(buf), &args); err != nil {
+			return err
+		}
+		command.Args = args
+		if fields, ok := args.(map[string]interface{}); ok && command.Type == "item_close" {
+			if item := s.FindItem(jsonID(fields["id"])); item != nil && item.Due != nil && item.Due.IsRecurring {
+				// The API moves it to the next date instead.
+				continue
+			}
+		}
+		// Objects may have been changed by someone else since the last
+		// sync, the sync brings the store back in line then.
+		state.exec(command)
+	}
+
+	buf, err = json.Marshal(state.data)
+	if err != nil {
+		return err
+	}
+	var store Store
+	if err := json.Unmarshal(buf, &store); err != nil {
+		return err
+	}
+	*s = store
+	s.ConstructItemTree()
+	return nil
+}
+
+// Merge updates the store with the response of an incremental sync, which
+// has only the objects changed since the sync token: those are replaced by
+// id, or removed when deleted, and anything else, like the user or the new
+// sync token, is replaced. A full sync replaces the whole store.
+func (s *Store) Merge(changes []byte) error {
+	var changed map[string]interface{}
+	if err := decodeJSON(bytes.NewReader(changes), &changed); err != nil {
+		return err
+	}
+	if changed["full_sync"] == true {
+		var store Store
+		if err := json.Unmarshal(changes, &store); err != nil {
+			return err
+		}
+		*s = store
+		return nil
+	}
+
+	buf, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	state := &commandState{data: map[string]interface{}{}}
+	if err := decodeJSON(bytes.NewReader(buf), &state.data); err != nil {
+		return err
+	}
+	for resource, value := range changed {
+		list, ok := value.([]interface{})
+		if !ok {
+			state.data[resource] = value
+			continue
+		}
+		objects := state.objects(resource)
+		for _, v := range list {
+			object, ok := v.(map[string]interface{})
+			if !ok || object["id"] == nil {
+				// Like the collaborator states, which have no id and are
+				// only ever sent whole.
+				state.data[resource] = list
+				break
+			}
+			// Changed objects keep their place, as the order of the items
+			// is that of the tree.
+			i := 0
+			for i < len(objects) && jsonID(objects[i]["id"]) != jsonID(object["id"]) {
+				i++
+			}
+			switch {
+			case object["is_deleted"] == true && i < len(objects):
+				objects = append(objects[:i], objects[i+1:]...)
+			case object["is_deleted"] == true:
+			case i < len(objects):
+				objects[i] = object
+			default:
+				objects = append(objects, object)
+			}
+			state.setObjects(resource, objects)
+		}
+	}
+
+	buf, err = json.Marshal(state.data)
+	if err != nil {
+		return err
+	}
+	var store Store
+	if err := json.Unmarshal(buf, &store); err != nil {
+		return err
+	}
+	*s = store
+	return nil
+}
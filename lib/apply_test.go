@@ -0,0 +1,94 @@
+package todoist
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreApply(t *testing.T) {
+	var store Store
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"user": {"inbox_project_id": "1"},
+		"projects": [{"id": "1", "name": "Inbox", "parent_id": null}],
+		"items": [{"id": "10", "project_id": "1", "content": "parent", "parent_id": null},
+		          {"id": "11", "project_id": "1", "content": "child", "parent_id": "10"},
+		          {"id": "12", "project_id": "1", "content": "water plants", "parent_id": null,
+		           "due": {"date": "2020-01-01", "is_recurring": true, "string": "every day"}},
+		          {"id": "13", "project_id": "1", "content": "call", "parent_id": null,
+		           "due": {"date": "2020-01-01", "is_recurring": false, "string": "jan 1"}}]
+	}`), &store))
+	store.ConstructItemTree()
+
+	project := NewCommand("project_add", map[string]interface{}{"name": "Work"})
+	item := NewCommand("item_add", Item{BaseItem: BaseItem{HaveProjectID: HaveProjectID{ProjectID: project.TempID}, Content: "new"}, DateString: "2020-02-03"}.AddParam())
+	commands := Commands{
+		project,
+		item,
+		NewCommand("item_update", Item{BaseItem: BaseItem{HaveID: HaveID{ID: "10"}, Content: "renamed"}, DateString: "next monday"}.UpdateParam()),
+		NewCommand("item_update", Item{BaseItem: BaseItem{HaveID: HaveID{ID: "13"}}, DateString: "null"}.UpdateParam()),
+		NewCommand("item_close", map[string]interface{}{"id": "12"}),
+		NewCommand("item_delete", map[string]interface{}{"id": "10"}),
+	}
+	assert.NoError(t, store.Apply(commands, map[string]string{project.TempID: "2", item.TempID: "20"}))
+
+	assert.Equal(t, "Work", store.FindProject("2").Name, "they should be equal")
+	added := store.FindItem("20")
+	assert.Equal(t, "new", added.Content, "they should be equal")
+	assert.Equal(t, "2", added.ProjectID, "they should be equal")
+	assert.Equal(t, "2020-02-03", added.Due.Date, "they should be equal")
+	assert.Nil(t, store.FindItem("10"))
+	assert.Nil(t, store.FindItem("11"))
+	assert.False(t, store.FindItem("12").Checked)
+	assert.Nil(t, store.FindItem("13").Due)
+}
+
+func TestStoreApplyVacationMode(t *testing.T) {
+	var store Store
+	assert.NoError(t, json.Unmarshal([]byte(`{"user": {"id": "1", "features": {"beta": 0}}}`), &store))
+
+	on := NewCommand("update_goals", map[string]interface{}{"vacation_mode": 1})
+	assert.NoError(t, store.Apply(Commands{on}, nil))
+	assert.True(t, store.User.VacationMode())
+
+	off := NewCommand("update_goals", map[string]interface{}{"vacation_mode": 0})
+	assert.NoError(t, store.Apply(Commands{off}, nil))
+	assert.False(t, store.User.VacationMode())
+}
+
+func TestStoreMerge(t *testing.T) {
+	var store Store
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"sync_token": "1",
+		"user": {"inbox_project_id": "1", "full_name": "Old"},
+		"projects": [{"id": "1", "name": "Inbox"}],
+		"items": [{"id": "10", "project_id": "1", "content": "first"},
+		          {"id": "11", "project_id": "1", "content": "second"},
+		          {"id": "12", "project_id": "1", "content": "third"}]
+	}`), &store))
+
+	assert.NoError(t, store.Merge([]byte(`{
+		"full_sync": false,
+		"sync_token": "2",
+		"user": {"inbox_project_id": "1", "full_name": "New"},
+		"items": [{"id": "10", "project_id": "1", "content": "first, renamed"},
+		          {"id": "11", "is_deleted": true},
+		          {"id": "13", "project_id": "1", "content": "added"}]
+	}`)))
+	store.ConstructItemTree()
+
+	assert.Equal(t, "2", store.SyncToken, "they should be equal")
+	assert.Equal(t, "New", store.User.FullName, "they should be equal")
+	assert.Equal(t, "Inbox", store.FindProject("1").Name, "they should be equal")
+	contents := []string{}
+	for _, item := range store.Items {
+		contents = append(contents, item.Content)
+	}
+	assert.Equal(t, []string{"first, renamed", "third", "added"}, contents, "they should be equal")
+
+	assert.NoError(t, store.Merge([]byte(`{"full_sync": true, "sync_token": "3", "items": []}`)))
+	assert.Equal(t, "3", store.SyncToken, "they should be equal")
+	assert.Empty(t, store.Items)
+	assert.Nil(t, store.Projects)
+}
@@ -0,0 +1,60 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// page is a page of the results of the paginated endpoints.
+type page struct {
+	Results    json.RawMessage `json:"results"`
+	NextCursor *string         `json:"next_cursor"`
+}
+
+// getPages calls add with the results of every page of uri.
+func (c *Client) getPages(ctx context.Context, uri string, params url.Values, add func(results json.RawMessage) error) error {
+	for n := 1; ; n++ {
+		var p page
+		if err := c.doApiPage(ctx, http.MethodGet, uri, params, n, &p); err != nil {
+			return err
+		}
+		if err := add(p.Results); err != nil {
+			return err
+		}
+		if p.NextCursor == nil || *p.NextCursor == "" {
+			return nil
+		}
+		params.Set("cursor", *p.NextCursor)
+	}
+}
+
+// ArchivedProjects fetches the archived projects, which syncing leaves out.
+func (c *Client) ArchivedProjects(ctx context.Context) (Projects, error) {
+	projects := Projects{}
+	err := c.getPages(ctx, "projects/archived", url.Values{"limit": {"200"}}, func(results json.RawMessage) error {
+		var page Projects
+		if err := json.Unmarshal(results, &page); err != nil {
+			return err
+		}
+		projects = append(projects, page...)
+		return nil
+	})
+	return projects, err
+}
+
+// ProjectItems fetches the open tasks of a project, which works for the
+// archived projects syncing leaves out as well.
+func (c *Client) ProjectItems(ctx context.Context, projectID string) (Items, error) {
+	items := Items{}
+	err := c.getPages(ctx, "tasks", url.Values{"project_id": {projectID}, "limit": {"200"}}, func(results json.RawMessage) error {
+		var page Items
+		if err := json.Unmarshal(results, &page); err != nil {
+			return err
+		}
+		items = append(items, page...)
+		return nil
+	})
+	return items, err
+}
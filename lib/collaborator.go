@@ -0,0 +1,71 @@
+package todoist
+
+import (
+	"context"
+	"fmt"
+)
+
+type Collaborator struct {
+	ID       string `json:"id"`
+	Email    string `json:"email"`
+	FullName string `json:"full_name"`
+	Timezone string `json:"timezone"`
+	ImageID  string `json:"image_id"`
+}
+
+type Collaborators []Collaborator
+
+// CollaboratorState tells whether a collaborator is a member of a shared
+// project, and with which role.
+type CollaboratorState struct {
+	ProjectID string `json:"project_id"`
+	UserID    string `json:"user_id"`
+	State     string `json:"state"`
+	Role      string `json:"role"`
+}
+
+func (s *Store) FindCollaborator(id string) *Collaborator {
+	for i, collaborator := range s.Collaborators {
+		if collaborator.ID == id {
+			return &s.Collaborators[i]
+		}
+	}
+	return nil
+}
+
+// ResponsibleID returns the id of the user the item is assigned to, or "".
+func (item Item) ResponsibleID() string {
+	if item.ResponsibleUID == nil {
+		return ""
+	}
+	return fmt.Sprint(item.ResponsibleUID)
+}
+
+// IsShared reports whether the user with uid is a member of the project.
+// The projects of a workspace are shared with all its members, unless they
+// are invite only.
+func (s *Store) IsShared(projectID string, uid string) bool {
+	if project := s.FindProject(projectID); project != nil && project.WorkspaceID != "" && !project.IsInviteOnly {
+		if s.IsWorkspaceMember(project.WorkspaceID, uid) {
+			return true
+		}
+	}
+	for _, state := range s.CollaboratorStates {
+		if state.ProjectID == projectID && state.UserID == uid && state.State == "active" {
+			return true
+		}
+	}
+	return false
+}
+
+// AssignItem assigns the item to the user with uid, or unassigns it for "".
+func (c *Client) AssignItem(ctx context.Context, id string, uid string) error {
+	var responsible interface{}
+	if uid != "" {
+		responsible = uid
+	}
+	commands := Commands{
+		NewCommand("item_update", map[string]interface{}{"id": id, "responsible_uid": responsible}),
+	}
+	return c.ExecCommands(ctx, commands)
+}
@@ -0,0 +1,30 @@
+package todoist
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssignItem(t *testing.T) {
+	sandbox, err := NewSandbox([]byte(`{
+		"projects": [{"id": "1", "name": "Team", "parent_id": null}],
+		"items": [{"id": "10", "project_id": "1", "content": "task", "parent_id": null, "checked": false}],
+		"collaborator_states": [{"project_id": "1", "user_id": "2", "state": "active"},
+		                        {"project_id": "1", "user_id": "3", "state": "deleted"}]
+	}`))
+	assert.NoError(t, err)
+	client := NewClient(&Config{})
+	client.Transport = sandbox
+	ctx := context.Background()
+	assert.NoError(t, client.Sync(ctx))
+
+	assert.True(t, client.Store.IsShared("1", "2"))
+	assert.False(t, client.Store.IsShared("1", "3"))
+
+	assert.NoError(t, client.AssignItem(ctx, "10", "2"))
+	assert.Equal(t, "2", client.Store.FindItem("10").ResponsibleID(), "they should be equal")
+	assert.NoError(t, client.AssignItem(ctx, "10", ""))
+	assert.Equal(t, "", client.Store.FindItem("10").ResponsibleID(), "they should be equal")
+}
@@ -4,13 +4,41 @@ import (
 	"context"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 type Completed struct {
-	Items    CompletedItems `json:"items"`
-	Projects interface{}    `json:"projects"`
+	Items      CompletedItems `json:"items"`
+	NextCursor *string        `json:"next_cursor"`
 }
 
+// CompletedDays is how far back CompletedAll looks, the API allows at most
+// three months per request.
+const CompletedDays = 90
+
+// CompletedAll fetches the tasks completed within the last CompletedDays.
 func (c *Client) CompletedAll(ctx context.Context, r *Completed) error {
-	return c.doApi(ctx, http.MethodPost, "completed/get_all", url.Values{}, &r)
+	until := time.Now()
+	return c.CompletedBetween(ctx, until.AddDate(0, 0, -CompletedDays), until, r)
+}
+
+// CompletedBetween fetches every page of the tasks completed from since
+// until until, which must be at most CompletedDays apart.
+func (c *Client) CompletedBetween(ctx context.Context, since time.Time, until time.Time, r *Completed) error {
+	params := url.Values{
+		"since": {since.UTC().Format(RFC3339DateTime)},
+		"until": {until.UTC().Format(RFC3339DateTime)},
+		"limit": {"200"},
+	}
+	for n := 1; ; n++ {
+		var page Completed
+		if err := c.doApiPage(ctx, http.MethodGet, "tasks/completed/by_completion_date", params, n, &page); err != nil {
+			return err
+		}
+		r.Items = append(r.Items, page.Items...)
+		if page.NextCursor == nil || *page.NextCursor == "" {
+			return nil
+		}
+		params.Set("cursor", *page.NextCursor)
+	}
 }
@@ -0,0 +1,56 @@
+package todoist
+
+import (
+	"context"
+)
+
+type Filter struct {
+	HaveID
+	Color      string `json:"color"`
+	IsDeleted  bool   `json:"is_deleted"`
+	IsFavorite bool   `json:"is_favorite"`
+	ItemOrder  int    `json:"item_order"`
+	Name       string `json:"name"`
+	Query      string `json:"query"`
+}
+
+type Filters []Filter
+
+func (a Filters) Len() int           { return len(a) }
+func (a Filters) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a Filters) Less(i, j int) bool { return a[i].ID < a[j].ID }
+
+func (a Filters) At(i int) IDCarrier { return a[i] }
+
+func (a Filters) FindByName(name string) *Filter {
+	for i, filter := range a {
+		if filter.Name == name {
+			return &a[i]
+		}
+	}
+	return nil
+}
+
+func (filter Filter) UpdateParam() interface{} {
+	param := map[string]interface{}{
+		"id":          filter.ID,
+		"is_favorite": filter.IsFavorite,
+	}
+	if filter.Name != "" {
+		param["name"] = filter.Name
+	}
+	if filter.Query != "" {
+		param["query"] = filter.Query
+	}
+	if filter.Color != "" {
+		param["color"] = filter.Color
+	}
+	return param
+}
+
+func (c *Client) UpdateFilter(ctx context.Context, filter Filter) error {
+	commands := Commands{
+		NewCommand("filter_update", filter.UpdateParam()),
+	}
+	return c.ExecCommands(ctx, commands)
+}
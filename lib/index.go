@@ -0,0 +1,169 @@
+package todoist
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// IndexVersion is the version of the index format, indexes of other
+// versions are rebuilt.
+const IndexVersion = 2
+
+// IndexedItem is what the index knows about one item.
+type IndexedItem struct {
+	// Hash is the hash of the indexed text, to skip unchanged items.
+	Hash  string   `json:"hash"`
+	Terms []string `json:"terms"`
+}
+
+// Index is an inverted index over the content, description and comments of
+// items, with the names of the files attached to the comments, so searching
+// doesn't have to go through all of them.
+type Index struct {
+	Version int                    `json:"version"`
+	Items   map[string]IndexedItem `json:"items"`
+	// Postings maps each term to the sorted ids of the items containing it.
+	Postings map[string][]string `json:"postings"`
+	terms    []string
+}
+
+func NewIndex() *Index {
+	return &Index{Version: IndexVersion, Items: map[string]IndexedItem{}, Postings: map[string][]string{}}
+}
+
+// Tokenize splits text into lower case words.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func uniqueTerms(text string) []string {
+	seen := map[string]bool{}
+	terms := []string{}
+	for _, term := range Tokenize(text) {
+		if !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+	sort.Strings(terms)
+	return terms
+}
+
+func (idx *Index) addPosting(term string, id string) {
+	ids := idx.Postings[term]
+	i := sort.SearchStrings(ids, id)
+	if i < len(ids) && ids[i] == id {
+		return
+	}
+	ids = append(ids, "")
+	copy(ids[i+1:], ids[i:])
+	ids[i] = id
+	idx.Postings[term] = ids
+}
+
+func (idx *Index) removePosting(term string, id string) {
+	ids := idx.Postings[term]
+	i := sort.SearchStrings(ids, id)
+	if i == len(ids) || ids[i] != id {
+		return
+	}
+	ids = append(ids[:i], ids[i+1:]...)
+	if len(ids) == 0 {
+		delete(idx.Postings, term)
+		return
+	}
+	idx.Postings[term] = ids
+}
+
+func (idx *Index) remove(id string) {
+	for _, term := range idx.Items[id].Terms {
+		idx.removePosting(term, id)
+	}
+	delete(idx.Items, id)
+}
+
+// Update brings the index in line with store, only indexing again the items
+// which were added or changed since the last update. It reports whether the
+// index changed.
+func (idx *Index) Update(store *Store) bool {
+	if idx.Version != IndexVersion || idx.Items == nil || idx.Postings == nil {
+		*idx = *NewIndex()
+	}
+
+	notes := map[string][]string{}
+	for _, note := range store.Notes {
+		if note.IsDeleted {
+			continue
+		}
+		notes[note.ItemID] = append(notes[note.ItemID], note.Content)
+		if note.FileAttachment != nil {
+			notes[note.ItemID] = append(notes[note.ItemID], note.FileAttachment.FileName)
+		}
+	}
+
+	changed := false
+	seen := map[string]bool{}
+	for _, item := range store.Items {
+		seen[item.ID] = true
+		text := strings.Join(append([]string{item.Content, item.Description}, notes[item.ID]...), "\n")
+		sum := sha1.Sum([]byte(text))
+		hash := hex.EncodeToString(sum[:])
+		if indexed, ok := idx.Items[item.ID]; ok && indexed.Hash == hash {
+			continue
+		}
+		idx.remove(item.ID)
+		terms := uniqueTerms(text)
+		for _, term := range terms {
+			idx.addPosting(term, item.ID)
+		}
+		idx.Items[item.ID] = IndexedItem{Hash: hash, Terms: terms}
+		changed = true
+	}
+	for id := range idx.Items {
+		if !seen[id] {
+			idx.remove(id)
+			changed = true
+		}
+	}
+	if changed {
+		idx.terms = nil
+	}
+	return changed
+}
+
+// Search returns the ids of the items containing a word starting with each
+// word of query.
+func (idx *Index) Search(query string) map[string]bool {
+	if idx.terms == nil {
+		idx.terms = make([]string, 0, len(idx.Postings))
+		for term := range idx.Postings {
+			idx.terms = append(idx.terms, term)
+		}
+		sort.Strings(idx.terms)
+	}
+
+	var result map[string]bool
+	for _, word := range Tokenize(query) {
+		matches := map[string]bool{}
+		for i := sort.SearchStrings(idx.terms, word); i < len(idx.terms) && strings.HasPrefix(idx.terms[i], word); i++ {
+			for _, id := range idx.Postings[idx.terms[i]] {
+				if result == nil || result[id] {
+					matches[id] = true
+				}
+			}
+		}
+		result = matches
+		if len(result) == 0 {
+			break
+		}
+	}
+	if result == nil {
+		result = map[string]bool{}
+	}
+	return result
+}
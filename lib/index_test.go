@@ -0,0 +1,38 @@
+package todoist
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndex(t *testing.T) {
+	store := &Store{
+		Items: Items{
+			Item{BaseItem: BaseItem{HaveID: HaveID{ID: "1"}, Content: "Buy milk"}},
+			Item{BaseItem: BaseItem{HaveID: HaveID{ID: "2"}, Content: "Call Bob"}, Description: "About the milk delivery"},
+			Item{BaseItem: BaseItem{HaveID: HaveID{ID: "3"}, Content: "Write report"}},
+		},
+		Notes: Notes{
+			Note{HaveID: HaveID{ID: "10"}, ItemID: "3", Content: "Numbers are in the spreadsheet"},
+			Note{HaveID: HaveID{ID: "11"}, ItemID: "3", FileAttachment: &FileAttachment{FileName: "budget-2020.xlsx"}},
+		},
+	}
+	idx := NewIndex()
+	assert.True(t, idx.Update(store))
+	assert.False(t, idx.Update(store))
+
+	assert.Equal(t, map[string]bool{"1": true, "2": true}, idx.Search("milk"), "they should be equal")
+	assert.Equal(t, map[string]bool{"2": true}, idx.Search("MILK deliv"), "they should be equal")
+	assert.Equal(t, map[string]bool{"3": true}, idx.Search("spreadsheet"), "they should be equal")
+	assert.Equal(t, map[string]bool{"3": true}, idx.Search("budget xlsx"), "they should be equal")
+	assert.Equal(t, map[string]bool{}, idx.Search("groceries"), "they should be equal")
+
+	store.Items[0].Content = "Buy bread"
+	store.Items = store.Items[:2]
+	assert.True(t, idx.Update(store))
+	assert.Equal(t, map[string]bool{"2": true}, idx.Search("milk"), "they should be equal")
+	assert.Equal(t, map[string]bool{}, idx.Search("spreadsheet"), "they should be equal")
+	_, ok := idx.Postings["report"]
+	assert.False(t, ok)
+}
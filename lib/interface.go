@@ -5,12 +5,12 @@ import (
 )
 
 type HaveID struct {
-	ID int `json:"id"`
+	ID string `json:"id"`
 }
 type HaveIDs []HaveID
 
 type HaveProjectID struct {
-	ProjectID int `json:"project_id"`
+	ProjectID string `json:"project_id"`
 }
 
 type HaveIndent struct {
@@ -18,7 +18,7 @@ type HaveIndent struct {
 }
 
 type IDCarrier interface {
-	GetID() int
+	GetID() string
 }
 type Repository interface {
 	Len() int
@@ -26,36 +26,52 @@ type Repository interface {
 }
 
 type HaveParentID struct {
-	ParentID *int `json:"parent_id"`
+	ParentID *string `json:"parent_id"`
 }
 
 type ParentIDCarrier interface {
-	GetParentID() (int, error)
+	GetParentID() (string, error)
 }
 
-func (carrier HaveParentID) GetParentID() (int, error) {
+func (carrier HaveParentID) GetParentID() (string, error) {
 	if carrier.ParentID == nil {
-		return 0, errors.New("Parent ID is null")
+		return "", errors.New("Parent ID is null")
 	}
 	return *carrier.ParentID, nil
 }
 
+// SearchProjectParents returns the ancestors of project, the topmost first,
+// up to a missing one or a cycle.
 func SearchProjectParents(store *Store, project *Project) []*Project {
-	if project.ParentID == nil {
-		return []*Project{}
+	parents := []*Project{}
+	seen := map[string]bool{project.ID: true}
+	for project.ParentID != nil {
+		parent := store.FindProject(*project.ParentID)
+		if parent == nil || seen[parent.ID] {
+			break
+		}
+		seen[parent.ID] = true
+		parents = append([]*Project{parent}, parents...)
+		project = parent
 	}
-
-	parentProject := store.FindProject(*project.ParentID)
-	return append(SearchProjectParents(store, parentProject), parentProject)
+	return parents
 }
 
+// SearchItemParents returns the ancestors of item, the topmost first, up to
+// a missing one or a cycle.
 func SearchItemParents(store *Store, item *Item) []*Item {
-	if item.ParentID == nil {
-		return []*Item{}
+	parents := []*Item{}
+	seen := map[string]bool{item.ID: true}
+	for item.ParentID != nil {
+		parent := store.FindItem(*item.ParentID)
+		if parent == nil || seen[parent.ID] {
+			break
+		}
+		seen[parent.ID] = true
+		parents = append([]*Item{parent}, parents...)
+		item = parent
 	}
-
-	parentItem := store.FindItem(*item.ParentID)
-	return append(SearchItemParents(store, parentItem), parentItem)
+	return parents
 }
 
 type ContentCarrier interface {
@@ -63,10 +79,10 @@ type ContentCarrier interface {
 }
 
 type ProjectIDCarrier interface {
-	GetProjectID() int
+	GetProjectID() string
 }
 
-func (carrier HaveID) GetID() int {
+func (carrier HaveID) GetID() string {
 	return carrier.ID
 }
 
@@ -74,6 +90,6 @@ func (carrier HaveIndent) GetIndent() int {
 	return carrier.Indent
 }
 
-func (carrier HaveProjectID) GetProjectID() int {
+func (carrier HaveProjectID) GetProjectID() string {
 	return carrier.ProjectID
 }
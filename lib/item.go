@@ -2,13 +2,17 @@ package todoist
 
 import (
 	"context"
+	"errors"
 	"regexp"
 	"strings"
 	"time"
 )
 
 var (
-	linkRegex = regexp.MustCompile(`\[(.*?)\]\((.*?)\)`)
+	linkRegex      = regexp.MustCompile(`\[(.*?)\]\((.*?)\)`)
+	recurringRegex = regexp.MustCompile(`(?i)^\s*(every|ev)(!|\s)`)
+
+	RecurrenceLost = errors.New("the new due date would remove the recurrence")
 )
 
 const (
@@ -29,31 +33,46 @@ type BaseItem struct {
 	HaveID
 	HaveProjectID
 	Content string `json:"content"`
-	UserID  int    `json:"user_id"`
+	UserID  string `json:"user_id"`
 }
 
 func (bitem BaseItem) GetContent() string {
 	return bitem.Content
 }
 
+// Duration is how long a task takes, in minutes or days.
+type Duration struct {
+	Amount int    `json:"amount"`
+	Unit   string `json:"unit"`
+}
+
+// Minutes returns the duration in minutes, or false for one in days, which
+// says a task spans days rather than how long was spent on it.
+func (d *Duration) Minutes() (int, bool) {
+	if d == nil || d.Unit != "minute" {
+		return 0, false
+	}
+	return d.Amount, true
+}
+
 type CompletedItem struct {
 	BaseItem
-	CompletedData string      `json:"completed_date"`
-	MetaData      interface{} `json:"meta_data"`
-	TaskID        int         `json:"task_id"`
+	CompletedAt string    `json:"completed_at"`
+	LabelNames  []string  `json:"labels"`
+	Duration    *Duration `json:"duration"`
 }
 
 func (item CompletedItem) DateTime() time.Time {
-	t, _ := time.Parse(time.RFC3339, item.CompletedData)
+	t, _ := time.Parse(time.RFC3339, item.CompletedAt)
 	return t
 }
 
-func (item CompletedItem) GetProjectID() int {
+func (item CompletedItem) GetProjectID() string {
 	return item.ProjectID
 }
 
-func (item CompletedItem) GetLabelIDs() []int {
-	return []int{}
+func (item CompletedItem) GetLabelNames() []string {
+	return item.LabelNames
 }
 
 type CompletedItems []CompletedItem
@@ -62,27 +81,31 @@ type Item struct {
 	BaseItem
 	HaveParentID
 	HaveIndent
+	Description    string      `json:"description"`
+	SectionID      *string     `json:"section_id"`
 	ChildItem      *Item       `json:"-"`
 	BrotherItem    *Item       `json:"-"`
 	AllDay         bool        `json:"all_day"`
-	AssignedByUID  int         `json:"assigned_by_uid"`
-	Checked        int         `json:"checked"`
-	Collapsed      int         `json:"collapsed"`
-	DateAdded      string      `json:"date_added"`
+	AddedAt        string      `json:"added_at"`
+	AssignedByUID  interface{} `json:"assigned_by_uid"`
+	Checked        bool        `json:"checked"`
+	ChildOrder     int         `json:"child_order"`
+	CompletedAt    string      `json:"completed_at"`
 	DateLang       string      `json:"date_lang"`
 	DateString     string      `json:"date_string"`
 	DayOrder       int         `json:"day_order"`
 	Due            *Due        `json:"due"`
-	HasMoreNotes   bool        `json:"has_more_notes"`
-	InHistory      int         `json:"in_history"`
-	IsArchived     int         `json:"is_archived"`
-	IsDeleted      int         `json:"is_deleted"`
-	ItemOrder      int         `json:"item_order"`
-	LabelIDs       []int       `json:"labels"`
+	Duration       *Duration   `json:"duration"`
+	IsCollapsed    bool        `json:"is_collapsed"`
+	IsDeleted      bool        `json:"is_deleted"`
+	LabelNames     []string    `json:"labels"`
 	Priority       int         `json:"priority"`
 	AutoReminder   bool        `json:"auto_reminder"`
 	ResponsibleUID interface{} `json:"responsible_uid"`
 	SyncID         interface{} `json:"sync_id"`
+	UpdatedAt      string      `json:"updated_at"`
+	// NewDue, when set, replaces the due date on update.
+	NewDue *Due `json:"-"`
 }
 
 type Items []Item
@@ -113,19 +136,19 @@ func (item Item) DateTime() time.Time {
 	return t
 }
 
-func (item Item) GetProjectID() int {
+func (item Item) GetProjectID() string {
 	return item.ProjectID
 }
 
-func (item Item) GetLabelIDs() []int {
-	return item.LabelIDs
+func (item Item) GetLabelNames() []string {
+	return item.LabelNames
 }
 
 // interface for Eval actions
 type AbstractItem interface {
 	DateTime() time.Time
-	GetProjectID() int
-	GetLabelIDs() []int
+	GetProjectID() string
+	GetLabelNames() []string
 }
 
 func GetContentTitle(item ContentCarrier) string {
@@ -150,21 +173,37 @@ func HasURL(item ContentCarrier) bool {
 	return linkRegex.MatchString(item.GetContent())
 }
 
+// dueParam is the due argument setting the due date of item to its date
+// string, understood in its date language, or removing it for "null".
+func (item Item) dueParam() interface{} {
+	if item.DateString == "null" {
+		return nil
+	}
+	lang := item.DateLang
+	if lang == "" {
+		lang = "en"
+	}
+	return map[string]interface{}{"string": item.DateString, "lang": lang}
+}
+
 func (item Item) AddParam() interface{} {
 	param := map[string]interface{}{}
 	if item.Content != "" {
 		param["content"] = item.Content
 	}
+	if item.Description != "" {
+		param["description"] = item.Description
+	}
 	if item.DateString != "" {
-		param["date_string"] = item.DateString
+		param["due"] = item.dueParam()
 	}
-	if len(item.LabelIDs) != 0 {
-		param["labels"] = item.LabelIDs
+	if len(item.LabelNames) != 0 {
+		param["labels"] = item.LabelNames
 	}
 	if item.Priority != 0 {
 		param["priority"] = item.Priority
 	}
-	if item.ProjectID != 0 {
+	if item.ProjectID != "" {
 		param["project_id"] = item.ProjectID
 	}
 	param["auto_reminder"] = item.AutoReminder
@@ -174,21 +213,26 @@ func (item Item) AddParam() interface{} {
 
 func (item Item) UpdateParam() interface{} {
 	param := map[string]interface{}{}
-	if item.ID != 0 {
+	if item.ID != "" {
 		param["id"] = item.ID
 	}
 	if item.Content != "" {
 		param["content"] = item.Content
 	}
+	if item.Description != "" {
+		param["description"] = item.Description
+	}
 	if item.DateString != "" {
-		param["date_string"] = item.DateString
+		param["due"] = item.dueParam()
 	}
-	// TODO: more cool
-	if item.DateString == "null" {
-		param["date_string"] = ""
+	if item.NewDue != nil {
+		param["due"] = item.NewDue
+	}
+	if item.Duration != nil {
+		param["duration"] = item.Duration
 	}
-	if len(item.LabelIDs) != 0 {
-		param["labels"] = item.LabelIDs
+	if len(item.LabelNames) != 0 {
+		param["labels"] = item.LabelNames
 	}
 	if item.Priority != 0 {
 		param["priority"] = item.Priority
@@ -196,7 +240,41 @@ func (item Item) UpdateParam() interface{} {
 	return param
 }
 
-func (item *Item) MoveParam(projectId int) interface{} {
+// IsRecurringDateString reports whether s describes a recurring due date,
+// like "every monday" or "every! 3 days".
+func IsRecurringDateString(s string) bool {
+	return recurringRegex.MatchString(s)
+}
+
+// Reschedule changes the due date of item to dateString. A recurring task
+// keeps its recurrence, so only a concrete date (2006-01-02 or
+// 2006-01-02T15:04:05) or a new recurring date string can be applied to it
+// unless removeRecurrence is set.
+func (item *Item) Reschedule(dateString string, removeRecurrence bool) error {
+	if dateString == "" {
+		return nil
+	}
+	if item.Due == nil || !item.Due.IsRecurring || removeRecurrence || IsRecurringDateString(dateString) {
+		item.DateString = dateString
+		return nil
+	}
+
+	for _, layout := range []string{RFC3339Date, RFC3339DateTime} {
+		if _, err := time.Parse(layout, dateString); err == nil {
+			item.NewDue = &Due{
+				Date:        dateString,
+				TimeZone:    item.Due.TimeZone,
+				IsRecurring: true,
+				String:      item.Due.String,
+				Lang:        item.Due.Lang,
+			}
+			return nil
+		}
+	}
+	return RecurrenceLost
+}
+
+func (item *Item) MoveParam(projectId string) interface{} {
 	param := map[string]interface{}{
 		"id":         item.ID,
 		"project_id": projectId,
@@ -206,10 +284,9 @@ func (item *Item) MoveParam(projectId int) interface{} {
 
 func (item Item) LabelsString(store *Store) string {
 	var b strings.Builder
-	for i, labelId := range item.LabelIDs {
-		label := store.FindLabel(labelId)
-		b.WriteString("@" + label.Name)
-		if i < len(item.LabelIDs)-1 {
+	for i, name := range item.LabelNames {
+		b.WriteString("@" + name)
+		if i < len(item.LabelNames)-1 {
 			b.WriteString(",")
 		}
 	}
@@ -230,7 +307,19 @@ func (c *Client) UpdateItem(ctx context.Context, item Item) error {
 	return c.ExecCommands(ctx, commands)
 }
 
-func (c *Client) CloseItem(ctx context.Context, ids []int) error {
+// SetItemLabels replaces the labels of the item with names, which may be
+// none unlike with UpdateItem.
+func (c *Client) SetItemLabels(ctx context.Context, id string, names []string) error {
+	if names == nil {
+		names = []string{}
+	}
+	commands := Commands{
+		NewCommand("item_update", map[string]interface{}{"id": id, "labels": names}),
+	}
+	return c.ExecCommands(ctx, commands)
+}
+
+func (c *Client) CloseItem(ctx context.Context, ids []string) error {
 	var commands Commands
 	for _, id := range ids {
 		command := NewCommand("item_close", map[string]interface{}{"id": id})
@@ -239,7 +328,7 @@ func (c *Client) CloseItem(ctx context.Context, ids []int) error {
 	return c.ExecCommands(ctx, commands)
 }
 
-func (c *Client) DeleteItem(ctx context.Context, ids []int) error {
+func (c *Client) DeleteItem(ctx context.Context, ids []string) error {
 	var commands Commands
 	for _, id := range ids {
 		command := NewCommand("item_delete", map[string]interface{}{"id": id})
@@ -248,9 +337,64 @@ func (c *Client) DeleteItem(ctx context.Context, ids []int) error {
 	return c.ExecCommands(ctx, commands)
 }
 
-func (c *Client) MoveItem(ctx context.Context, item *Item, projectId int) error {
+func (c *Client) MoveItem(ctx context.Context, item *Item, projectId string) error {
 	commands := Commands{
 		NewCommand("item_move", item.MoveParam(projectId)),
 	}
 	return c.ExecCommands(ctx, commands)
 }
+
+// CopyOptions controls what CopyItemCommands copies and where.
+type CopyOptions struct {
+	// ProjectID is the id or temp id of the target project, nil keeps the
+	// item's project.
+	ProjectID interface{}
+	// ParentID is the id or temp id of the new parent, nil for none.
+	ParentID interface{}
+	// SectionIDs maps section ids to the ids or temp ids of their copies.
+	// Items in sections which are not mapped are copied without a section.
+	SectionIDs map[string]interface{}
+	SkipNotes  bool
+}
+
+// CopyItemCommands returns the commands which create a copy of item with its
+// open subtasks and, unless skipped, its comments.
+func (s *Store) CopyItemCommands(item *Item, opts CopyOptions) Commands {
+	param := item.AddParam().(map[string]interface{})
+	if opts.ProjectID != nil {
+		param["project_id"] = opts.ProjectID
+	}
+	if item.Due != nil {
+		param["due"] = item.Due
+	}
+	if opts.ParentID != nil {
+		param["parent_id"] = opts.ParentID
+	}
+	if item.SectionID != nil {
+		if sectionID, ok := opts.SectionIDs[*item.SectionID]; ok {
+			param["section_id"] = sectionID
+		} else if opts.ProjectID == nil {
+			param["section_id"] = *item.SectionID
+		}
+	}
+
+	command := NewCommand("item_add", param)
+	commands := Commands{command}
+	if !opts.SkipNotes {
+		for _, note := range s.ItemNotes(item.ID) {
+			noteParam := note.AddParam().(map[string]interface{})
+			noteParam["item_id"] = command.TempID
+			commands = append(commands, NewCommand("note_add", noteParam))
+		}
+	}
+
+	childOpts := opts
+	childOpts.ParentID = command.TempID
+	for child := item.ChildItem; child != nil; child = child.BrotherItem {
+		if child.Checked {
+			continue
+		}
+		commands = append(commands, s.CopyItemCommands(child, childOpts)...)
+	}
+	return commands
+}
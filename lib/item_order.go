@@ -2,7 +2,7 @@ package todoist
 
 type Order struct {
 	Num  int         `json:"num"`
-	ID   int         `json:"id"`
+	ID   string      `json:"id"`
 	Data interface{} `json:"-"`
 }
 type Orders []Order
@@ -0,0 +1,90 @@
+package todoist
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func recurringItem() Item {
+	return Item{Due: &Due{Date: "2020-03-02", IsRecurring: true, String: "every monday", Lang: "en"}}
+}
+
+func TestIsRecurringDateString(t *testing.T) {
+	assert.True(t, IsRecurringDateString("every monday"))
+	assert.True(t, IsRecurringDateString("Every! 3 days"))
+	assert.True(t, IsRecurringDateString("ev 2 weeks"))
+	assert.False(t, IsRecurringDateString("tomorrow"))
+	assert.False(t, IsRecurringDateString("everything"))
+}
+
+func TestRescheduleNonRecurring(t *testing.T) {
+	item := Item{Due: &Due{Date: "2020-03-02"}}
+	assert.NoError(t, item.Reschedule("tomorrow", false))
+	assert.Equal(t, "tomorrow", item.DateString, "they should be equal")
+	assert.Nil(t, item.NewDue)
+	assert.Equal(t, map[string]interface{}{"string": "tomorrow", "lang": "en"}, item.UpdateParam().(map[string]interface{})["due"], "they should be equal")
+
+	assert.NoError(t, item.Reschedule("null", false))
+	due, ok := item.UpdateParam().(map[string]interface{})["due"]
+	assert.True(t, ok)
+	assert.Nil(t, due)
+}
+
+func TestRescheduleRecurringKeepsRecurrence(t *testing.T) {
+	item := recurringItem()
+	assert.NoError(t, item.Reschedule("2020-03-09", false))
+	assert.Equal(t, "", item.DateString, "they should be equal")
+	assert.Equal(t, &Due{Date: "2020-03-09", IsRecurring: true, String: "every monday", Lang: "en"}, item.NewDue, "they should be equal")
+	assert.Equal(t, item.NewDue, item.UpdateParam().(map[string]interface{})["due"], "they should be equal")
+}
+
+func TestRescheduleRecurringRejectsNaturalDate(t *testing.T) {
+	item := recurringItem()
+	assert.Equal(t, RecurrenceLost, item.Reschedule("tomorrow", false), "they should be equal")
+	assert.Equal(t, RecurrenceLost, item.Reschedule("null", false), "they should be equal")
+}
+
+func TestRescheduleRecurringWithNewRecurrence(t *testing.T) {
+	item := recurringItem()
+	assert.NoError(t, item.Reschedule("every! 2 weeks", false))
+	assert.Equal(t, "every! 2 weeks", item.DateString, "they should be equal")
+	assert.Nil(t, item.NewDue)
+}
+
+func TestRescheduleRemoveRecurrence(t *testing.T) {
+	item := recurringItem()
+	assert.NoError(t, item.Reschedule("tomorrow", true))
+	assert.Equal(t, "tomorrow", item.DateString, "they should be equal")
+}
+
+func TestCopyItemCommands(t *testing.T) {
+	parentID := "1"
+	store := &Store{
+		Items: Items{
+			Item{BaseItem: BaseItem{HaveID: HaveID{ID: "1"}, Content: "checklist"}, Priority: 4},
+			Item{BaseItem: BaseItem{HaveID: HaveID{ID: "2"}, Content: "step"}, HaveParentID: HaveParentID{ParentID: &parentID}},
+			Item{BaseItem: BaseItem{HaveID: HaveID{ID: "3"}, Content: "done step"}, HaveParentID: HaveParentID{ParentID: &parentID}, Checked: true},
+		},
+		Notes: Notes{
+			Note{HaveID: HaveID{ID: "10"}, ItemID: "1", Content: "comment"},
+		},
+	}
+	store.ConstructItemTree()
+
+	commands := store.CopyItemCommands(store.FindItem("1"), CopyOptions{ProjectID: "5"})
+	assert.Equal(t, 3, len(commands), "they should be equal")
+
+	root := commands[0].Args.(map[string]interface{})
+	assert.Equal(t, "item_add", commands[0].Type, "they should be equal")
+	assert.Equal(t, "checklist", root["content"], "they should be equal")
+	assert.Equal(t, "5", root["project_id"], "they should be equal")
+	assert.Equal(t, 4, root["priority"], "they should be equal")
+
+	assert.Equal(t, "note_add", commands[1].Type, "they should be equal")
+	assert.Equal(t, commands[0].TempID, commands[1].Args.(map[string]interface{})["item_id"], "they should be equal")
+
+	child := commands[2].Args.(map[string]interface{})
+	assert.Equal(t, "step", child["content"], "they should be equal")
+	assert.Equal(t, commands[0].TempID, child["parent_id"], "they should be equal")
+}
@@ -1,11 +1,16 @@
 package todoist
 
+import (
+	"context"
+)
+
 type Label struct {
 	HaveID
-	Color     int    `json:"color"`
-	IsDeleted int    `json:"is_deleted"`
-	ItemOrder int    `json:"item_order"`
-	Name      string `json:"name"`
+	Color      string `json:"color"`
+	IsDeleted  bool   `json:"is_deleted"`
+	IsFavorite bool   `json:"is_favorite"`
+	ItemOrder  int    `json:"item_order"`
+	Name       string `json:"name"`
 }
 
 type Labels []Label
@@ -16,11 +21,49 @@ func (a Labels) Less(i, j int) bool { return a[i].ID < a[j].ID }
 
 func (a Labels) At(i int) IDCarrier { return a[i] }
 
-func (a Labels) GetIDByName(name string) int {
+func (a Labels) GetIDByName(name string) string {
 	for _, label := range a {
 		if label.Name == name {
 			return label.ID
 		}
 	}
-	return 0
+	return ""
+}
+
+func (a Labels) FindByID(id string) *Label {
+	for i, label := range a {
+		if label.ID == id {
+			return &a[i]
+		}
+	}
+	return nil
+}
+
+func (label Label) UpdateParam() interface{} {
+	param := map[string]interface{}{
+		"id":          label.ID,
+		"is_favorite": label.IsFavorite,
+	}
+	if label.Name != "" {
+		param["name"] = label.Name
+	}
+	if label.Color != "" {
+		param["color"] = label.Color
+	}
+	return param
+}
+
+func (c *Client) UpdateLabel(ctx context.Context, label Label) error {
+	commands := Commands{
+		NewCommand("label_update", label.UpdateParam()),
+	}
+	return c.ExecCommands(ctx, commands)
+}
+
+func (c *Client) DeleteLabel(ctx context.Context, ids []string) error {
+	var commands Commands
+	for _, id := range ids {
+		commands = append(commands, NewCommand("label_delete", map[string]interface{}{"id": id}))
+	}
+	return c.ExecCommands(ctx, commands)
 }
@@ -9,22 +9,43 @@ import (
 
 var (
 	FindFailed = errors.New("Find Failed")
+	ReadOnly   = errors.New("read-only mode, refusing to change data")
 )
 
 const (
-	Server     = "https://todoist.com/API/v8/"
+	Server = "https://api.todoist.com/api/v1/"
 )
 
-func ParseAPIError(prefix string, resp *http.Response) error {
-	errMsg := fmt.Sprintf("%s: %s", prefix, resp.Status)
-	var e struct {
-		Error string `json:"error"`
-	}
+// APIError is an error response of the Todoist API.
+type APIError struct {
+	Prefix     string
+	StatusCode int
+	Status     string
+	Tag        string `json:"error_tag"`
+	Message    string `json:"error"`
+}
 
-	json.NewDecoder(resp.Body).Decode(&e)
-	if e.Error != "" {
-		errMsg = fmt.Sprintf("%s: %s", errMsg, e.Error)
+func (e *APIError) Error() string {
+	errMsg := fmt.Sprintf("%s: %s", e.Prefix, e.Status)
+	if e.Message != "" {
+		errMsg = fmt.Sprintf("%s: %s", errMsg, e.Message)
 	}
+	return errMsg
+}
 
-	return errors.New(errMsg)
+// IsPremiumOnly reports whether err is the API refusing a feature, like the
+// completed tasks or the activity log, to a user without premium.
+func IsPremiumOnly(err error) bool {
+	e, ok := err.(*APIError)
+	return ok && e.StatusCode == http.StatusForbidden
+}
+
+func ParseAPIError(prefix string, resp *http.Response) error {
+	e := &APIError{
+		Prefix:     prefix,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+	}
+	json.NewDecoder(resp.Body).Decode(e)
+	return e
 }
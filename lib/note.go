@@ -0,0 +1,104 @@
+package todoist
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// FileAttachment is a file attached to a comment.
+type FileAttachment struct {
+	FileName     string `json:"file_name"`
+	FileType     string `json:"file_type"`
+	FileURL      string `json:"file_url"`
+	ResourceType string `json:"resource_type"`
+}
+
+type Note struct {
+	HaveID
+	HaveProjectID
+	Content        string          `json:"content"`
+	FileAttachment *FileAttachment `json:"file_attachment"`
+	IsDeleted      bool            `json:"is_deleted"`
+	ItemID         string          `json:"item_id"`
+	PostedAt       string          `json:"posted_at"`
+	PostedUID      string          `json:"posted_uid"`
+	UidsToNotify   interface{}     `json:"uids_to_notify"`
+}
+
+type Notes []Note
+
+func (note Note) AddParam() interface{} {
+	param := map[string]interface{}{
+		"item_id": note.ItemID,
+		"content": note.Content,
+	}
+	if note.FileAttachment != nil {
+		param["file_attachment"] = note.FileAttachment
+	}
+	return param
+}
+
+// ItemNotes returns the notes of the item with the given id.
+func (s *Store) ItemNotes(itemID string) []Note {
+	notes := []Note{}
+	for _, note := range s.Notes {
+		if note.ItemID == itemID && !note.IsDeleted {
+			notes = append(notes, note)
+		}
+	}
+	return notes
+}
+
+// ItemAttachments returns the files attached to the comments of the item
+// with the given id, in the order they were posted.
+func (s *Store) ItemAttachments(itemID string) []FileAttachment {
+	attachments := []FileAttachment{}
+	for _, note := range s.ItemNotes(itemID) {
+		if note.FileAttachment != nil && note.FileAttachment.FileURL != "" {
+			attachments = append(attachments, *note.FileAttachment)
+		}
+	}
+	return attachments
+}
+
+func (c *Client) AddNote(ctx context.Context, note Note) error {
+	commands := Commands{
+		NewCommand("note_add", note.AddParam()),
+	}
+	return c.ExecCommands(ctx, commands)
+}
+
+// Download writes the file at fileURL, like that of an attachment, to w.
+// The token is only sent to Todoist, not to other hosts files may be on.
+func (c *Client) Download(ctx context.Context, fileURL string, w io.Writer) error {
+	u, err := url.Parse(fileURL)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	if host := u.Hostname(); host == "todoist.com" || strings.HasSuffix(host, ".todoist.com") {
+		req.Header.Set("Authorization", "Bearer "+c.config.AccessToken)
+	}
+	if c.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.Timeout)
+		defer cancel()
+	}
+
+	resp, err := c.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return ParseAPIError("download failed", resp)
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
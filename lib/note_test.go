@@ -0,0 +1,45 @@
+package todoist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItemAttachments(t *testing.T) {
+	var store Store
+	assert.NoError(t, json.Unmarshal([]byte(`{"notes": [
+		{"id": "1", "item_id": "10", "content": "no file", "file_attachment": null},
+		{"id": "2", "item_id": "10", "content": "", "file_attachment": {"file_name": "a.png", "file_type": "image/png", "file_url": "https://example.com/a.png"}},
+		{"id": "3", "item_id": "11", "content": "", "file_attachment": {"file_name": "b.pdf", "file_url": "https://example.com/b.pdf"}},
+		{"id": "4", "item_id": "10", "content": "", "is_deleted": true, "file_attachment": {"file_name": "c.png", "file_url": "https://example.com/c.png"}}
+	]}`), &store))
+
+	attachments := store.ItemAttachments("10")
+	assert.Equal(t, 1, len(attachments), "they should be equal")
+	assert.Equal(t, "a.png", attachments[0].FileName, "they should be equal")
+}
+
+func TestDownload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The token is only for Todoist.
+		assert.Equal(t, "", r.Header.Get("Authorization"), "they should be equal")
+		if r.URL.Path != "/a.png" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("image"))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{AccessToken: "secret"})
+	buf := &bytes.Buffer{}
+	assert.NoError(t, client.Download(context.Background(), server.URL+"/a.png", buf))
+	assert.Equal(t, "image", buf.String(), "they should be equal")
+	assert.Error(t, client.Download(context.Background(), server.URL+"/missing.png", &bytes.Buffer{}))
+}
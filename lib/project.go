@@ -1,6 +1,7 @@
 package todoist
 
 import (
+	"context"
 	"strings"
 )
 
@@ -8,15 +9,17 @@ type Project struct {
 	HaveID
 	HaveParentID
 	HaveIndent
-	Collapsed      int      `json:"collapsed"`
-	Color          int      `json:"color"`
-	HasMoreNotes   bool     `json:"has_more_notes"`
+	ChildOrder     int      `json:"child_order"`
+	Color          string   `json:"color"`
 	InboxProject   bool     `json:"inbox_project"`
-	IsArchived     int      `json:"is_archived"`
-	IsDeleted      int      `json:"is_deleted"`
-	ItemOrder      int      `json:"item_order"`
+	IsArchived     bool     `json:"is_archived"`
+	IsCollapsed    bool     `json:"is_collapsed"`
+	IsDeleted      bool     `json:"is_deleted"`
+	IsFavorite     bool     `json:"is_favorite"`
 	Name           string   `json:"name"`
 	Shared         bool     `json:"shared"`
+	WorkspaceID    string   `json:"workspace_id"`
+	IsInviteOnly   bool     `json:"is_invite_only"`
 	ChildProject   *Project `json:"-"`
 	BrotherProject *Project `json:"-"`
 }
@@ -29,17 +32,17 @@ func (a Projects) Less(i, j int) bool { return a[i].ID < a[j].ID }
 
 func (a Projects) At(i int) IDCarrier { return a[i] }
 
-func (a Projects) GetIDByName(name string) int {
+func (a Projects) GetIDByName(name string) string {
 	for _, pjt := range a {
 		if pjt.Name == name {
 			return pjt.GetID()
 		}
 	}
-	return 0
+	return ""
 }
 
-func (a Projects) GetIDsByName(name string, isAll bool) []int {
-	ids := []int{}
+func (a Projects) GetIDsByName(name string, isAll bool) []string {
+	ids := []string{}
 	name = strings.ToLower(name)
 	for _, pjt := range a {
 		if strings.Contains(strings.ToLower(pjt.Name), name) {
@@ -55,8 +58,8 @@ func (a Projects) GetIDsByName(name string, isAll bool) []int {
 	return ids
 }
 
-func childProjectIDs(parentId int, projects Projects) []int {
-	ids := []int{}
+func childProjectIDs(parentId string, projects Projects) []string {
+	ids := []string{}
 	for _, pjt := range projects {
 		id, err := pjt.GetParentID()
 		if err != nil {
@@ -70,3 +73,48 @@ func childProjectIDs(parentId int, projects Projects) []int {
 	}
 	return ids
 }
+
+func (project Project) AddParam() interface{} {
+	param := map[string]interface{}{}
+	if project.Name != "" {
+		param["name"] = project.Name
+	}
+	if project.ParentID != nil {
+		param["parent_id"] = *project.ParentID
+	}
+	if project.Color != "" {
+		param["color"] = project.Color
+	}
+	if project.WorkspaceID != "" {
+		param["workspace_id"] = project.WorkspaceID
+	}
+	return param
+}
+
+func (c *Client) AddProject(ctx context.Context, project Project) error {
+	commands := Commands{
+		NewCommand("project_add", project.AddParam()),
+	}
+	return c.ExecCommands(ctx, commands)
+}
+
+func (project Project) UpdateParam() interface{} {
+	param := map[string]interface{}{
+		"id":          project.ID,
+		"is_favorite": project.IsFavorite,
+	}
+	if project.Name != "" {
+		param["name"] = project.Name
+	}
+	if project.Color != "" {
+		param["color"] = project.Color
+	}
+	return param
+}
+
+func (c *Client) UpdateProject(ctx context.Context, project Project) error {
+	commands := Commands{
+		NewCommand("project_update", project.UpdateParam()),
+	}
+	return c.ExecCommands(ctx, commands)
+}
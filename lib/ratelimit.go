@@ -0,0 +1,34 @@
+package todoist
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit is the rate limit state reported by the last API response.
+type RateLimit struct {
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+	Known     bool      `json:"known"`
+}
+
+func ParseRateLimit(header http.Header) RateLimit {
+	var r RateLimit
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return r
+	}
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return r
+	}
+	r.Limit = limit
+	r.Remaining = remaining
+	r.Known = true
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		r.Reset = time.Unix(reset, 0)
+	}
+	return r
+}
@@ -0,0 +1,47 @@
+package todoist
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "todoist")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	ctx := context.Background()
+
+	sandbox, err := NewSandbox([]byte(`{"user": {"token": "secret"}, "items": []}`))
+	assert.NoError(t, err)
+	client := NewClient(&Config{AccessToken: "secret"})
+	client.Transport = NewRecorder(dir, sandbox)
+	client.Store = &Store{}
+	assert.NoError(t, client.AddItem(ctx, Item{BaseItem: BaseItem{Content: "recorded"}}))
+	assert.NoError(t, client.Sync(ctx))
+
+	names, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(names), "they should be equal")
+	for _, name := range names {
+		buf, err := ioutil.ReadFile(name)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(buf), "secret")
+	}
+
+	replayer, err := NewReplayer(dir)
+	assert.NoError(t, err)
+	client = NewClient(&Config{AccessToken: "other"})
+	client.Transport = replayer
+	client.Store = &Store{}
+	// Requests are matched by kind, so the sync is answered by the second
+	// recording although it comes first.
+	assert.NoError(t, client.Sync(ctx))
+	assert.Equal(t, "recorded", client.Store.Items[0].Content, "they should be equal")
+	assert.NoError(t, client.AddItem(ctx, Item{BaseItem: BaseItem{Content: "recorded"}}))
+	assert.Error(t, client.Sync(ctx))
+}
@@ -0,0 +1,106 @@
+package todoist
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Reminder struct {
+	Due          *Due   `json:"due"`
+	ID           string `json:"id"`
+	IsDeleted    bool   `json:"is_deleted"`
+	ItemID       string `json:"item_id"`
+	MinuteOffset int    `json:"minute_offset"`
+	// Name is the place of a location reminder.
+	Name      string `json:"name"`
+	NotifyUID string `json:"notify_uid"`
+	Service   string `json:"service"`
+	Type      string `json:"type"`
+}
+
+func (r Reminder) GetID() string {
+	return r.ID
+}
+
+type Reminders []Reminder
+
+// Time returns when the reminder goes off. Location reminders, and relative
+// ones of tasks without a due time, have none.
+func (r Reminder) Time(store *Store) (time.Time, bool) {
+	switch r.Type {
+	case "absolute":
+		if r.Due == nil {
+			return time.Time{}, false
+		}
+		return Item{Due: r.Due}.DateTime(), true
+	case "relative":
+		item := store.FindItem(r.ItemID)
+		if item == nil || item.Due == nil || !strings.Contains(item.Due.Date, "T") {
+			return time.Time{}, false
+		}
+		return item.DateTime().Add(-time.Duration(r.MinuteOffset) * time.Minute), true
+	}
+	return time.Time{}, false
+}
+
+// UpcomingReminders returns the reminders of open tasks going off from now
+// on, earliest first, followed by the location reminders.
+func (s *Store) UpcomingReminders(now time.Time) Reminders {
+	upcoming := Reminders{}
+	times := map[string]time.Time{}
+	for _, reminder := range s.Reminders {
+		item := s.FindItem(reminder.ItemID)
+		if reminder.IsDeleted || item == nil || item.Checked {
+			continue
+		}
+		t, ok := reminder.Time(s)
+		if ok && t.Before(now) {
+			continue
+		}
+		if !ok && reminder.Type != "location" {
+			continue
+		}
+		times[reminder.ID] = t
+		upcoming = append(upcoming, reminder)
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		a, b := times[upcoming[i].ID], times[upcoming[j].ID]
+		if a.IsZero() != b.IsZero() {
+			return b.IsZero()
+		}
+		return a.Before(b)
+	})
+	return upcoming
+}
+
+// FindReminder returns the reminder with id, or nil.
+func (s *Store) FindReminder(id string) *Reminder {
+	for i, reminder := range s.Reminders {
+		if reminder.ID == id && !reminder.IsDeleted {
+			return &s.Reminders[i]
+		}
+	}
+	return nil
+}
+
+// SnoozeReminder replaces reminder by one going off at at, in one batch.
+func (c *Client) SnoozeReminder(ctx context.Context, reminder Reminder, at time.Time) error {
+	args := map[string]interface{}{
+		"item_id": reminder.ItemID,
+		"type":    "absolute",
+		"due":     &Due{Date: at.Local().Format(RFC3339DateTime)},
+	}
+	if reminder.NotifyUID != "" {
+		args["notify_uid"] = reminder.NotifyUID
+	}
+	if reminder.Service != "" {
+		args["service"] = reminder.Service
+	}
+	commands := Commands{
+		NewCommand("reminder_delete", map[string]interface{}{"id": reminder.ID}),
+		NewCommand("reminder_add", args),
+	}
+	return c.ExecCommands(ctx, commands)
+}
@@ -0,0 +1,68 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpcomingReminders(t *testing.T) {
+	var store Store
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"items": [
+			{"id": "1", "content": "timed", "due": {"date": "2020-01-02T15:00:00"}},
+			{"id": "2", "content": "all day", "due": {"date": "2020-01-02"}},
+			{"id": "3", "content": "done", "checked": true}
+		],
+		"reminders": [
+			{"id": "10", "item_id": "1", "type": "relative", "minute_offset": 30},
+			{"id": "11", "item_id": "2", "type": "absolute", "due": {"date": "2020-01-02T09:00:00"}},
+			{"id": "12", "item_id": "2", "type": "location", "name": "Office"},
+			{"id": "13", "item_id": "2", "type": "relative", "minute_offset": 10},
+			{"id": "14", "item_id": "1", "type": "absolute", "due": {"date": "2020-01-01T09:00:00"}},
+			{"id": "15", "item_id": "3", "type": "absolute", "due": {"date": "2020-01-03T09:00:00"}},
+			{"id": "16", "item_id": "1", "type": "absolute", "due": {"date": "2020-01-04T09:00:00"}, "is_deleted": true}
+		]
+	}`), &store))
+	store.ConstructItemTree()
+
+	reminder := store.Reminders[0]
+	at, ok := reminder.Time(&store)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2020, 1, 2, 14, 30, 0, 0, time.Local), at, "they should be equal")
+
+	ids := []string{}
+	for _, reminder := range store.UpcomingReminders(time.Date(2020, 1, 2, 8, 0, 0, 0, time.Local)) {
+		ids = append(ids, reminder.ID)
+	}
+	assert.Equal(t, []string{"11", "10", "12"}, ids, "they should be equal")
+}
+
+func TestSnoozeReminder(t *testing.T) {
+	sandbox, err := NewSandbox([]byte(`{
+		"items": [{"id": "1", "content": "timed", "due": {"date": "2020-01-02T15:00:00"}}],
+		"reminders": [{"id": "10", "item_id": "1", "type": "relative", "minute_offset": 30, "notify_uid": "5"}]
+	}`))
+	assert.NoError(t, err)
+	client := NewClient(&Config{})
+	client.Transport = sandbox
+	client.Store = &Store{}
+	ctx := context.Background()
+	assert.NoError(t, client.Sync(ctx))
+
+	at := time.Date(2020, 1, 2, 15, 0, 0, 0, time.Local)
+	assert.NoError(t, client.SnoozeReminder(ctx, *client.Store.FindReminder("10"), at))
+	assert.NoError(t, client.Sync(ctx))
+
+	assert.Nil(t, client.Store.FindReminder("10"))
+	assert.Equal(t, 1, len(client.Store.Reminders), "they should be equal")
+	reminder := client.Store.Reminders[0]
+	assert.Equal(t, "absolute", reminder.Type, "they should be equal")
+	assert.Equal(t, "5", reminder.NotifyUID, "they should be equal")
+	got, ok := reminder.Time(client.Store)
+	assert.True(t, ok)
+	assert.Equal(t, at, got, "they should be equal")
+}
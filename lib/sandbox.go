@@ -0,0 +1,232 @@
+package todoist
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Sandbox is an http.RoundTripper which fakes the Todoist API in memory, so
+// every command can be tried without an account or network access. Closed
+// tasks stay in the items with checked set and are served as completed tasks.
+type Sandbox struct {
+	mu sync.Mutex
+	*commandState
+	nextID int
+}
+
+// NewSandbox returns a Sandbox serving state, which is a sync response or a
+// cache file.
+func NewSandbox(state []byte) (*Sandbox, error) {
+	s := &Sandbox{nextID: 1}
+	s.commandState = &commandState{data: map[string]interface{}{}, tempIDs: map[string]string{}, newID: s.newID, guessDates: true}
+	if err := decodeJSON(bytes.NewReader(state), &s.data); err != nil {
+		return nil, err
+	}
+	for _, resource := range commandResources {
+		for _, object := range s.objects(resource) {
+			// Sandbox ids are numbers, but any string works.
+			if id, err := strconv.Atoi(jsonID(object["id"])); err == nil && id >= s.nextID {
+				s.nextID = id + 1
+			}
+		}
+	}
+	return s, nil
+}
+
+func (s *Sandbox) newID(tempID string) string {
+	id := strconv.Itoa(s.nextID)
+	s.nextID++
+	return id
+}
+
+func (s *Sandbox) quickAdd(text string) map[string]interface{} {
+	args := map[string]interface{}{}
+	labels := []interface{}{}
+	words := []string{}
+	for _, word := range strings.Fields(text) {
+		switch {
+		case strings.HasPrefix(word, "#"):
+			for _, project := range s.objects("projects") {
+				if project["name"] == word[1:] {
+					args["project_id"] = project["id"]
+				}
+			}
+		case strings.HasPrefix(word, "@"):
+			for _, label := range s.objects("labels") {
+				if label["name"] == word[1:] {
+					labels = append(labels, label["name"])
+				}
+			}
+		default:
+			words = append(words, word)
+		}
+	}
+	args["content"] = strings.Join(words, " ")
+	args["labels"] = labels
+	return s.add("items", "", args)
+}
+
+func (s *Sandbox) completed() interface{} {
+	items := []interface{}{}
+	for _, item := range s.objects("items") {
+		if item["checked"] == true {
+			items = append(items, item)
+		}
+	}
+	return map[string]interface{}{"items": items}
+}
+
+// added returns the activity of the items added, those with a time they
+// were added at.
+func (s *Sandbox) added() interface{} {
+	events := []interface{}{}
+	for _, item := range s.objects("items") {
+		if addedAt, ok := item["added_at"].(string); ok && addedAt != "" {
+			events = append(events, map[string]interface{}{
+				"id":                jsonID(item["id"]),
+				"object_type":       "item",
+				"object_id":         jsonID(item["id"]),
+				"event_type":        "added",
+				"event_date":        addedAt,
+				"parent_project_id": jsonID(item["project_id"]),
+			})
+		}
+	}
+	return map[string]interface{}{"results": events, "next_cursor": nil}
+}
+
+// unarchived returns the data without the archived projects and their
+// tasks, which syncing leaves out.
+func (s *Sandbox) unarchived() map[string]interface{} {
+	archived := map[string]bool{}
+	projects := []interface{}{}
+	for _, project := range s.objects("projects") {
+		if project["is_archived"] == true {
+			archived[jsonID(project["id"])] = true
+			continue
+		}
+		projects = append(projects, project)
+	}
+	items := []interface{}{}
+	for _, item := range s.objects("items") {
+		if !archived[jsonID(item["project_id"])] {
+			items = append(items, item)
+		}
+	}
+	data := map[string]interface{}{}
+	for key, value := range s.data {
+		data[key] = value
+	}
+	data["projects"] = projects
+	data["items"] = items
+	return data
+}
+
+func (s *Sandbox) serve(endpoint string, params url.Values) (int, interface{}) {
+	switch endpoint {
+	case "sync":
+		if params.Get("commands") == "" {
+			s.data["full_sync"] = true
+			s.data["sync_token"] = "sandbox"
+			return http.StatusOK, s.unarchived()
+		}
+		var commands Commands
+		if err := decodeJSON(bytes.NewReader([]byte(params.Get("commands"))), &commands); err != nil {
+			return http.StatusBadRequest, map[string]interface{}{"error_tag": "INVALID_ARGUMENT_VALUE", "error": err.Error()}
+		}
+		status := map[string]interface{}{}
+		for _, command := range commands {
+			if err := s.exec(command); err != nil {
+				status[command.UUID] = map[string]interface{}{"error_tag": "INVALID_ARGUMENT_VALUE", "error": err.Error()}
+				continue
+			}
+			status[command.UUID] = "ok"
+		}
+		return http.StatusOK, map[string]interface{}{"sync_token": "sandbox", "sync_status": status, "temp_id_mapping": s.tempIDs}
+	case "tasks/quick":
+		return http.StatusOK, s.quickAdd(params.Get("text"))
+	case "tasks/completed/by_completion_date":
+		return http.StatusOK, s.completed()
+	case "activities":
+		if params.Get("object_type") == "item" && params.Get("event_type") == "added" {
+			return http.StatusOK, s.added()
+		}
+	case "projects/archived":
+		projects := []interface{}{}
+		for _, project := range s.objects("projects") {
+			if project["is_archived"] == true {
+				projects = append(projects, project)
+			}
+		}
+		return http.StatusOK, map[string]interface{}{"results": projects, "next_cursor": nil}
+	case "tasks":
+		items := []interface{}{}
+		for _, item := range s.objects("items") {
+			if jsonID(item["project_id"]) == params.Get("project_id") && item["checked"] != true {
+				items = append(items, item)
+			}
+		}
+		return http.StatusOK, map[string]interface{}{"results": items, "next_cursor": nil}
+	}
+	return http.StatusNotFound, map[string]interface{}{"error_tag": "NOT_FOUND", "error": "not available in the sandbox"}
+}
+
+// respond answers req from the sandbox state.
+func (s *Sandbox) respond(req *http.Request) (int, []byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	params, err := readRequestParams(req)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	server, err := url.Parse(Server)
+	if err != nil {
+		return 0, nil, err
+	}
+	endpoint := strings.TrimPrefix(req.URL.Path, path.Clean(server.Path)+"/")
+
+	code, res := s.serve(endpoint, params)
+	buf, err := json.Marshal(res)
+	return code, buf, err
+}
+
+// RoundTrip answers req from the sandbox state.
+func (s *Sandbox) RoundTrip(req *http.Request) (*http.Response, error) {
+	code, buf, err := s.respond(req)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", code, http.StatusText(code)),
+		StatusCode: code,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       ioutil.NopCloser(bytes.NewReader(buf)),
+		Request:    req,
+	}, nil
+}
+
+// ServeHTTP answers req from the sandbox state, so that it can be served
+// at the path of the API, /api/v1/, like by todoisttest.Server.
+func (s *Sandbox) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	code, buf, err := s.respond(req)
+	if err != nil {
+		code = http.StatusBadRequest
+		buf, _ = json.Marshal(map[string]interface{}{"error_tag": "INVALID_ARGUMENT_VALUE", "error": err.Error()})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(buf)
+}
@@ -0,0 +1,79 @@
+package todoist
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSandbox(t *testing.T) {
+	sandbox, err := NewSandbox([]byte(`{
+		"projects": [{"id": "1", "name": "Inbox", "parent_id": null}],
+		"items": [{"id": "10", "project_id": "1", "content": "parent", "parent_id": null, "checked": false},
+		          {"id": "11", "project_id": "1", "content": "child", "parent_id": "10", "checked": false}]
+	}`))
+	assert.NoError(t, err)
+	client := NewClient(&Config{})
+	client.Transport = sandbox
+	client.Store = &Store{}
+	ctx := context.Background()
+
+	project := NewCommand("project_add", map[string]interface{}{"name": "Work"})
+	commands := Commands{
+		project,
+		NewCommand("item_add", map[string]interface{}{"content": "new", "project_id": project.TempID}),
+		NewCommand("item_close", map[string]interface{}{"id": "11"}),
+	}
+	assert.NoError(t, client.ExecCommands(ctx, commands))
+	assert.NoError(t, client.Sync(ctx))
+
+	assert.Equal(t, 2, len(client.Store.Projects), "they should be equal")
+	assert.Equal(t, 3, len(client.Store.Items), "they should be equal")
+	item := client.Store.FindItem("13")
+	assert.Equal(t, "new", item.Content, "they should be equal")
+	assert.Equal(t, "12", item.ProjectID, "they should be equal")
+	assert.True(t, client.Store.FindItem("11").Checked)
+
+	var completed Completed
+	assert.NoError(t, client.CompletedAll(ctx, &completed))
+	assert.Equal(t, 1, len(completed.Items), "they should be equal")
+
+	var activity Activity
+	assert.NoError(t, client.ActivityBetween(ctx, "item", "added", time.Now().AddDate(0, 0, -7), time.Now(), &activity))
+	if assert.Equal(t, 1, len(activity.Events), "they should be equal") {
+		assert.Equal(t, "13", activity.Events[0].ObjectID, "they should be equal")
+	}
+
+	assert.NoError(t, client.DeleteItem(ctx, []string{"10"}))
+	assert.NoError(t, client.Sync(ctx))
+	assert.Equal(t, 1, len(client.Store.Items), "they should be equal")
+}
+
+func TestSandboxArchived(t *testing.T) {
+	sandbox, err := NewSandbox([]byte(`{
+		"projects": [{"id": "1", "name": "Inbox", "parent_id": null},
+		             {"id": "2", "name": "Old", "parent_id": null, "is_archived": true}],
+		"items": [{"id": "10", "project_id": "1", "content": "open", "parent_id": null, "checked": false},
+		          {"id": "11", "project_id": "2", "content": "archived", "parent_id": null, "checked": false}]
+	}`))
+	assert.NoError(t, err)
+	client := NewClient(&Config{})
+	client.Transport = sandbox
+	ctx := context.Background()
+
+	assert.NoError(t, client.Sync(ctx))
+	assert.Equal(t, 1, len(client.Store.Projects), "they should be equal")
+	assert.Equal(t, 1, len(client.Store.Items), "they should be equal")
+
+	projects, err := client.ArchivedProjects(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(projects), "they should be equal")
+	assert.Equal(t, "Old", projects[0].Name, "they should be equal")
+
+	items, err := client.ProjectItems(ctx, "2")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(items), "they should be equal")
+	assert.Equal(t, "archived", items[0].Content, "they should be equal")
+}
@@ -0,0 +1,30 @@
+package todoist
+
+type Section struct {
+	HaveID
+	HaveProjectID
+	Name         string `json:"name"`
+	SectionOrder int    `json:"section_order"`
+	IsCollapsed  bool   `json:"is_collapsed"`
+	IsArchived   bool   `json:"is_archived"`
+	IsDeleted    bool   `json:"is_deleted"`
+}
+
+type Sections []Section
+
+func (a Sections) Len() int           { return len(a) }
+func (a Sections) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a Sections) Less(i, j int) bool { return a[i].SectionOrder < a[j].SectionOrder }
+
+func (a Sections) At(i int) IDCarrier { return a[i] }
+
+// ProjectSections returns the sections of the project with the given id.
+func (s *Store) ProjectSections(projectID string) Sections {
+	sections := Sections{}
+	for _, section := range s.Sections {
+		if section.ProjectID == projectID && !section.IsDeleted {
+			sections = append(sections, section)
+		}
+	}
+	return sections
+}
@@ -1,80 +1,52 @@
 package todoist
 
+import (
+	"time"
+)
+
+// CacheVersion is the version of the cache format, caches of older versions
+// have to be synced again.
+const CacheVersion = 2
+
 type Store struct {
-	CollaboratorStates []interface{} `json:"collaborator_states"`
-	Collaborators      []interface{} `json:"collaborators"`
-	DayOrders          interface{}   `json:"day_orders"`
-	DayOrdersTimestamp string        `json:"day_orders_timestamp"`
-	Filters            []struct {
-		Color     int    `json:"color"`
-		ID        int    `json:"id"`
-		IsDeleted int    `json:"is_deleted"`
-		ItemOrder int    `json:"item_order"`
-		Name      string `json:"name"`
-		Query     string `json:"query"`
-	} `json:"filters"`
-	FullSync          bool   `json:"full_sync"`
-	Items             Items  `json:"items"`
-	Labels            Labels `json:"labels"`
-	LiveNotifications []struct {
-		CompletedTasks   int     `json:"completed_tasks"`
-		Created          string  `json:"created"`
-		DateReached      string  `json:"date_reached"`
-		ID               int     `json:"id"`
-		IsDeleted        int     `json:"is_deleted"`
-		KarmaLevel       int     `json:"karma_level"`
-		NotificationKey  string  `json:"notification_key"`
-		NotificationType string  `json:"notification_type"`
-		PromoImg         string  `json:"promo_img"`
-		SeqNo            int64   `json:"seq_no"`
-		TopProcent       float32 `json:"top_procent"`
-	} `json:"live_notifications"`
-	LiveNotificationsLastReadID int           `json:"live_notifications_last_read_id"`
-	Locations                   []interface{} `json:"locations"`
-	Notes                       []struct {
-		Content        string      `json:"content"`
-		FileAttachment interface{} `json:"file_attachment"`
-		ID             int         `json:"id"`
-		IsArchived     int         `json:"is_archived"`
-		IsDeleted      int         `json:"is_deleted"`
-		ItemID         int         `json:"item_id"`
-		Posted         string      `json:"posted"`
-		PostedUID      int         `json:"posted_uid"`
-		ProjectID      int         `json:"project_id"`
-		UidsToNotify   interface{} `json:"uids_to_notify"`
-	} `json:"notes"`
-	ProjectNotes []interface{} `json:"project_notes"`
-	Projects     Projects      `json:"projects"`
-	Reminders    []struct {
-		DateLang     string `json:"date_lang"`
-		Due          *Due   `json:"due"`
-		ID           int    `json:"id"`
-		IsDeleted    int    `json:"is_deleted"`
-		ItemID       int    `json:"item_id"`
-		MinuteOffset int    `json:"minute_offset"`
-		NotifyUID    int    `json:"notify_uid"`
-		Service      string `json:"service"`
-		Type         string `json:"type"`
-	} `json:"reminders"`
-	SyncToken     string           `json:"sync_token"`
-	TempIDMapping struct{}         `json:"temp_id_mapping"`
-	User          User             `json:"user"`
-	RootItem      *Item            `json:"-"`
-	RootProject   *Project         `json:"-"`
-	ItemMap       map[int]*Item    `json:"-"`
-	ProjectMap    map[int]*Project `json:"-"`
-	LabelMap      map[int]*Label   `json:"-"`
+	CacheVersion       int                 `json:"cache_version"`
+	CollaboratorStates []CollaboratorState `json:"collaborator_states"`
+	Collaborators      Collaborators       `json:"collaborators"`
+	DayOrders          interface{}         `json:"day_orders"`
+	Filters            Filters             `json:"filters"`
+	FullSync           bool                `json:"full_sync"`
+	Items              Items               `json:"items"`
+	Labels             Labels              `json:"labels"`
+	LiveNotifications  []interface{}       `json:"live_notifications"`
+	Locations          []interface{}       `json:"locations"`
+	Notes              Notes               `json:"notes"`
+	ProjectNotes       []interface{}       `json:"project_notes"`
+	Projects           Projects            `json:"projects"`
+	Reminders          Reminders           `json:"reminders"`
+	Sections           Sections            `json:"sections"`
+	SyncToken          string              `json:"sync_token"`
+	LastSync           time.Time           `json:"last_sync"`
+	RateLimit          RateLimit           `json:"rate_limit"`
+	TempIDMapping      map[string]string   `json:"temp_id_mapping"`
+	User               User                `json:"user"`
+	WorkspaceUsers     []WorkspaceUser     `json:"workspace_users"`
+	Workspaces         Workspaces          `json:"workspaces"`
+	RootItem           *Item               `json:"-"`
+	RootProject        *Project            `json:"-"`
+	ItemMap            map[string]*Item    `json:"-"`
+	ProjectMap         map[string]*Project `json:"-"`
+	LabelMap           map[string]*Label   `json:"-"`
 }
 
-func (s *Store) FindItem(id int) *Item {
+func (s *Store) FindItem(id string) *Item {
 	return s.ItemMap[id]
 }
 
-func (s *Store) FindProject(id int) *Project {
+func (s *Store) FindProject(id string) *Project {
 	return s.ProjectMap[id]
 }
 
-func (s *Store) FindLabel(id int) *Label {
+func (s *Store) FindLabel(id string) *Label {
 	return s.LabelMap[id]
 }
 
@@ -117,9 +89,9 @@ func addToChildProject(project *Project, b *Project) {
 }
 
 func (s *Store) ConstructItemTree() {
-	s.LabelMap = map[int]*Label{}
-	s.ProjectMap = map[int]*Project{}
-	s.ItemMap = map[int]*Item{}
+	s.LabelMap = map[string]*Label{}
+	s.ProjectMap = map[string]*Project{}
+	s.ItemMap = map[string]*Item{}
 
 	for i, label := range s.Labels {
 		s.LabelMap[label.ID] = &s.Labels[i]
@@ -137,45 +109,74 @@ func (s *Store) ConstructItemTree() {
 		s.Projects[i].BrotherProject = nil
 	}
 
-	for _, item := range s.Items {
-		if item.ParentID == nil {
-			s.RootItem = &item
-			break
+	// Items whose parent is missing, like in an archived project, or which
+	// are in a cycle of parents are put at the top so none gets lost.
+	s.RootItem = nil
+	for i := range s.Items {
+		item := &s.Items[i]
+		parent := s.itemTreeParent(item)
+		switch {
+		case parent != nil:
+			addToChildItem(parent, item)
+		case s.RootItem == nil:
+			s.RootItem = item
+		default:
+			addToBrotherItem(s.RootItem, item)
 		}
 	}
 
-	for _, project := range s.Projects {
-		if project.ParentID == nil {
-			s.RootProject = &project
-			break
+	s.RootProject = nil
+	for i := range s.Projects {
+		project := &s.Projects[i]
+		parent := s.projectTreeParent(project)
+		switch {
+		case parent != nil:
+			addToChildProject(parent, project)
+		case s.RootProject == nil:
+			s.RootProject = project
+		default:
+			addToBrotherProject(s.RootProject, project)
 		}
 	}
+}
 
-	for i := range s.Items {
-		if s.Items[i].ID == s.RootItem.ID {
-			continue
+// itemTreeParent returns the parent of item, or nil if it has none, it is
+// missing or item is its own ancestor.
+func (s *Store) itemTreeParent(item *Item) *Item {
+	if item.ParentID == nil {
+		return nil
+	}
+	parent := s.ItemMap[*item.ParentID]
+	seen := map[string]bool{}
+	for ancestor := parent; ancestor != nil && !seen[ancestor.ID]; {
+		if ancestor.ID == item.ID {
+			return nil
 		}
-
-		if s.Items[i].ParentID == nil {
-			addToBrotherItem(s.RootItem, &s.Items[i])
-			continue
+		seen[ancestor.ID] = true
+		if ancestor.ParentID == nil {
+			break
 		}
-		id, _ := s.Items[i].GetParentID()
-		parent := s.FindItem(id)
-		addToChildItem(parent, &s.Items[i])
+		ancestor = s.ItemMap[*ancestor.ParentID]
 	}
+	return parent
+}
 
-	for i := range s.Projects {
-		if s.Projects[i].ID == s.RootProject.ID {
-			continue
+// projectTreeParent is itemTreeParent for projects.
+func (s *Store) projectTreeParent(project *Project) *Project {
+	if project.ParentID == nil {
+		return nil
+	}
+	parent := s.ProjectMap[*project.ParentID]
+	seen := map[string]bool{}
+	for ancestor := parent; ancestor != nil && !seen[ancestor.ID]; {
+		if ancestor.ID == project.ID {
+			return nil
 		}
-
-		if s.Projects[i].ParentID == nil {
-			addToBrotherProject(s.RootProject, &s.Projects[i])
-			continue
+		seen[ancestor.ID] = true
+		if ancestor.ParentID == nil {
+			break
 		}
-		id, _ := s.Projects[i].GetParentID()
-		parent := s.FindProject(id)
-		addToChildProject(parent, &s.Projects[i])
+		ancestor = s.ProjectMap[*ancestor.ParentID]
 	}
+	return parent
 }
@@ -0,0 +1,42 @@
+package todoist
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstructItemTree(t *testing.T) {
+	var store Store
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"items": [
+			{"id": "1", "content": "root"},
+			{"id": "2", "content": "child", "parent_id": "1"},
+			{"id": "3", "content": "orphan", "parent_id": "99"},
+			{"id": "4", "content": "cycle a", "parent_id": "5"},
+			{"id": "5", "content": "cycle b", "parent_id": "4"},
+			{"id": "6", "content": "self", "parent_id": "6"}
+		],
+		"projects": [
+			{"id": "1", "name": "Child", "parent_id": "2"},
+			{"id": "2", "name": "Parent"},
+			{"id": "3", "name": "Orphan", "parent_id": "99"}
+		]
+	}`), &store))
+	store.ConstructItemTree()
+
+	ids := []string{}
+	for item := store.RootItem; item != nil; item = item.BrotherItem {
+		ids = append(ids, item.ID)
+	}
+	assert.Equal(t, []string{"1", "3", "4", "5", "6"}, ids, "they should be equal")
+	assert.Equal(t, "2", store.RootItem.ChildItem.ID, "they should be equal")
+	assert.Equal(t, 1, len(SearchItemParents(&store, store.FindItem("5"))), "they should be equal")
+	assert.Equal(t, 0, len(SearchItemParents(&store, store.FindItem("3"))), "they should be equal")
+
+	assert.Equal(t, "2", store.RootProject.ID, "they should be equal")
+	assert.Equal(t, "1", store.RootProject.ChildProject.ID, "they should be equal")
+	assert.Equal(t, "3", store.RootProject.BrotherProject.ID, "they should be equal")
+	assert.Equal(t, 0, len(SearchProjectParents(&store, store.FindProject("3"))), "they should be equal")
+}
@@ -1,6 +1,7 @@
 package todoist
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"io"
@@ -8,20 +9,37 @@ import (
 	"net/http"
 	"net/url"
 	"path"
-	"strconv"
 	"strings"
+	"time"
 )
 
 type Config struct {
 	AccessToken string
 	DebugMode   bool
 	Color       bool
+	ReadOnly    bool
+	// Timeout limits each API call, zero means no limit.
+	Timeout time.Duration
+	// Server is the base URL of the API, Server by default.
+	Server string
 }
 
 type Client struct {
 	http.Client
-	config *Config
-	Store  *Store
+	config    *Config
+	Store     *Store
+	RateLimit RateLimit
+	buffering bool
+	buffer    Commands
+	// Executed, if set, is called after commands were accepted by the API
+	// and applied to Store, with tempIDs mapping their temp ids to the ids
+	// of added objects and the store from before.
+	Executed func(commands Commands, tempIDs map[string]string, before Store)
+	// Progress, if set, is called before each request to the API with its
+	// endpoint and, for endpoints returning pages, the number of the page
+	// from 1 on, else 0. The function it returns is called once the request
+	// is done.
+	Progress func(endpoint string, page int) (done func())
 }
 
 func NewClient(config *Config) *Client {
@@ -37,17 +55,40 @@ func (c *Client) Log(format string, v ...interface{}) {
 	}
 }
 
-func (c *Client) doApi(ctx context.Context, method string, uri string, params url.Values, res interface{}) error {
-	c.Log("doAPi: called")
-	u, err := url.Parse(Server)
+// url returns the URL of the endpoint uri.
+func (c *Client) url(uri string) (*url.URL, error) {
+	server := Server
+	if c.config.Server != "" {
+		server = c.config.Server
+	}
+	u, err := url.Parse(server)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	u.Path = path.Join(u.Path, uri)
+	return u, nil
+}
 
-	params.Add("token", c.config.AccessToken)
+func (c *Client) progress(endpoint string, page int) (done func()) {
+	if c.Progress == nil {
+		return func() {}
+	}
+	return c.Progress(endpoint, page)
+}
 
-	c.Log("config: %#v", c.config)
+func (c *Client) doApi(ctx context.Context, method string, uri string, params url.Values, res interface{}) error {
+	return c.doApiPage(ctx, method, uri, params, 0, res)
+}
+
+// doApiPage calls uri for the page numbered page of its results, or 0 if it
+// has none.
+func (c *Client) doApiPage(ctx context.Context, method string, uri string, params url.Values, page int, res interface{}) error {
+	c.Log("doAPi: called")
+	defer c.progress(uri, page)()
+	u, err := c.url(uri)
+	if err != nil {
+		return err
+	}
 
 	var body io.Reader
 	if method == http.MethodGet {
@@ -60,13 +101,46 @@ func (c *Client) doApi(ctx context.Context, method string, uri string, params ur
 	if err != nil {
 		return err
 	}
-
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	c.Log("params: %#v", params)
+
+	return c.do(ctx, req, res)
+}
+
+// doJSON posts body as JSON, which the REST style endpoints expect.
+func (c *Client) doJSON(ctx context.Context, uri string, body interface{}, res interface{}) error {
+	defer c.progress(uri, 0)()
+	u, err := c.url(uri)
+	if err != nil {
+		return err
+	}
+
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, u.String(), bytes.NewReader(buf))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	c.Log("body: %s", buf)
+
+	return c.do(ctx, req, res)
+}
+
+func (c *Client) do(ctx context.Context, req *http.Request, res interface{}) error {
+	if c.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.Timeout)
+		defer cancel()
+	}
+	req.Header.Set("Authorization", "Bearer "+c.config.AccessToken)
 	req = req.WithContext(ctx)
 
-	c.Log("request: %#v", req)
 	c.Log("request.URL: %#v", req.URL)
-	c.Log("params: %#v", body)
 
 	resp, err := c.Do(req)
 	if err != nil {
@@ -76,9 +150,14 @@ func (c *Client) doApi(ctx context.Context, method string, uri string, params ur
 
 	c.Log("response: %#v", resp)
 
+	if rateLimit := ParseRateLimit(resp.Header); rateLimit.Known {
+		c.RateLimit = rateLimit
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		c.Log(ParseAPIError("bad request", resp).Error())
-		return ParseAPIError("bad request", resp)
+		err := ParseAPIError("bad request", resp)
+		c.Log("%s", err.Error())
+		return err
 	} else if res == nil {
 		return nil
 	}
@@ -86,52 +165,167 @@ func (c *Client) doApi(ctx context.Context, method string, uri string, params ur
 }
 
 type ExecResult struct {
-	SyncToken     string      `json:"sync_token"`
-	SyncStatus    interface{} `json:"sync_status"`
-	TempIdMapping interface{} `json:"temp_id_mapping"`
+	SyncToken     string                     `json:"sync_token"`
+	SyncStatus    map[string]json.RawMessage `json:"sync_status"`
+	TempIdMapping map[string]string          `json:"temp_id_mapping"`
+}
+
+// Err returns the error of the first command of commands which failed.
+func (r ExecResult) Err(commands Commands) error {
+	for _, command := range commands {
+		status, ok := r.SyncStatus[command.UUID]
+		if !ok || string(status) == `"ok"` {
+			continue
+		}
+		e := &APIError{Prefix: command.Type, Status: "failed"}
+		json.Unmarshal(status, e)
+		return e
+	}
+	return nil
 }
 
 func (c *Client) ExecCommands(ctx context.Context, commands Commands) error {
+	if c.config.ReadOnly {
+		return ReadOnly
+	}
+	if c.buffering {
+		c.buffer = append(c.buffer, commands...)
+		return nil
+	}
 	var r ExecResult
-	return c.doApi(ctx, http.MethodPost, "sync", commands.UrlValues(), &r)
+	if err := c.doApi(ctx, http.MethodPost, "sync", commands.UrlValues(), &r); err != nil {
+		return err
+	}
+	if err := r.Err(commands); err != nil {
+		return err
+	}
+	if c.Store == nil {
+		return nil
+	}
+	// Apply replaces the contents of the store, so this copy keeps the
+	// objects as they were.
+	before := *c.Store
+	if err := c.Store.Apply(commands, r.TempIdMapping); err != nil {
+		return err
+	}
+	if c.Executed != nil {
+		c.Executed(commands, r.TempIdMapping, before)
+	}
+	return nil
 }
 
-func (c *Client) QuickCommand(ctx context.Context, text string) error {
-	var r ExecResult
+// Buffer makes ExecCommands collect commands instead of sending them, until
+// Flush sends all of them in a single request. Commands can refer to objects
+// added earlier in the same batch by the temp id of the adding command.
+func (c *Client) Buffer() {
+	c.buffering = true
+}
 
-	values := url.Values{
-		"text": {text},
+// Flush sends the buffered commands and stops buffering.
+func (c *Client) Flush(ctx context.Context) error {
+	commands := c.buffer
+	c.buffering = false
+	c.buffer = nil
+	if len(commands) == 0 {
+		return nil
 	}
+	return c.ExecCommands(ctx, commands)
+}
 
-	return c.doApi(ctx, http.MethodPost, "quick/add", values, &r)
+func (c *Client) QuickCommand(ctx context.Context, text string) error {
+	if c.config.ReadOnly {
+		return ReadOnly
+	}
+	var item Item
+	if err := c.doJSON(ctx, "tasks/quick", map[string]string{"text": text}, &item); err != nil {
+		return err
+	}
+	if c.Store != nil && item.ID != "" {
+		before := *c.Store
+		c.Store.Items = append(c.Store.Items[:len(c.Store.Items):len(c.Store.Items)], item)
+		c.Store.ConstructItemTree()
+		if c.Executed != nil {
+			command := NewCommand("item_add", map[string]interface{}{"content": text})
+			c.Executed(Commands{command}, map[string]string{command.TempID: item.ID}, before)
+		}
+	}
+	return nil
 }
 
 func (c *Client) Sync(ctx context.Context) error {
+	store, err := c.Fetch(ctx)
+	if err != nil {
+		return err
+	}
+	if c.Store == nil {
+		c.Store = store
+	} else {
+		*c.Store = *store
+	}
+	return nil
+}
+
+// Fetch returns all data of the account, like Sync, without changing Store.
+func (c *Client) Fetch(ctx context.Context) (*Store, error) {
 	params := url.Values{"sync_token": {"*"}, "resource_types": {"[\"all\"]"}}
 
-	err := c.doApi(ctx, http.MethodPost, "sync", params, &c.Store)
+	var store Store
+	err := c.doApi(ctx, http.MethodPost, "sync", params, &store)
 	if err != nil {
+		return nil, err
+	}
+	c.synced(&store)
+	return &store, nil
+}
+
+// SyncChanges brings Store up to date with what changed since its sync
+// token, which is only those changes instead of all data like Sync. A store
+// without a token is synced fully.
+func (c *Client) SyncChanges(ctx context.Context) error {
+	if c.Store == nil || c.Store.SyncToken == "" {
+		return c.Sync(ctx)
+	}
+	params := url.Values{"sync_token": {c.Store.SyncToken}, "resource_types": {"[\"all\"]"}}
+
+	var changes json.RawMessage
+	if err := c.doApi(ctx, http.MethodPost, "sync", params, &changes); err != nil {
+		return err
+	}
+	if err := c.Store.Merge(changes); err != nil {
 		return err
 	}
-	c.Store.ConstructItemTree()
+	c.synced(c.Store)
 	return nil
 }
 
-func (c *Client) CompleteItemIDByPrefix(prefix string) (id int, err error) {
-	var matchid int = 0
-	for _, cmpid := range c.Store.Items {
-		if strings.HasPrefix(strconv.Itoa(cmpid.GetID()), prefix) {
-			if matchid != 0 {
-				// Ambiguous prefix, return converted input instead
-				return strconv.Atoi(prefix)
-			} else {
-				matchid = cmpid.GetID()
+// synced marks store as just synced.
+func (c *Client) synced(store *Store) {
+	store.CacheVersion = CacheVersion
+	store.LastSync = time.Now()
+	if c.RateLimit.Known {
+		store.RateLimit = c.RateLimit
+	}
+	store.ConstructItemTree()
+}
+
+// CompleteItemIDByPrefix returns the id of the only item whose id starts
+// with prefix, or prefix itself.
+func (c *Client) CompleteItemIDByPrefix(prefix string) string {
+	if c.Store.FindItem(prefix) != nil {
+		return prefix
+	}
+	match := ""
+	for _, item := range c.Store.Items {
+		if strings.HasPrefix(item.GetID(), prefix) {
+			if match != "" {
+				// Ambiguous prefix, return the input instead
+				return prefix
 			}
+			match = item.GetID()
 		}
 	}
-	if matchid != 0 {
-		return matchid, nil
-	} else {
-		return strconv.Atoi(prefix)
+	if match != "" {
+		return match
 	}
+	return prefix
 }
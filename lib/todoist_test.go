@@ -0,0 +1,98 @@
+package todoist
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type blockingTransport struct{}
+
+func (blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+func TestClientTimeout(t *testing.T) {
+	client := NewClient(&Config{Timeout: 10 * time.Millisecond})
+	client.Transport = blockingTransport{}
+	client.Store = &Store{}
+	assert.Error(t, client.Sync(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client = NewClient(&Config{})
+	client.Transport = blockingTransport{}
+	client.Store = &Store{}
+	assert.Error(t, client.Sync(ctx))
+}
+
+type countingTransport struct {
+	http.RoundTripper
+	requests int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	return t.RoundTripper.RoundTrip(req)
+}
+
+func TestClientBuffer(t *testing.T) {
+	sandbox, err := NewSandbox([]byte(`{"projects": [], "items": [{"id": "1", "content": "task", "parent_id": null, "checked": false}]}`))
+	assert.NoError(t, err)
+	transport := &countingTransport{RoundTripper: sandbox}
+	client := NewClient(&Config{})
+	client.Transport = transport
+	client.Store = &Store{}
+	ctx := context.Background()
+
+	client.Buffer()
+	project := NewCommand("project_add", map[string]interface{}{"name": "Work"})
+	assert.NoError(t, client.ExecCommands(ctx, Commands{project}))
+	assert.NoError(t, client.MoveItem(ctx, &Item{BaseItem: BaseItem{HaveID: HaveID{ID: "1"}}}, project.TempID))
+	assert.NoError(t, client.CloseItem(ctx, []string{"1"}))
+	assert.Equal(t, 0, transport.requests, "they should be equal")
+	assert.NoError(t, client.Flush(ctx))
+	assert.Equal(t, 1, transport.requests, "they should be equal")
+
+	assert.NoError(t, client.Sync(ctx))
+	assert.Equal(t, client.Store.Projects.GetIDByName("Work"), client.Store.FindItem("1").ProjectID, "they should be equal")
+	assert.True(t, client.Store.FindItem("1").Checked)
+
+	err = client.CloseItem(ctx, []string{"404"})
+	assert.Error(t, err)
+	assert.Equal(t, "INVALID_ARGUMENT_VALUE", err.(*APIError).Tag, "they should be equal")
+}
+
+type pagingTransport struct{}
+
+// RoundTrip answers with two pages, the first one having a cursor.
+func (pagingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := `{"results": [{"id": "2", "name": "Old"}], "next_cursor": "next"}`
+	if req.URL.Query().Get("cursor") != "" {
+		body = `{"results": [{"id": "3", "name": "Older"}], "next_cursor": null}`
+	}
+	return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: ioutil.NopCloser(strings.NewReader(body)), Request: req}, nil
+}
+
+func TestClientProgress(t *testing.T) {
+	client := NewClient(&Config{})
+	client.Transport = pagingTransport{}
+	client.Store = &Store{}
+	progress := []string{}
+	client.Progress = func(endpoint string, page int) func() {
+		progress = append(progress, fmt.Sprintf("%s %d", endpoint, page))
+		return func() { progress = append(progress, "done") }
+	}
+
+	projects, err := client.ArchivedProjects(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(projects), "they should be equal")
+	assert.Equal(t, []string{"projects/archived 1", "done", "projects/archived 2", "done"}, progress, "they should be equal")
+}
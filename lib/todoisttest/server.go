@@ -0,0 +1,58 @@
+// Package todoisttest provides a fake Todoist API for tests, serving the
+// sync and REST endpoints of the todoist package from memory like the
+// sandbox does.
+package todoisttest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sachaos/todoist/lib"
+)
+
+// Token is the API token the server accepts.
+const Token = "todoisttest"
+
+// Server is an HTTP server faking the Todoist API.
+type Server struct {
+	*httptest.Server
+	Sandbox *todoist.Sandbox
+}
+
+// NewServer starts a server with state, a sync response like the sandbox
+// takes, closed at the end of the test.
+func NewServer(t testing.TB, state string) *Server {
+	t.Helper()
+	sandbox, err := todoist.NewSandbox([]byte(state))
+	if err != nil {
+		t.Fatalf("todoisttest: invalid state: %s", err)
+	}
+	s := &Server{Sandbox: sandbox}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	if strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ") != Token {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error_tag": "AUTH_INVALID_TOKEN", "error": "Invalid token"}`))
+		return
+	}
+	s.Sandbox.ServeHTTP(w, req)
+}
+
+// APIURL is the base URL of the API, for todoist.Config.Server.
+func (s *Server) APIURL() string {
+	return s.URL + "/api/v1/"
+}
+
+// NewClient returns a client of the server with an empty store.
+func (s *Server) NewClient() *todoist.Client {
+	client := todoist.NewClient(&todoist.Config{AccessToken: Token, Server: s.APIURL()})
+	client.Store = &todoist.Store{}
+	return client
+}
@@ -0,0 +1,41 @@
+package todoisttest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer(t *testing.T) {
+	server := NewServer(t, `{
+		"projects": [{"id": "1", "name": "Inbox", "inbox_project": true}],
+		"items": [{"id": "10", "project_id": "1", "content": "existing"}]
+	}`)
+	client := server.NewClient()
+	ctx := context.Background()
+
+	assert.NoError(t, client.Sync(ctx))
+	assert.Equal(t, 1, len(client.Store.Items), "they should be equal")
+
+	assert.NoError(t, client.AddItem(ctx, todoist.Item{BaseItem: todoist.BaseItem{Content: "new"}, DateString: "2020-02-03"}))
+	assert.NoError(t, client.CloseItem(ctx, []string{"10"}))
+	assert.NoError(t, client.Sync(ctx))
+	assert.Equal(t, 2, len(client.Store.Items), "they should be equal")
+	assert.True(t, client.Store.FindItem("10").Checked)
+	for _, item := range client.Store.Items {
+		if item.Content == "new" {
+			assert.Equal(t, "2020-02-03", item.Due.Date, "they should be equal")
+		}
+	}
+
+	legacy := todoist.NewCommand("item_add", map[string]interface{}{"content": "legacy", "date_string": "today"})
+	assert.Error(t, client.ExecCommands(ctx, todoist.Commands{legacy}))
+
+	stranger := todoist.NewClient(&todoist.Config{AccessToken: "wrong", Server: server.APIURL()})
+	err := stranger.Sync(ctx)
+	if assert.Error(t, err) {
+		assert.Equal(t, "AUTH_INVALID_TOKEN", err.(*todoist.APIError).Tag, "they should be equal")
+	}
+}
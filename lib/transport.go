@@ -0,0 +1,64 @@
+package todoist
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+)
+
+// TLSOptions configures the TLS connection to the API, e.g. for proxies
+// which intercept TLS with their own certificate authority.
+type TLSOptions struct {
+	// CAFile is a PEM bundle of certificate authorities trusted in addition
+	// to the system ones.
+	CAFile string
+	// CertFile and KeyFile are a PEM client certificate and its key.
+	CertFile string
+	KeyFile  string
+}
+
+// NewTransport returns an http.Transport like http.DefaultTransport, which
+// honors HTTP_PROXY, HTTPS_PROXY and NO_PROXY, using opts.
+func NewTransport(opts TLSOptions) (*http.Transport, error) {
+	config := &tls.Config{}
+
+	if opts.CAFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		pem, err := ioutil.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", opts.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       config,
+	}, nil
+}
@@ -0,0 +1,40 @@
+package todoist
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTransportCAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	f, err := ioutil.TempFile("", "todoist-ca")
+	assert.NoError(t, err)
+	defer os.Remove(f.Name())
+	assert.NoError(t, pem.Encode(f, &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	f.Close()
+
+	transport, err := NewTransport(TLSOptions{})
+	assert.NoError(t, err)
+	_, err = (&http.Client{Transport: transport}).Get(server.URL)
+	assert.Error(t, err)
+
+	transport, err = NewTransport(TLSOptions{CAFile: f.Name()})
+	assert.NoError(t, err)
+	resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+	assert.NoError(t, err)
+	if resp != nil {
+		assert.Equal(t, http.StatusOK, resp.StatusCode, "they should be equal")
+		resp.Body.Close()
+	}
+
+	_, err = NewTransport(TLSOptions{CAFile: os.DevNull})
+	assert.Error(t, err)
+}
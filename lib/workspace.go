@@ -0,0 +1,53 @@
+package todoist
+
+// Workspace is a team of Todoist Business. Its projects have its id as
+// WorkspaceID, personal projects have none, and they are shared with all its
+// members unless they are invite only.
+type Workspace struct {
+	HaveID
+	IsDeleted bool   `json:"is_deleted"`
+	Name      string `json:"name"`
+}
+
+type Workspaces []Workspace
+
+func (a Workspaces) At(i int) IDCarrier { return a[i] }
+
+func (a Workspaces) GetIDByName(name string) string {
+	for _, workspace := range a {
+		if workspace.Name == name && !workspace.IsDeleted {
+			return workspace.ID
+		}
+	}
+	return ""
+}
+
+// WorkspaceUser is a member of a workspace.
+type WorkspaceUser struct {
+	UserID      string `json:"user_id"`
+	WorkspaceID string `json:"workspace_id"`
+	Email       string `json:"email"`
+	FullName    string `json:"full_name"`
+	Role        string `json:"role"`
+	IsDeleted   bool   `json:"is_deleted"`
+}
+
+func (s *Store) FindWorkspace(id string) *Workspace {
+	for i, workspace := range s.Workspaces {
+		if workspace.ID == id && !workspace.IsDeleted {
+			return &s.Workspaces[i]
+		}
+	}
+	return nil
+}
+
+// IsWorkspaceMember reports whether the user with uid is a member of the
+// workspace.
+func (s *Store) IsWorkspaceMember(workspaceID string, uid string) bool {
+	for _, user := range s.WorkspaceUsers {
+		if user.WorkspaceID == workspaceID && user.UserID == uid && !user.IsDeleted {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/urfave/cli"
+)
+
+// taskURLRegex matches the app URLs of tasks, the old showTask ones as well.
+var taskURLRegex = regexp.MustCompile(`https://(?:app\.)?todoist\.com/(?:app/task/|showTask\?id=)([0-9A-Za-z]+)`)
+
+// taskURL returns the URL of the task with id in the Todoist app.
+func taskURL(id string) string {
+	return "https://app.todoist.com/app/task/" + id
+}
+
+// linkedTaskIDs returns the ids of the tasks the comments of the task with
+// id link to, in the order they were linked.
+func linkedTaskIDs(store *todoist.Store, id string) []string {
+	ids := []string{}
+	seen := map[string]bool{id: true}
+	for _, note := range store.ItemNotes(id) {
+		for _, match := range taskURLRegex.FindAllStringSubmatch(note.Content, -1) {
+			if !seen[match[1]] {
+				ids = append(ids, match[1])
+				seen[match[1]] = true
+			}
+		}
+	}
+	return ids
+}
+
+// linkCommand adds a comment to the task from linking to the task to.
+func linkCommand(from *todoist.Item, to *todoist.Item) todoist.Command {
+	note := todoist.Note{ItemID: from.ID, Content: fmt.Sprintf("Related: [%s](%s)", todoist.GetContentTitle(to), taskURL(to.ID))}
+	return todoist.NewCommand("note_add", note.AddParam())
+}
+
+func Link(c *cli.Context) error {
+	client := GetClient(c)
+
+	if len(c.Args()) != 2 {
+		return ArgumentRequired
+	}
+	items := []*todoist.Item{}
+	for _, arg := range c.Args() {
+		id, err := ResolveItemID(client, arg)
+		if err != nil {
+			return err
+		}
+		item := client.Store.FindItem(id)
+		if item == nil {
+			return IdNotFound
+		}
+		items = append(items, item)
+	}
+	a, b := items[0], items[1]
+	if a.ID == b.ID {
+		return &Error{Code: "invalid_argument", Message: "cannot link a task to itself"}
+	}
+
+	commands := todoist.Commands{}
+	for _, pair := range [][2]*todoist.Item{{a, b}, {b, a}} {
+		linked := false
+		for _, id := range linkedTaskIDs(client.Store, pair[0].ID) {
+			linked = linked || id == pair[1].ID
+		}
+		if !linked {
+			commands = append(commands, linkCommand(pair[0], pair[1]))
+		}
+	}
+	if len(commands) == 0 {
+		return nil
+	}
+
+	if err := client.ExecCommands(GetContext(c), commands); err != nil {
+		return err
+	}
+	return Sync(c)
+}
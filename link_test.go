@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkedTaskIDs(t *testing.T) {
+	store := testStore(t, `{
+		"items": [
+			{"id": "1", "content": "a"},
+			{"id": "2", "content": "b"}
+		],
+		"notes": [
+			{"id": "10", "item_id": "1", "content": "Related: [b](https://app.todoist.com/app/task/2)"},
+			{"id": "11", "item_id": "1", "content": "see https://todoist.com/showTask?id=6X7rM8997g3RQmvh and https://app.todoist.com/app/task/2"},
+			{"id": "12", "item_id": "1", "content": "and itself https://app.todoist.com/app/task/1"}
+		]
+	}`)
+	assert.Equal(t, []string{"2", "6X7rM8997g3RQmvh"}, linkedTaskIDs(store, "1"), "they should be equal")
+	assert.Equal(t, []string{}, linkedTaskIDs(store, "2"), "they should be equal")
+
+	command := linkCommand(store.FindItem("2"), store.FindItem("1"))
+	assert.Equal(t, "note_add", command.Type, "they should be equal")
+	assert.Equal(t, map[string]interface{}{"item_id": "2", "content": "Related: [a](https://app.todoist.com/app/task/1)"}, command.Args, "they should be equal")
+}
@@ -2,16 +2,286 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"sort"
+	"strings"
+
 	"github.com/sachaos/todoist/lib"
 	"github.com/urfave/cli"
-	"os"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
 )
 
+// View describes which tasks list shows and how.
+type View struct {
+	Filter  string   `mapstructure:"filter"`
+	Sort    string   `mapstructure:"sort"`
+	Columns []string `mapstructure:"columns"`
+	GroupBy string   `mapstructure:"group_by"`
+	// AssignedTo is who the tasks of shared projects are assigned to, as
+	// taken by resolveAssignee.
+	AssignedTo string `mapstructure:"assigned_to"`
+	// Limit is the most tasks shown, 0 being all of them.
+	Limit int `mapstructure:"limit"`
+	// Workspace is the workspace whose tasks are shown, as taken by
+	// resolveWorkspace, the one in use by default.
+	Workspace string `mapstructure:"workspace"`
+}
+
+type listColumn struct {
+	header string
+	format func(item *todoist.Item, depth int) string
+}
+
+var defaultListColumns = []string{"id", "priority", "due", "project", "labels", "content"}
+
+func listColumns(c *cli.Context, store *todoist.Store) map[string]listColumn {
+	colorList := ColorList()
+	projectIds := make([]string, len(store.Projects))
+	for i, project := range store.Projects {
+		projectIds[i] = project.GetID()
+	}
+	projectColorHash := GenerateColorHash(projectIds, colorList)
+	noteCounts := map[string]int{}
+	attachmentCounts := map[string]int{}
+	for _, note := range store.Notes {
+		if !note.IsDeleted {
+			noteCounts[note.ItemID]++
+			if note.FileAttachment != nil && note.FileAttachment.FileURL != "" {
+				attachmentCounts[note.ItemID]++
+			}
+		}
+	}
+	reminderCounts := map[string]int{}
+	for _, reminder := range store.Reminders {
+		if !reminder.IsDeleted {
+			reminderCounts[reminder.ItemID]++
+		}
+	}
+
+	return map[string]listColumn{
+		"id": {"ID", func(item *todoist.Item, depth int) string {
+			return IdFormat(item)
+		}},
+		"priority": {"Priority", func(item *todoist.Item, depth int) string {
+			return PriorityFormat(item.Priority)
+		}},
+		"due": {"DueDate", func(item *todoist.Item, depth int) string {
+			return DueDateFormat(item.DateTime(), item.AllDay)
+		}},
+		"project": {"Project", func(item *todoist.Item, depth int) string {
+			return ProjectFormat(item.ProjectID, store, projectColorHash, c)
+		}},
+		"labels": {"Labels", func(item *todoist.Item, depth int) string {
+			return LabelsFormat(item, store)
+		}},
+		"comments": {"Comments", func(item *todoist.Item, depth int) string {
+			return CommentCountFormat(noteCounts[item.ID])
+		}},
+		"icons": {"Icons", func(item *todoist.Item, depth int) string {
+			return IconsFormat(item, reminderCounts[item.ID], noteCounts[item.ID], attachmentCounts[item.ID])
+		}},
+		"content": {"Content", func(item *todoist.Item, depth int) string {
+			return ContentPrefix(store, item, depth, c) + ContentFormat(item)
+		}},
+	}
+}
+
+// itemSortKeys maps a sort or group-by key to a function returning a
+// comparable value.
+var itemSortKeys = map[string]func(store *todoist.Store, item *todoist.Item) string{
+	"id": func(store *todoist.Store, item *todoist.Item) string {
+		return item.ID
+	},
+	"priority": func(store *todoist.Store, item *todoist.Item) string {
+		return fmt.Sprintf("%d", priorityMapping[item.Priority])
+	},
+	"due": func(store *todoist.Store, item *todoist.Item) string {
+		if item.Due == nil {
+			// Tasks without due date come last.
+			return "~"
+		}
+		return item.DateTime().UTC().Format("2006-01-02T15:04:05")
+	},
+	"project": func(store *todoist.Store, item *todoist.Item) string {
+		if project := store.FindProject(item.ProjectID); project != nil {
+			return project.Name
+		}
+		return ""
+	},
+	"labels": func(store *todoist.Store, item *todoist.Item) string {
+		return item.LabelsString(store)
+	},
+	"content": func(store *todoist.Store, item *todoist.Item) string {
+		return strings.ToLower(todoist.GetContentTitle(item))
+	},
+}
+
+// contentCollator, when set by the sort_locale config, compares contents by
+// the rules of a language instead of naturalCompare.
+var contentCollator *collate.Collator
+
+// naturalCompare compares a and b like strings.Compare, except for runs of
+// digits, which are compared by their value, so that "Task 2" comes before
+// "Task 10".
+func naturalCompare(a string, b string) int {
+	for a != "" && b != "" {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			i, j := digitsEnd(a), digitsEnd(b)
+			x, y := strings.TrimLeft(a[:i], "0"), strings.TrimLeft(b[:j], "0")
+			if len(x) != len(y) {
+				if len(x) < len(y) {
+					return -1
+				}
+				return 1
+			}
+			if c := strings.Compare(x, y); c != 0 {
+				return c
+			}
+			a, b = a[i:], b[j:]
+			continue
+		}
+		if a[0] != b[0] {
+			if a[0] < b[0] {
+				return -1
+			}
+			return 1
+		}
+		a, b = a[1:], b[1:]
+	}
+	return strings.Compare(a, b)
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func digitsEnd(s string) int {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return i
+}
+
+// setSortLocale makes sorting by content follow the rules of the language
+// locale, like "de" or "sv", ignoring case and comparing numbers by value.
+func setSortLocale(locale string) error {
+	if locale == "" {
+		contentCollator = nil
+		return nil
+	}
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return &Error{Code: "invalid_config", Message: fmt.Sprintf("invalid sort_locale %q", locale), Hint: "use a language tag like en, de or sv"}
+	}
+	contentCollator = collate.New(tag, collate.IgnoreCase, collate.Numeric)
+	return nil
+}
+
+func compareSortKey(key string, a string, b string) int {
+	if key == "content" && contentCollator != nil {
+		return contentCollator.CompareString(a, b)
+	}
+	return naturalCompare(a, b)
+}
+
+// sortItems sorts items by the first of keys, then the next for those equal
+// and so on. Items equal in all keys are ordered by id, so that the order
+// doesn't change with the order tasks come from the API in.
+func sortItems(store *todoist.Store, items []listedItem, keys []string) {
+	sort.SliceStable(items, func(i, j int) bool {
+		for _, key := range keys {
+			a, b := itemSortKeys[key](store, items[i].item), itemSortKeys[key](store, items[j].item)
+			if c := compareSortKey(key, a, b); c != 0 {
+				return c < 0
+			}
+		}
+		return naturalCompare(items[i].item.ID, items[j].item.ID) < 0
+	})
+}
+
+// limitItems returns the first n of items, or all of them if n is 0.
+func limitItems(items []listedItem, n int) []listedItem {
+	if n > 0 && len(items) > n {
+		return items[:n]
+	}
+	return items
+}
+
+type listedItem struct {
+	item  *todoist.Item
+	depth int
+}
+
+// listLayout returns how tasks are listed, flat or as a tree, which the
+// older --indent stands for.
+func listLayout(c *cli.Context) (string, error) {
+	layout := c.GlobalString("view")
+	if c.GlobalBool("indent") && !c.GlobalIsSet("view") {
+		layout = "tree"
+	}
+	if layout != "flat" && layout != "tree" {
+		return "", &Error{Code: "invalid_argument", Message: fmt.Sprintf("unknown view %q", layout), Hint: "use flat or tree"}
+	}
+	return layout, nil
+}
+
+// itemTree orders items as a tree: each item follows its nearest ancestor
+// among items one level deeper, siblings keeping their order in items. Items
+// without one, like those whose parent is filtered out or in another
+// project, are at the top, and a cycle of parents is broken at its first
+// item.
+func itemTree(store *todoist.Store, items []listedItem) []listedItem {
+	listed := map[string]bool{}
+	for _, l := range items {
+		listed[l.item.ID] = true
+	}
+	children := map[string][]*todoist.Item{}
+	roots := []*todoist.Item{}
+	for _, l := range items {
+		parents := todoist.SearchItemParents(store, l.item)
+		parentID := ""
+		for i := len(parents) - 1; i >= 0; i-- {
+			if listed[parents[i].ID] {
+				parentID = parents[i].ID
+				break
+			}
+		}
+		if parentID == "" {
+			roots = append(roots, l.item)
+		} else {
+			children[parentID] = append(children[parentID], l.item)
+		}
+	}
+
+	tree := make([]listedItem, 0, len(items))
+	added := map[string]bool{}
+	var add func(item *todoist.Item, depth int)
+	add = func(item *todoist.Item, depth int) {
+		if added[item.ID] {
+			return
+		}
+		added[item.ID] = true
+		tree = append(tree, listedItem{item: item, depth: depth})
+		for _, child := range children[item.ID] {
+			add(child, depth+1)
+		}
+	}
+	for _, root := range roots {
+		add(root, 0)
+	}
+	for _, l := range items {
+		add(l.item, 0)
+	}
+	return tree
+}
+
 func traverseItems(item *todoist.Item, f func(item *todoist.Item, depth int), depth int) {
 	f(item, depth)
 
 	if item.ChildItem != nil {
-		traverseItems(item.ChildItem, f, depth + 1)
+		traverseItems(item.ChildItem, f, depth+1)
 	}
 
 	if item.BrotherItem != nil {
@@ -19,53 +289,216 @@ func traverseItems(item *todoist.Item, f func(item *todoist.Item, depth int), de
 	}
 }
 
-func List(c *cli.Context) error {
-	client := GetClient(c)
+// FilterItems returns the unchecked items matching ex in tree order.
+func FilterItems(store *todoist.Store, ex Expression) []*todoist.Item {
+	items := []*todoist.Item{}
+	for _, listed := range filterListedItems(store, ex) {
+		items = append(items, listed.item)
+	}
+	return items
+}
 
-	colorList := ColorList()
-	projectsCount := len(client.Store.Projects)
-	projectIds := make([]int, projectsCount)
-	for i, project := range client.Store.Projects {
-		projectIds[i] = project.GetID()
+func filterListedItems(store *todoist.Store, ex Expression) []listedItem {
+	items := []listedItem{}
+	if store.RootItem == nil {
+		return items
 	}
-	projectColorHash := GenerateColorHash(projectIds, colorList)
-	ex := Filter(c.String("filter"))
+	ex = resolveSearch(ex, store)
+	traverseItems(store.RootItem, func(item *todoist.Item, depth int) {
+		r, err := Eval(ex, item, store.Projects, store.Labels)
+		if err != nil || !r || item.Checked {
+			return
+		}
+		items = append(items, listedItem{item: item, depth: depth})
+	}, 0)
+	return items
+}
 
-	itemList := [][]string{}
-	rootItem := client.Store.RootItem
+func validateKey(kind string, key string) error {
+	if _, ok := itemSortKeys[key]; !ok {
+		return &Error{Code: "invalid_argument", Message: fmt.Sprintf("unknown %s key %q", kind, key), Hint: "use one of id, priority, due, project, labels, content"}
+	}
+	return nil
+}
 
-	if rootItem == nil {
+// ShowView writes the tasks selected by view.
+func ShowView(c *cli.Context, view View) error {
+	client := GetClient(c)
+	store := client.Store
+
+	filter, err := ApplyContext(view.Filter)
+	if err != nil {
+		return err
+	}
+	ex := Filter(filter)
+	if err := ReadCacheItems(default_cache_path, store, ex); err != nil {
+		return err
+	}
+
+	if store.RootItem == nil {
 		fmt.Fprintln(os.Stderr, "There is no task. You can fetch latest tasks by `todoist sync`.")
 		return nil
 	}
 
-	traverseItems(rootItem, func(item *todoist.Item, depth int) {
-		r, err := Eval(ex, item, client.Store.Projects, client.Store.Labels)
+	names := view.Columns
+	if len(names) == 0 {
+		names = defaultListColumns
+	}
+	if icons != nil {
+		// The icons go first, unless the columns place them.
+		placed := false
+		for _, name := range names {
+			placed = placed || name == "icons"
+		}
+		if !placed {
+			names = append([]string{"icons"}, names...)
+		}
+	}
+	columns := listColumns(c, store)
+	for _, name := range names {
+		if _, ok := columns[name]; !ok {
+			return &Error{Code: "invalid_argument", Message: fmt.Sprintf("unknown column %q", name), Hint: "use any of " + strings.Join(append(append([]string{}, defaultListColumns...), "comments", "icons"), ", ")}
+		}
+	}
+
+	layout, err := listLayout(c)
+	if err != nil {
+		return err
+	}
+
+	if view.Limit < 0 {
+		return &Error{Code: "invalid_argument", Message: fmt.Sprintf("invalid limit %d", view.Limit), Hint: "use a positive number of tasks, or 0 for all"}
+	}
+
+	items := filterListedItems(store, ex)
+	if view.AssignedTo != "" {
+		id, err := resolveAssignee(store, view.AssignedTo)
 		if err != nil {
-			return
+			return err
 		}
-		if !r || item.Checked == 1 {
-			return
+		items = filterAssigned(items, id)
+	}
+	workspaceID, scoped, err := workspaceScope(store, view.Workspace)
+	if err != nil {
+		return err
+	}
+	if scoped {
+		items = filterWorkspace(store, items, workspaceID)
+	}
+
+	if view.Sort != "" {
+		keys := strings.Split(view.Sort, ",")
+		for _, key := range keys {
+			if err := validateKey("sort", key); err != nil {
+				return err
+			}
 		}
-		itemList = append(itemList, []string{
-			IdFormat(item),
-			PriorityFormat(item.Priority),
-			DueDateFormat(item.DateTime(), item.AllDay),
-			ProjectFormat(item.ProjectID, client.Store, projectColorHash, c),
-			item.LabelsString(client.Store),
-			ContentPrefix(client.Store, item, depth, c) + ContentFormat(item),
+		sortItems(store, items, keys)
+	}
+
+	header := []string{}
+	var group func(store *todoist.Store, item *todoist.Item) string
+	if view.GroupBy != "" {
+		if err := validateKey("group-by", view.GroupBy); err != nil {
+			return err
+		}
+		group = itemSortKeys[view.GroupBy]
+		sort.SliceStable(items, func(i, j int) bool {
+			return compareSortKey(view.GroupBy, group(store, items[i].item), group(store, items[j].item)) < 0
 		})
-	}, 0)
+		header = append(header, columns[view.GroupBy].header)
+	}
+	if layout == "tree" {
+		// With groups, the tree is the one of the tasks of each group.
+		tree := make([]listedItem, 0, len(items))
+		for start := 0; start < len(items); {
+			end := start + 1
+			for group != nil && end < len(items) && group(store, items[end].item) == group(store, items[start].item) {
+				end++
+			}
+			if group == nil {
+				end = len(items)
+			}
+			tree = append(tree, itemTree(store, items[start:end])...)
+			start = end
+		}
+		items = tree
+	} else {
+		for i := range items {
+			items[i].depth = 0
+		}
+	}
+	items = limitItems(items, view.Limit)
+	for _, name := range names {
+		header = append(header, columns[name].header)
+	}
 
-	defer writer.Flush()
+	itemList := [][]string{}
+	contentColumn := -1
+	previousGroup := ""
+	for i, listed := range items {
+		record := []string{}
+		if group != nil {
+			value := columns[view.GroupBy].format(listed.item, 0)
+			// Only tables for humans leave repeated group names out.
+			if i > 0 && value == previousGroup && (outputFormat == "tsv" || outputFormat == "table") {
+				record = append(record, "")
+			} else {
+				record = append(record, value)
+			}
+			previousGroup = value
+		}
+		for _, name := range names {
+			if name == "content" {
+				contentColumn = len(record)
+			}
+			record = append(record, columns[name].format(listed.item, listed.depth))
+		}
+		itemList = append(itemList, record)
+	}
 
-	if c.GlobalBool("header") {
-		writer.Write([]string{"ID", "Priority", "DueDate", "Project", "Labels", "Content"})
+	if contentColumn >= 0 {
+		TruncateColumn(itemList, contentColumn, OutputWidth(c))
 	}
 
+	defer writer.Flush()
+
+	writer.WriteHeader(header)
+
 	for _, strings := range itemList {
 		writer.Write(strings)
 	}
 
 	return nil
 }
+
+// deferItems reports whether the items of the cache are to be read by the
+// filter of the command, for list with a database cache.
+func deferItems(c *cli.Context, sandbox bool) bool {
+	if sandbox || !isDatabaseCache(default_cache_path) {
+		return false
+	}
+	if _, err := os.Stat(default_cache_path); err != nil {
+		return false
+	}
+	command := c.App.Command(c.Args().First())
+	return command != nil && command.Name == "list"
+}
+
+func List(c *cli.Context) error {
+	if c.Bool("include-archived-projects") {
+		// The tasks of archived projects join those of the cache.
+		if err := ReadCacheItems(default_cache_path, GetClient(c).Store, nil); err != nil {
+			return err
+		}
+		if err := includeArchived(GetContext(c), GetClient(c), true); err != nil {
+			return err
+		}
+	}
+	view := View{Filter: c.String("filter"), Sort: c.String("sort"), AssignedTo: c.String("assigned-to"), Limit: c.Int("limit"), Workspace: c.String("workspace")}
+	if c.Bool("comments") {
+		// The comment count goes right before the content it is about.
+		view.Columns = append(append(append([]string{}, defaultListColumns[:len(defaultListColumns)-1]...), "comments"), "content")
+	}
+	return ShowView(c, view)
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortItems(t *testing.T) {
+	store := testStore(t, `{"items": [
+		{"id": "12", "content": "b", "priority": 1, "due": {"date": "2020-01-02"}},
+		{"id": "100", "content": "a", "priority": 1, "due": {"date": "2020-01-02"}},
+		{"id": "11", "content": "c", "priority": 4, "due": {"date": "2020-01-02"}},
+		{"id": "9", "content": "a", "priority": 1, "due": {"date": "2020-01-02"}},
+		{"id": "10", "content": "d", "priority": 1, "due": {"date": "2020-01-01"}}
+	]}`)
+	items := []listedItem{}
+	for i := range store.Items {
+		items = append(items, listedItem{item: &store.Items[i]})
+	}
+
+	sortItems(store, items, []string{"due", "priority", "content"})
+	ids := []string{}
+	for _, listed := range items {
+		ids = append(ids, listed.item.ID)
+	}
+	assert.Equal(t, []string{"10", "11", "9", "100", "12"}, ids, "they should be equal")
+}
+
+func TestNaturalCompare(t *testing.T) {
+	assert.Equal(t, -1, naturalCompare("task 2", "task 10"), "they should be equal")
+	assert.Equal(t, 1, naturalCompare("task 10", "task 9"), "they should be equal")
+	assert.Equal(t, -1, naturalCompare("task 02a", "task 2b"), "they should be equal")
+	assert.Equal(t, -1, naturalCompare("abc", "abd"), "they should be equal")
+	assert.Equal(t, 0, naturalCompare("v1.2", "v1.2"), "they should be equal")
+}
+
+func TestSortItemsLocale(t *testing.T) {
+	store := testStore(t, `{"items": [
+		{"id": "1", "content": "Zucker"},
+		{"id": "2", "content": "Äpfel 10"},
+		{"id": "3", "content": "äpfel 9"},
+		{"id": "4", "content": "Birnen"}
+	]}`)
+	items := []listedItem{}
+	for i := range store.Items {
+		items = append(items, listedItem{item: &store.Items[i]})
+	}
+	ids := func() []string {
+		ids := []string{}
+		for _, listed := range items {
+			ids = append(ids, listed.item.ID)
+		}
+		return ids
+	}
+
+	sortItems(store, items, []string{"content"})
+	assert.Equal(t, []string{"4", "1", "3", "2"}, ids(), "they should be equal")
+
+	assert.NoError(t, setSortLocale("de"))
+	defer setSortLocale("")
+	sortItems(store, items, []string{"content"})
+	assert.Equal(t, []string{"3", "2", "4", "1"}, ids(), "they should be equal")
+}
+
+func TestLimitItems(t *testing.T) {
+	items := make([]listedItem, 5)
+	assert.Equal(t, 3, len(limitItems(items, 3)), "they should be equal")
+	assert.Equal(t, 5, len(limitItems(items, 10)), "they should be equal")
+	assert.Equal(t, 5, len(limitItems(items, 0)), "they should be equal")
+}
+
+func TestCommentsColumn(t *testing.T) {
+	store := testStore(t, `{
+		"items": [{"id": "1", "content": "a"}, {"id": "2", "content": "b"}],
+		"notes": [
+			{"id": "10", "item_id": "1", "content": "x"},
+			{"id": "11", "item_id": "1", "content": "y"},
+			{"id": "12", "item_id": "1", "content": "deleted", "is_deleted": true}
+		]
+	}`)
+	columns := listColumns(nil, store)
+	assert.Equal(t, "✎ 2", columns["comments"].format(store.FindItem("1"), 0), "they should be equal")
+	assert.Equal(t, "", columns["comments"].format(store.FindItem("2"), 0), "they should be equal")
+}
+
+func TestItemTree(t *testing.T) {
+	store := testStore(t, `{"items": [
+		{"id": "1", "content": "root"},
+		{"id": "2", "content": "child", "parent_id": "1"},
+		{"id": "3", "content": "grandchild", "parent_id": "2"},
+		{"id": "4", "content": "orphan", "parent_id": "99"},
+		{"id": "5", "content": "cycle a", "parent_id": "6"},
+		{"id": "6", "content": "cycle b", "parent_id": "5"},
+		{"id": "7", "content": "other child", "parent_id": "1"}
+	]}`)
+	tree := func(ids ...string) []string {
+		items := []listedItem{}
+		for _, id := range ids {
+			items = append(items, listedItem{item: store.FindItem(id)})
+		}
+		lines := []string{}
+		for _, listed := range itemTree(store, items) {
+			lines = append(lines, strings.Repeat(" ", listed.depth)+listed.item.ID)
+		}
+		return lines
+	}
+
+	// Siblings keep their order, like a sort gave it.
+	assert.Equal(t, []string{"1", " 7", " 2", "  3", "4"}, tree("7", "3", "1", "4", "2"), "they should be equal")
+	// Without the parent, its children go under the nearest ancestor shown.
+	assert.Equal(t, []string{"1", " 3", " 7"}, tree("1", "3", "7"), "they should be equal")
+	assert.Equal(t, []string{"3", "7"}, tree("3", "7"), "they should be equal")
+	// A cycle is broken at its first item.
+	assert.Equal(t, []string{"5", " 6"}, tree("5", "6"), "they should be equal")
+}
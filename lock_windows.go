@@ -0,0 +1,40 @@
+//go:build windows
+// +build windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32         = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx   = kernel32.NewProc("LockFileEx")
+	procUnlockFileEx = kernel32.NewProc("UnlockFileEx")
+)
+
+const lockfileExclusiveLock = 0x2
+
+func lockFile(f *os.File, exclusive bool) error {
+	var flags uintptr
+	if exclusive {
+		flags = lockfileExclusiveLock
+	}
+	var overlapped syscall.Overlapped
+	r, _, err := procLockFileEx.Call(f.Fd(), flags, 0, 1, 0, uintptr(unsafe.Pointer(&overlapped)))
+	if r == 0 {
+		return err
+	}
+	return nil
+}
+
+func unlockFile(f *os.File) error {
+	var overlapped syscall.Overlapped
+	r, _, err := procUnlockFileEx.Call(f.Fd(), 0, 1, 0, uintptr(unsafe.Pointer(&overlapped)))
+	if r == 0 {
+		return err
+	}
+	return nil
+}
@@ -1,12 +1,13 @@
 package main
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"os"
 	"runtime"
+	"strings"
+	"time"
 
-	"encoding/csv"
 	"encoding/json"
 	"io/ioutil"
 	"path/filepath"
@@ -20,9 +21,11 @@ import (
 var (
 	configPath, _      = os.UserHomeDir()
 	default_cache_path = filepath.Join(configPath, ".todoist.cache.json")
-	CommandFailed      = errors.New("command failed")
-	IdNotFound         = errors.New("specified id not found")
 	writer             Writer
+	pager              *Pager
+	spinner            *Spinner
+	outputFormat       string
+	cancelContext      context.CancelFunc
 )
 
 const (
@@ -33,11 +36,75 @@ const (
 	ShortDateFormat     = "06/01/02(Mon)"
 )
 
+// setConfigFile points v at the config file, which is $TODOIST_CONFIG if set,
+// and returns its path.
+func setConfigFile(v *viper.Viper) string {
+	v.SetConfigType(configType)
+	if path := os.Getenv("TODOIST_CONFIG"); path != "" {
+		v.SetConfigFile(path)
+		return path
+	}
+	v.SetConfigName(configName)
+	v.AddConfigPath(configPath)
+	v.AddConfigPath(".")
+	return filepath.Join(configPath, configName+"."+configType)
+}
+
 func GetClient(c *cli.Context) *todoist.Client {
 	return c.App.Metadata["client"].(*todoist.Client)
 }
 
-func main() {
+// GetContext returns the context of API calls, which is cancelled on SIGINT.
+func GetContext(c *cli.Context) context.Context {
+	return c.App.Metadata["context"].(context.Context)
+}
+
+// colorFlag is the value of --color, which may be given without one to mean
+// always, like before it took a mode.
+type colorFlag struct {
+	mode string
+}
+
+func (f *colorFlag) Set(mode string) error {
+	f.mode = mode
+	return nil
+}
+
+func (f *colorFlag) String() string {
+	return f.mode
+}
+
+// IsBoolFlag has the flag package take --color alone as --color=true.
+func (f *colorFlag) IsBoolFlag() bool {
+	return true
+}
+
+// ColorEnabled decides whether output is colorized. An explicit flag wins over
+// the NO_COLOR environment variable, which wins over the config file.
+func ColorEnabled(flag string, config string) (bool, error) {
+	mode := flag
+	if mode == "" && os.Getenv("NO_COLOR") != "" {
+		mode = "never"
+	}
+	if mode == "" {
+		mode = config
+	}
+
+	switch mode {
+	case "always", "true":
+		return true, nil
+	case "never", "false":
+		return false, nil
+	case "auto", "":
+		return isTerminal(os.Stdout), nil
+	default:
+		return false, fmt.Errorf("invalid color mode %q (expected auto, always or never)", mode)
+	}
+}
+
+// newApp returns the todoist command line app, writing its output to
+// app.Writer.
+func newApp() *cli.App {
 	app := cli.NewApp()
 	app.Name = "todoist"
 	app.Usage = "Todoist CLI Client"
@@ -47,16 +114,16 @@ func main() {
 		Name:  "content, c",
 		Usage: "content",
 	}
-	priorityFlag := cli.IntFlag{
+	priorityFlag := cli.StringFlag{
 		Name:  "priority, p",
-		Value: 4,
-		Usage: "priority (1-4)",
+		Value: "p4",
+		Usage: "priority (p1-p4, p1 is the highest)",
 	}
 	labelIDsFlag := cli.StringFlag{
 		Name:  "label-ids, L",
 		Usage: "label ids (separated by ,)",
 	}
-	projectIDFlag := cli.IntFlag{
+	projectIDFlag := cli.StringFlag{
 		Name:  "project-id, P",
 		Usage: "project id",
 	}
@@ -72,10 +139,26 @@ func main() {
 		Name:  "browse, o",
 		Usage: "when contain URL, open it",
 	}
+	projectFlag := cli.StringFlag{
+		Name:  "project",
+		Usage: "project name",
+	}
+	favoritesFlag := cli.BoolFlag{
+		Name:  "favorites",
+		Usage: "only show favorites",
+	}
 	filterFlag := cli.StringFlag{
 		Name:  "filter, f",
 		Usage: "filter expression",
 	}
+	workspaceFlag := cli.StringFlag{
+		Name:  "workspace",
+		Usage: "only the projects of a workspace, or personal for those outside of any (default: the one in use)",
+	}
+	yesFlag := cli.BoolFlag{
+		Name:  "yes, y",
+		Usage: "do not ask for confirmation",
+	}
 	reminderFlg := cli.BoolFlag{
 		Name:  "reminder, r",
 		Usage: "set reminder (only premium users)",
@@ -86,13 +169,45 @@ func main() {
 			Name:  "header",
 			Usage: "output with header",
 		},
-		cli.BoolFlag{
+		cli.GenericFlag{
 			Name:  "color",
-			Usage: "colorize output",
+			Value: &colorFlag{},
+			Usage: "colorize output, --color=auto, --color=always or --color=never, always without a mode",
+		},
+		cli.StringFlag{
+			Name:  "theme",
+			Usage: "colors for a dark or light terminal background (auto, dark, light)",
+		},
+		cli.BoolFlag{
+			Name:  "icons",
+			Usage: "prefix tasks with icons for priority, recurrence, reminders, comments and attachments",
+		},
+		cli.StringFlag{
+			Name:  "icon-set",
+			Usage: "icons to prefix tasks with, implying --icons (nerd, emoji, ascii) (default: emoji, or ascii in a locale which isn't UTF-8)",
+		},
+		cli.StringFlag{
+			Name:  "output",
+			Value: "tsv",
+			Usage: "output format (" + strings.Join(WriterNames(), ", ") + ")",
+		},
+		cli.BoolFlag{
+			Name:  "zebra",
+			Usage: "shade alternate rows in table output",
 		},
 		cli.BoolFlag{
-			Name:  "csv",
-			Usage: "output in CSV format",
+			Name:   "csv",
+			Usage:  "output in CSV format (same as --output csv)",
+			Hidden: true,
+		},
+		cli.BoolFlag{
+			Name:   "json",
+			Usage:  "output in JSON format (same as --output json)",
+			Hidden: true,
+		},
+		cli.BoolFlag{
+			Name:  "schema",
+			Usage: "output the JSON Schema of the JSON output of the command instead of running it",
 		},
 		cli.BoolFlag{
 			Name:  "debug",
@@ -102,33 +217,100 @@ func main() {
 			Name:  "namespace",
 			Usage: "display parent task like namespace",
 		},
+		cli.StringFlag{
+			Name:  "view",
+			Value: "flat",
+			Usage: "list tasks flat, or as a tree with subtasks indented under their parents (flat, tree)",
+		},
 		cli.BoolFlag{
-			Name:  "indent",
-			Usage: "display children task with indent",
+			Name:   "indent",
+			Usage:  "same as --view tree",
+			Hidden: true,
 		},
 		cli.BoolFlag{
 			Name:  "project-namespace",
 			Usage: "display parent project like namespace",
 		},
+		cli.IntFlag{
+			Name:  "max-width",
+			Usage: "truncate content to fit in this width (default: terminal width)",
+		},
+		cli.BoolFlag{
+			Name:  "no-truncate",
+			Usage: "do not truncate long content",
+		},
+		cli.BoolFlag{
+			Name:  "no-pager",
+			Usage: "do not pipe long output into $PAGER",
+		},
+		cli.BoolFlag{
+			Name:  "a11y",
+			Usage: "output for screen readers, without colors, icons or tables, and with priorities and due dates in words",
+		},
+		cli.BoolFlag{
+			Name:  "porcelain",
+			Usage: "output for scripts, without colors, pager or progress",
+		},
+		cli.BoolFlag{
+			Name:  "sandbox",
+			Usage: "use a local fake account with demo data instead of Todoist",
+		},
+		cli.StringFlag{
+			Name:  "cache-path",
+			Usage: "use the cache in `FILE` (default: one per account in $HOME)",
+		},
+		cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "give up on an API call after this long, e.g. 30s (default: no timeout)",
+		},
+		cli.StringFlag{
+			Name:  "record",
+			Usage: "save API requests and responses into `DIR`",
+		},
+		cli.StringFlag{
+			Name:  "replay",
+			Usage: "answer API requests with the responses saved by --record in `DIR`",
+		},
+		cli.BoolFlag{
+			Name:  "read-only",
+			Usage: "refuse to run commands which change data",
+		},
 	}
 
-	app.Before = func(c *cli.Context) error {
-		var store todoist.Store
+	before := func(c *cli.Context) error {
+		sandbox := c.Bool("sandbox")
 
-		if err := LoadCache(default_cache_path, &store); err != nil {
-			return err
+		// First, so that errors from here on are in the format asked for.
+		outputFormat = c.String("output")
+		if c.Bool("csv") {
+			outputFormat = "csv"
+		}
+		if c.Bool("json") {
+			outputFormat = "json"
+		}
+		// Schemas are written from a table, without the account or the
+		// cache.
+		if c.Bool("schema") || c.Args().First() == "schema" {
+			return nil
 		}
 
-		viper.SetConfigType(configType)
-		viper.SetConfigName(configName)
-		viper.AddConfigPath(configPath)
-		viper.AddConfigPath(".")
+		viper.SetDefault("pager", true)
+		viper.SetDefault("trash_project", "Trash")
+		viper.SetDefault("trash_purge_days", 30)
+		viper.BindEnv("token", "TODOIST_TOKEN")
+		viper.BindEnv("api_url", "TODOIST_API_URL")
+		if sandbox {
+			// The sandbox needs no account, so do not ask for a token.
+			viper.SetDefault("token", "sandbox")
+		}
+		configFile := setConfigFile(viper.GetViper())
 
 		var token string
 
-		configFile := filepath.Join(configPath, configName+"."+configType)
-
-		if err := viper.ReadInConfig(); err != nil {
+		if err := viper.ReadInConfig(); err != nil && viper.GetString("token") != "" {
+			// The token comes from $TODOIST_TOKEN, so no config file is needed.
+			configFile = ""
+		} else if err != nil {
 			fmt.Printf("Input API Token: ")
 			fmt.Scan(&token)
 			viper.Set("token", token)
@@ -144,34 +326,204 @@ func main() {
 
 		// Ensure that the config file has permission 0600, because it contains
 		// the API token and should only be read by the user.
-		fi, err := os.Lstat(configFile)
+		if configFile != "" {
+			fi, err := os.Lstat(configFile)
+			if err != nil {
+				panic(fmt.Errorf("Fatal error config file: %s \n", err))
+			}
+			if fi.Mode().Perm() != 0600 {
+				panic(fmt.Errorf("Config file has wrong permissions. Make sure to give permissions 600 to file %s \n", configFile))
+			}
+		}
+
+		useColor, err := ColorEnabled(c.String("color"), viper.GetString("color"))
 		if err != nil {
-			panic(fmt.Errorf("Fatal error config file: %s \n", err))
+			return err
 		}
-		if fi.Mode().Perm() != 0600 {
-			panic(fmt.Errorf("Config file has wrong permissions. Make sure to give permissions 600 to file %s \n", configFile))
+		if c.Bool("porcelain") {
+			useColor = false
+		}
+		screenReader := c.Bool("a11y") || viper.GetBool("a11y")
+		if screenReader {
+			useColor = false
+			if outputFormat == "tsv" || outputFormat == "table" {
+				outputFormat = "linear"
+			}
+		}
+		// JSON and YAML are for programs, which want the same values
+		// either way.
+		a11y = screenReader && outputFormat != "json" && outputFormat != "yaml"
+		if theme, err = ThemeFor(c.String("theme"), viper.GetString("theme")); err != nil {
+			return err
+		}
+		if theme, err = theme.WithDueColors(viper.GetStringMapString("due_colors")); err != nil {
+			return err
+		}
+		if icons, err = IconSetFor(c.Bool("icons") || viper.GetBool("icons"), c.String("icon-set"), viper.GetString("icon_set")); err != nil {
+			return err
+		}
+		if screenReader {
+			// Icons are glyphs, which screen readers skip or read oddly.
+			icons = nil
+		}
+		if err := setSortLocale(viper.GetString("sort_locale")); err != nil {
+			return err
 		}
 
-		config := &todoist.Config{AccessToken: viper.GetString("token"), DebugMode: c.Bool("debug"), Color: viper.GetBool("color")}
+		if !sandbox {
+			token, err = APIToken()
+			if err != nil {
+				return err
+			}
+		}
+
+		switch {
+		case sandbox:
+			default_cache_path = sandboxCachePath
+		case c.String("cache-path") != "":
+			default_cache_path = c.String("cache-path")
+		case os.Getenv("TODOIST_CACHE") != "":
+			default_cache_path = os.Getenv("TODOIST_CACHE")
+		case viper.GetString("cache_path") != "":
+			default_cache_path = viper.GetString("cache_path")
+		default:
+			default_cache_path, err = accountCachePath(token, viper.GetString("cache_backend"))
+			if err != nil {
+				return err
+			}
+		}
+
+		var store todoist.Store
+		outdated := false
+		// Reading the cache is most of the time capture would take, and it
+		// doesn't need it. The sandbox keeps its data in the cache.
+		if c.Args().First() != "capture" || sandbox {
+			if deferItems(c, sandbox) {
+				// Only the tasks the filter may list are read, by the
+				// indexes of the database.
+				err = ReadCacheWithoutItems(default_cache_path, &store)
+			} else {
+				outdated, err = LoadCache(default_cache_path, &store)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		config := &todoist.Config{
+			AccessToken: token,
+			DebugMode:   c.Bool("debug"),
+			Color:       useColor,
+			ReadOnly:    c.Bool("read-only") || viper.GetBool("read_only"),
+			Timeout:     c.Duration("timeout"),
+			Server:      viper.GetString("api_url"),
+		}
+
+		ctx, cancel := interruptContext()
+		cancelContext = cancel
 
 		client := todoist.NewClient(config)
+		tlsOptions := todoist.TLSOptions{
+			CAFile:   viper.GetString("ca_file"),
+			CertFile: viper.GetString("client_cert_file"),
+			KeyFile:  viper.GetString("client_key_file"),
+		}
+		if tlsOptions != (todoist.TLSOptions{}) {
+			transport, err := todoist.NewTransport(tlsOptions)
+			if err != nil {
+				return err
+			}
+			client.Transport = transport
+		}
+		if sandbox {
+			client, err = NewSandboxClient(config, &store)
+			if err != nil {
+				return err
+			}
+		}
+		if dir := c.String("replay"); dir != "" {
+			replayer, err := todoist.NewReplayer(dir)
+			if err != nil {
+				return err
+			}
+			client.Transport = replayer
+		} else if dir := c.String("record"); dir != "" {
+			client.Transport = todoist.NewRecorder(dir, client.Transport)
+		}
 		client.Store = &store
+		// Progress goes to stderr, where it would only garble logs and
+		// the output captured by scripts, and screen readers would read
+		// every frame.
+		if !c.Bool("porcelain") && !screenReader && !c.Bool("debug") && isTerminal(os.Stderr) {
+			spinner = NewSpinner(os.Stderr)
+			client.Progress = spinner.Progress
+		}
+		if !sandbox && c.String("replay") == "" {
+			client.Executed = recordHistory(client, append([]string{"todoist"}, os.Args[1:]...))
+		}
+		if sandbox && store.SyncToken == "" {
+			if err := client.Sync(ctx); err != nil {
+				return err
+			}
+			if err := WriteCache(default_cache_path, client.Store); err != nil {
+				return err
+			}
+		} else if outdated && c.String("replay") == "" {
+			// Ids changed with the API, nothing in the old cache can be used.
+			fmt.Fprintln(os.Stderr, "Rebuilding the cache for the new Todoist API...")
+			if err := client.Sync(ctx); err != nil {
+				return CacheMigrationFailed(err)
+			}
+			if err := WriteCache(default_cache_path, client.Store); err != nil {
+				return err
+			}
+		}
 
 		app.Metadata = map[string]interface{}{
-			"client": client,
-			"config": config,
+			"client":  client,
+			"config":  config,
+			"context": ctx,
 		}
 
-		if !c.Bool("color") && !config.Color {
+		color.NoColor = !config.Color
+
+		if outputFormat == "json" || outputFormat == "yaml" {
 			color.NoColor = true
 		}
 
-		if c.Bool("csv") {
-			writer = csv.NewWriter(os.Stdout)
-		} else if runtime.GOOS == "windows" && !color.NoColor {
-			writer = NewTSVWriter(color.Output)
-		} else {
-			writer = NewTSVWriter(os.Stdout)
+		output := c.App.Writer
+		if runtime.GOOS == "windows" && !color.NoColor && output == os.Stdout {
+			output = color.Output
+		}
+		if !c.Bool("no-pager") && !c.Bool("porcelain") && viper.GetBool("pager") && runtime.GOOS != "windows" && output == os.Stdout && isTerminal(os.Stdout) {
+			if pager, err = NewPager(os.Stdout); err != nil {
+				return err
+			}
+			output = pager
+		}
+
+		writer, err = NewWriter(outputFormat, output, WriterOptions{Header: c.Bool("header"), Zebra: c.Bool("zebra")})
+		return err
+	}
+	app.Before = func(c *cli.Context) error {
+		err := before(c)
+		if err != nil && outputFormat == "json" {
+			// The help urfave/cli shows after an error here would break the
+			// JSON.
+			c.App.Writer = ioutil.Discard
+		}
+		return err
+	}
+
+	app.After = func(c *cli.Context) error {
+		if cancelContext != nil {
+			cancelContext()
+		}
+		if spinner != nil {
+			spinner.Stop()
+		}
+		if pager != nil {
+			return pager.Close()
 		}
 		return nil
 	}
@@ -184,8 +536,239 @@ func main() {
 			Action:  List,
 			Flags: []cli.Flag{
 				filterFlag,
+				cli.StringFlag{
+					Name:  "sort",
+					Usage: "sort by keys separated by , (id, priority, due, project, labels, content)",
+				},
+				cli.StringFlag{
+					Name:  "assigned-to",
+					Usage: "only show tasks assigned to me, unassigned, or a collaborator by name or email",
+				},
+				cli.IntFlag{
+					Name:  "limit",
+					Usage: "show only the first n tasks after filtering and sorting (0 = all)",
+				},
+				cli.BoolFlag{
+					Name:  "comments",
+					Usage: "show how many comments tasks have",
+				},
+				cli.BoolFlag{
+					Name:  "include-archived-projects",
+					Usage: "also show the tasks of archived projects, fetched from Todoist",
+				},
+				workspaceFlag,
+			},
+		},
+		{
+			Name:      "view",
+			Usage:     "Show tasks with a view defined in the config, or list the views",
+			ArgsUsage: "[name]",
+			Action:    ShowNamedView,
+		},
+		{
+			Name:   "context",
+			Usage:  "Show, switch or clear the context applied to task lists",
+			Action: ShowContext,
+			Subcommands: []cli.Command{
+				{
+					Name:   "list",
+					Usage:  "List the contexts defined in the config",
+					Action: ListContexts,
+				},
+				{
+					Name:      "use",
+					Usage:     "Apply the filter of a context to task lists",
+					ArgsUsage: "<name>",
+					Action:    UseContext,
+				},
+				{
+					Name:    "clear",
+					Aliases: []string{"none"},
+					Usage:   "Stop applying a context",
+					Action:  ClearContext,
+				},
+			},
+		},
+		{
+			Name:   "diff",
+			Usage:  "Show how the tasks on Todoist differ from the cache, without syncing",
+			Action: Diff,
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "accept",
+					Usage: "update the cache afterwards, like sync",
+				},
+			},
+		},
+		{
+			Name:   "history",
+			Usage:  "Show the changes made with todoist",
+			Action: History,
+			Flags: []cli.Flag{
+				cli.IntFlag{
+					Name:  "limit, n",
+					Usage: "show only the last n invocations (0 = all)",
+				},
+			},
+		},
+		{
+			Name:  "cleanup",
+			Usage: "Prune old data from the cache and the history",
+			Subcommands: []cli.Command{
+				{
+					Name:   "completed",
+					Usage:  "Move old completed tasks and history entries into an archive file",
+					Action: CleanupCompleted,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "older-than",
+							Value: "90d",
+							Usage: "prune what was completed or done longer ago than this, e.g. 90d or 12w",
+						},
+						cli.StringFlag{
+							Name:  "archive",
+							Usage: "append what is pruned to this file (default: ~/.todoist.archive.jsonl)",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:      "search",
+			Usage:     "Show tasks whose content, description or comments contain the words",
+			ArgsUsage: "<words>",
+			Action:    Search,
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "regex",
+					Usage: "take the words as a regular expression matched against content and description",
+				},
+			},
+		},
+		{
+			Name:   "pick",
+			Usage:  "Show one task picked at random",
+			Action: Pick,
+			Flags: []cli.Flag{
+				filterFlag,
+				cli.BoolFlag{
+					Name:  "weighted",
+					Usage: "pick urgent and overdue tasks more often",
+				},
+			},
+		},
+		{
+			Name:   "next",
+			Usage:  "Show the one task to do right now: overdue first, then by due time, then by priority",
+			Action: Next,
+			Flags: []cli.Flag{
+				filterFlag,
+			},
+		},
+		{
+			Name:      "focus",
+			Usage:     "Focus on a task for a while, marking it in progress, then close or postpone it",
+			ArgsUsage: "<task>",
+			Action:    Focus,
+			Flags: []cli.Flag{
+				cli.DurationFlag{
+					Name:  "for",
+					Value: 25 * time.Minute,
+					Usage: "how long to focus",
+				},
+			},
+		},
+		{
+			Name:  "plan",
+			Usage: "Plan the day",
+			Subcommands: []cli.Command{
+				{
+					Name:   "today",
+					Usage:  "Timebox today's tasks into the free time left, setting their due times",
+					Action: PlanToday,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "filter, f",
+							Value: "today | overdue",
+							Usage: "filter the tasks to plan",
+						},
+						cli.StringFlag{
+							Name:  "from",
+							Value: "9:00",
+							Usage: "when the day starts",
+						},
+						cli.StringFlag{
+							Name:  "to",
+							Value: "18:00",
+							Usage: "when the day ends",
+						},
+						cli.DurationFlag{
+							Name:  "duration",
+							Value: 30 * time.Minute,
+							Usage: "time of tasks without duration",
+						},
+						yesFlag,
+					},
+				},
+			},
+		},
+		{
+			Name:   "suggest-schedule",
+			Usage:  "Propose due dates for undated tasks and set them",
+			Action: SuggestScheduleCommand,
+			Flags: []cli.Flag{
+				filterFlag,
+				yesFlag,
+			},
+		},
+		{
+			Name:      "open",
+			Usage:     "Open a task in the Todoist app, or a file attached to its comments",
+			ArgsUsage: "<task>",
+			Action:    Open,
+			Flags: []cli.Flag{
+				cli.IntFlag{
+					Name:  "attachment",
+					Usage: "open the attachment with this number, as listed by show",
+				},
+				cli.BoolFlag{
+					Name:  "download",
+					Usage: "save the attachment into the current directory instead of opening it",
+				},
+				cli.StringFlag{
+					Name:  "out",
+					Usage: "save the attachment to this file",
+				},
 			},
 		},
+		{
+			Name:  "attachments",
+			Usage: "Download the files attached to comments",
+			Subcommands: []cli.Command{
+				{
+					Name:   "pull",
+					Usage:  "Download every file attached to the tasks of a project",
+					Action: PullAttachments,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "project",
+							Usage: "name of the project",
+						},
+						cli.StringFlag{
+							Name:  "out",
+							Value: ".",
+							Usage: "directory to save the files into",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:      "link",
+			Usage:     "Link two related tasks with a comment on each",
+			ArgsUsage: "<task> <task>",
+			Action:    Link,
+		},
 		{
 			Name:   "show",
 			Usage:  "Show task detail",
@@ -215,6 +798,18 @@ func main() {
 				projectNameFlag,
 				dateFlag,
 				reminderFlg,
+				cli.StringFlag{
+					Name:  "workspace",
+					Usage: "look the project name up in a workspace, or personal (default: the one in use)",
+				},
+				cli.StringFlag{
+					Name:  "url",
+					Usage: "add a task named after the title of the web page, with the URL as description and the url_label label (default: read-later)",
+				},
+				cli.StringFlag{
+					Name:  "from-eml",
+					Usage: "make the task of an email file, or - for stdin: the subject as content, sender and date as description, and the text as a comment",
+				},
 			},
 		},
 		{
@@ -229,6 +824,39 @@ func main() {
 				projectIDFlag,
 				projectNameFlag,
 				dateFlag,
+				cli.BoolFlag{
+					Name:  "remove-recurrence",
+					Usage: "allow --date to replace the recurrence of a recurring task",
+				},
+				cli.BoolFlag{
+					Name:  "force",
+					Usage: "modify the task even if it changed on Todoist since the last sync",
+				},
+			},
+		},
+		{
+			Name:      "delegate",
+			Usage:     "Assign a task of a shared project to a collaborator",
+			ArgsUsage: "<task> <collaborator>",
+			Action:    Delegate,
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "unassign",
+					Usage: "remove the assignment of the task instead",
+				},
+			},
+		},
+		{
+			Name:   "duplicate",
+			Usage:  "Duplicate task with its subtasks and comments",
+			Action: Duplicate,
+			Flags: []cli.Flag{
+				cli.IntFlag{
+					Name:  "count",
+					Value: 1,
+					Usage: "number of copies",
+				},
+				projectFlag,
 			},
 		},
 		{
@@ -236,43 +864,525 @@ func main() {
 			Aliases: []string{"c"},
 			Usage:   "Close task",
 			Action:  Close,
+			Flags: []cli.Flag{
+				yesFlag,
+			},
+		},
+		{
+			Name:   "dedupe",
+			Usage:  "Find similar tasks and close or delete the duplicates",
+			Action: Dedupe,
+			Flags: []cli.Flag{
+				yesFlag,
+				cli.StringFlag{
+					Name:  "project",
+					Usage: "only look for duplicates in the project with this name",
+				},
+				cli.BoolFlag{
+					Name:  "delete",
+					Usage: "delete the duplicates instead of closing them",
+				},
+				cli.BoolFlag{
+					Name:  "merge",
+					Usage: "fold the comments, labels and earliest due date of the duplicates into the task kept before closing them",
+				},
+			},
 		},
 		{
 			Name:    "delete",
 			Aliases: []string{"d"},
 			Usage:   "Delete task",
 			Action:  Delete,
+			Flags: []cli.Flag{
+				yesFlag,
+				cli.BoolFlag{
+					Name:  "to-trash",
+					Usage: "move the task into the trash project instead of deleting it",
+				},
+			},
+		},
+		{
+			Name:   "restore",
+			Usage:  "Restore task from the trash project",
+			Action: Restore,
+		},
+		{
+			Name:   "workspace",
+			Usage:  "Show, list, switch or clear the workspace commands are scoped to",
+			Action: ShowWorkspace,
+			Subcommands: []cli.Command{
+				{
+					Name:   "list",
+					Usage:  "List the workspaces you are a member of",
+					Action: ListWorkspaces,
+				},
+				{
+					Name:      "use",
+					Usage:     "Scope projects, task lists and adds to a workspace, or personal",
+					ArgsUsage: "<name>",
+					Action:    UseWorkspace,
+				},
+				{
+					Name:    "clear",
+					Aliases: []string{"none"},
+					Usage:   "Stop scoping to a workspace",
+					Action:  ClearWorkspace,
+				},
+			},
+		},
+		{
+			Name:   "reminders",
+			Usage:  "Show the upcoming reminders of all tasks",
+			Action: Reminders,
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "today",
+					Usage: "only show the reminders going off today",
+				},
+			},
+			Subcommands: []cli.Command{
+				{
+					Name:      "snooze",
+					Usage:     "Make a reminder go off again later, e.g. 30m or 2h",
+					ArgsUsage: "<reminder id> <duration>",
+					Action:    SnoozeReminder,
+				},
+			},
 		},
 		{
 			Name:   "labels",
 			Usage:  "Show all labels",
 			Action: Labels,
+			Flags: []cli.Flag{
+				favoritesFlag,
+			},
+			Subcommands: []cli.Command{
+				{
+					Name:      "set-color",
+					Usage:     "Set the color of a label",
+					ArgsUsage: "<name> <color>",
+					Action:    SetLabelColor,
+				},
+				{
+					Name:   "stats",
+					Usage:  "Show the open, overdue and recently completed tasks of each label",
+					Action: LabelsStats,
+				},
+				{
+					Name:   "prune",
+					Usage:  "Delete the labels no open task has",
+					Action: PruneLabels,
+					Flags: []cli.Flag{
+						yesFlag,
+					},
+				},
+			},
 		},
 		{
 			Name:   "projects",
 			Usage:  "Show all projects",
 			Action: Projects,
+			Flags: []cli.Flag{
+				favoritesFlag,
+				cli.BoolFlag{
+					Name:  "flat",
+					Usage: "list the projects without drawing their tree",
+				},
+				cli.BoolFlag{
+					Name:  "archived",
+					Usage: "also show archived projects, fetched from Todoist",
+				},
+				workspaceFlag,
+			},
+			Subcommands: []cli.Command{
+				{
+					Name:      "members",
+					Usage:     "Show who a project is shared with, their role and how many open tasks they are assigned",
+					ArgsUsage: "<name>",
+					Action:    ProjectMembers,
+				},
+				{
+					Name:      "set-color",
+					Usage:     "Set the color of a project",
+					ArgsUsage: "<name> <color>",
+					Action:    SetProjectColor,
+				},
+				{
+					Name:      "copy",
+					Usage:     "Copy a project with its sections and tasks",
+					ArgsUsage: "<source> <new name>",
+					Action:    CopyProject,
+					Flags: []cli.Flag{
+						cli.BoolFlag{
+							Name:  "include-completed",
+							Usage: "also copy completed tasks",
+						},
+						cli.BoolFlag{
+							Name:  "include-comments",
+							Usage: "also copy comments",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:   "filters",
+			Usage:  "Show all filters",
+			Action: Filters,
+			Flags: []cli.Flag{
+				favoritesFlag,
+			},
+			Subcommands: []cli.Command{
+				{
+					Name:      "check",
+					Usage:     "Check a filter for syntax errors and words which match no task",
+					ArgsUsage: "<filter>",
+					Action:    CheckFilterCommand,
+				},
+				{
+					Name:      "explain",
+					Usage:     "Show the clauses of a filter and how many cached tasks each of them matches",
+					ArgsUsage: "<filter>",
+					Action:    ExplainFilterCommand,
+				},
+			},
+		},
+		{
+			Name:      "favorite",
+			Usage:     "Toggle favorite status of a project, label or filter",
+			ArgsUsage: "<project|label|filter> <name>",
+			Action:    Favorite,
 		},
 		{
 			Name:   "karma",
 			Usage:  "Show karma",
 			Action: Karma,
+			Subcommands: []cli.Command{
+				{
+					Name:      "vacation",
+					Usage:     "Show or switch vacation mode, which keeps streaks while away",
+					ArgsUsage: "[on|off]",
+					Action:    KarmaVacation,
+				},
+			},
+		},
+		{
+			Name:  "stats",
+			Usage: "Show statistics of your tasks",
+			Subcommands: []cli.Command{
+				{
+					Name:   "flow",
+					Usage:  "Compare the tasks added and completed by the week, and the trend of the backlog",
+					Action: StatsFlow,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "since",
+							Value: "90d",
+							Usage: "how far back to look, like 90d or 12w",
+						},
+					},
+				},
+			},
 		},
 		{
 			Name:    "sync",
 			Aliases: []string{"s"},
 			Usage:   "Sync cache",
 			Action:  Sync,
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "daemon",
+					Usage: "keep running and sync the cache periodically",
+				},
+				cli.DurationFlag{
+					Name:  "interval",
+					Value: 5 * time.Minute,
+					Usage: "sync interval of the daemon",
+				},
+			},
+		},
+		{
+			Name:  "recurrence",
+			Usage: "Work with recurring due dates",
+			Subcommands: []cli.Command{
+				{
+					Name:      "preview",
+					Usage:     "Show the next dates of a recurring due date like \"every 2nd friday\", without a task",
+					ArgsUsage: "<recurrence>",
+					Action:    RecurrencePreview,
+					Flags: []cli.Flag{
+						cli.IntFlag{
+							Name:  "count",
+							Value: 10,
+							Usage: "number of dates to show",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:   "schedule",
+			Usage:  "List, add or remove the jobs running todoist commands on a cron schedule",
+			Action: ListSchedule,
+			Subcommands: []cli.Command{
+				{
+					Name:      "add",
+					Usage:     "Add a job running a todoist command, like \"0 8 * * *\" \"sync\"",
+					ArgsUsage: "<cron> <command>",
+					Action:    AddSchedule,
+				},
+				{
+					Name:      "remove",
+					Usage:     "Remove a job",
+					ArgsUsage: "<job id>",
+					Action:    RemoveSchedule,
+				},
+				{
+					Name:   "run",
+					Usage:  "Keep running and run the jobs when they are due",
+					Action: RunSchedule,
+				},
+			},
+		},
+		{
+			Name:   "serve",
+			Usage:  "Serve the tasks, projects and labels of the cache as JSON over HTTP",
+			Action: Serve,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "host",
+					Value: "localhost",
+					Usage: "address to listen on, \"\" for all of them",
+				},
+				cli.IntFlag{
+					Name:  "port",
+					Value: 7000,
+					Usage: "port to listen on",
+				},
+				cli.DurationFlag{
+					Name:  "interval",
+					Value: 5 * time.Minute,
+					Usage: "how often the tasks are synced",
+				},
+				cli.BoolFlag{
+					Name:  "allow-writes",
+					Usage: "allow adding and closing tasks with serve_token of the config",
+				},
+			},
+		},
+		{
+			Name:   "serve-feed",
+			Usage:  "Serve the tasks matching a filter as an Atom feed",
+			Action: ServeFeed,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "filter",
+					Usage: "serve the tasks matching this filter (default: all of them)",
+				},
+				cli.StringFlag{
+					Name:  "host",
+					Value: "localhost",
+					Usage: "address to listen on, \"\" for all of them",
+				},
+				cli.IntFlag{
+					Name:  "port",
+					Value: 8123,
+					Usage: "port to listen on",
+				},
+				cli.DurationFlag{
+					Name:  "interval",
+					Value: 5 * time.Minute,
+					Usage: "how often the tasks are synced",
+				},
+			},
+		},
+		{
+			Name:   "api-status",
+			Usage:  "Show rate limit and cache status",
+			Action: ApiStatus,
+		},
+		{
+			Name:   "self-update",
+			Usage:  "Update todoist to the latest release, checking the download for corruption but not its signature",
+			Action: SelfUpdate,
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "check",
+					Usage: "only report whether an update is available",
+				},
+			},
+		},
+		{
+			Name:  "export",
+			Usage: "Export tasks",
+			Subcommands: []cli.Command{
+				{
+					Name:   "html",
+					Usage:  "Export tasks as a standalone HTML report",
+					Action: ExportHTML,
+					Flags: []cli.Flag{
+						filterFlag,
+						cli.StringFlag{
+							Name:  "title",
+							Value: "Todoist",
+							Usage: "report title",
+						},
+						cli.StringFlag{
+							Name:  "out",
+							Usage: "write the report to this file instead of stdout",
+						},
+					},
+				},
+				{
+					Name:   "timesheet",
+					Usage:  "Export completed tasks per day as CSV, for billing",
+					Action: ExportTimesheet,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "since",
+							Value: "monday",
+							Usage: "first day: today, yesterday, a weekday of this week, or 2006-01-02",
+						},
+						cli.StringFlag{
+							Name:  "until",
+							Value: "today",
+							Usage: "last day, taking the same values as --since",
+						},
+						cli.StringFlag{
+							Name:  "project",
+							Usage: "only tasks of the project with this name",
+						},
+						cli.StringFlag{
+							Name:  "out",
+							Usage: "write the timesheet to this file instead of stdout",
+						},
+					},
+				},
+				{
+					Name:   "trello",
+					Usage:  "Export a project as a Trello board in JSON",
+					Action: ExportTrello,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "project",
+							Usage: "the project with this name",
+						},
+						cli.StringFlag{
+							Name:  "out",
+							Usage: "write the board to this file instead of stdout",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:  "import",
+			Usage: "Import tasks from other tools",
+			Subcommands: []cli.Command{
+				{
+					Name:      "trello",
+					Usage:     "Import a Trello board exported as JSON into a new project",
+					ArgsUsage: "<board.json>",
+					Action:    ImportTrello,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "project",
+							Usage: "name of the new project (default: the name of the board)",
+						},
+					},
+				},
+				{
+					Name:      "mstodo",
+					Usage:     "Import the lists and tasks of Microsoft To Do, Outlook tasks included",
+					ArgsUsage: "<export.json>",
+					Action:    ImportMSTodo,
+					Flags: []cli.Flag{
+						cli.BoolFlag{
+							Name:  "no-reminders",
+							Usage: "leave the reminders out (they are only available to premium users)",
+						},
+					},
+				},
+				{
+					Name:      "asana",
+					Usage:     "Import the tasks of an Asana project exported as CSV",
+					ArgsUsage: "<export.csv>",
+					Action:    ImportAsana,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "project",
+							Usage: "add the tasks to the project with this name (default: their project in Asana)",
+						},
+					},
+				},
+				{
+					Name:   "github",
+					Usage:  "Add a task for each open issue of a GitHub repository not imported before",
+					Action: ImportGitHub,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "repo",
+							Usage: "the repository, as owner/name",
+						},
+						cli.StringFlag{
+							Name:  "label",
+							Usage: "only the issues with this label, or all of these separated by ,",
+						},
+						cli.StringFlag{
+							Name:  "project",
+							Usage: "add the tasks to the project with this name (default: the inbox)",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:      "capture",
+			Usage:     "Add a thought to the Inbox as fast as possible, with one quick add request and without the cache",
+			ArgsUsage: "<text>",
+			Action:    Capture,
 		},
 		{
 			Name:    "quick",
 			Aliases: []string{"q"},
-			Usage:   "Quick add a task",
+			Usage:   "Quick add a task, or one task per line of stdin",
 			Action:  Quick,
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "editor, e",
+					Usage: "write the tasks in $EDITOR, one per line",
+				},
+			},
+		},
+		{
+			Name:            "schema",
+			Usage:           "Show the JSON Schema of the JSON output of a command",
+			ArgsUsage:       "<command> [arguments...]",
+			Action:          Schema,
+			SkipFlagParsing: true,
+		},
+		{
+			Name:      "_complete",
+			Usage:     "Print completions from the cache, for shells and launchers",
+			ArgsUsage: "tasks|projects|labels",
+			Action:    Complete,
+			Hidden:    true,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "prefix",
+					Usage: "only those whose value or description starts with this, ignoring case",
+				},
+			},
 		},
 	}
-	if err := app.Run(os.Args); err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
+	app.Commands = schemaActions(app.Commands, nil)
+	return app
+}
+
+func main() {
+	app := newApp()
+	if err := app.Run(CommandDefaults(app, os.Args)); err != nil {
+		PrintError(os.Stderr, err)
 		os.Exit(1)
 	}
 }
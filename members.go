@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/urfave/cli"
+)
+
+// ProjectMember is someone a project is shared with.
+type ProjectMember struct {
+	todoist.Collaborator
+	Role string
+	// Tasks is how many open tasks of the project are assigned to them.
+	Tasks int
+}
+
+// projectMembers joins the collaborators of the project with id with the
+// tasks assigned to them. The members of its workspace come after those
+// invited, unless it is invite only.
+func projectMembers(store *todoist.Store, id string) []ProjectMember {
+	members := []ProjectMember{}
+	seen := map[string]bool{}
+	add := func(collaborator todoist.Collaborator, role string) {
+		if seen[collaborator.ID] {
+			return
+		}
+		seen[collaborator.ID] = true
+		members = append(members, ProjectMember{Collaborator: collaborator, Role: role})
+	}
+
+	for _, state := range store.CollaboratorStates {
+		if state.ProjectID != id || state.State != "active" {
+			continue
+		}
+		collaborator := todoist.Collaborator{ID: state.UserID}
+		if found := store.FindCollaborator(state.UserID); found != nil {
+			collaborator = *found
+		}
+		add(collaborator, state.Role)
+	}
+	if project := store.FindProject(id); project != nil && project.WorkspaceID != "" && !project.IsInviteOnly {
+		for _, user := range store.WorkspaceUsers {
+			if user.WorkspaceID == project.WorkspaceID && !user.IsDeleted {
+				add(todoist.Collaborator{ID: user.UserID, Email: user.Email, FullName: user.FullName}, user.Role)
+			}
+		}
+	}
+
+	index := map[string]int{}
+	for i, member := range members {
+		index[member.ID] = i
+	}
+	for _, item := range store.Items {
+		if item.ProjectID != id || item.Checked {
+			continue
+		}
+		if i, ok := index[item.ResponsibleID()]; ok {
+			members[i].Tasks++
+		}
+	}
+	return members
+}
+
+// RoleFormat turns an API role like READ_WRITE into read write.
+func RoleFormat(role string) string {
+	return strings.ToLower(strings.ReplaceAll(role, "_", " "))
+}
+
+func ProjectMembers(c *cli.Context) error {
+	client := GetClient(c)
+
+	if !c.Args().Present() {
+		return ArgumentRequired
+	}
+	name := c.Args().First()
+	id := client.Store.Projects.GetIDByName(name)
+	if id == "" {
+		return ProjectNotFound(name)
+	}
+
+	members := projectMembers(client.Store, id)
+	if len(members) == 0 {
+		fmt.Fprintln(os.Stderr, "The project is not shared.")
+		return nil
+	}
+
+	defer writer.Flush()
+
+	writer.WriteHeader([]string{"ID", "Name", "Email", "Role", "Tasks"})
+
+	for _, member := range members {
+		writer.Write([]string{
+			member.ID,
+			member.FullName,
+			member.Email,
+			RoleFormat(member.Role),
+			strconv.Itoa(member.Tasks),
+		})
+	}
+	return nil
+}
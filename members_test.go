@@ -0,0 +1,52 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectMembers(t *testing.T) {
+	store := testStore(t, `{
+		"projects": [
+			{"id": "1", "name": "Work"},
+			{"id": "2", "name": "Roadmap", "workspace_id": "51"}
+		],
+		"items": [
+			{"id": "10", "project_id": "1", "content": "a", "responsible_uid": "2"},
+			{"id": "11", "project_id": "1", "content": "b", "responsible_uid": "2"},
+			{"id": "12", "project_id": "1", "content": "done", "responsible_uid": "2", "checked": true},
+			{"id": "13", "project_id": "2", "content": "other project", "responsible_uid": "2"},
+			{"id": "14", "project_id": "1", "content": "unassigned"}
+		],
+		"collaborators": [
+			{"id": "1", "full_name": "Me"},
+			{"id": "2", "full_name": "Alex Kim", "email": "alex@example.com"}
+		],
+		"collaborator_states": [
+			{"project_id": "1", "user_id": "1", "state": "active", "role": "CREATOR"},
+			{"project_id": "1", "user_id": "2", "state": "active", "role": "READ_WRITE"},
+			{"project_id": "1", "user_id": "3", "state": "deleted", "role": "READ_ONLY"},
+			{"project_id": "2", "user_id": "2", "state": "active", "role": "READ_WRITE"}
+		],
+		"workspace_users": [
+			{"user_id": "2", "workspace_id": "51", "full_name": "Alex Kim", "role": "MEMBER"},
+			{"user_id": "4", "workspace_id": "51", "full_name": "Jo Park", "role": "GUEST"}
+		]
+	}`)
+
+	members := projectMembers(store, "1")
+	assert.Equal(t, 2, len(members), "they should be equal")
+	assert.Equal(t, "Alex Kim", members[1].FullName, "they should be equal")
+	assert.Equal(t, "read write", RoleFormat(members[1].Role), "they should be equal")
+	assert.Equal(t, 0, members[0].Tasks, "they should be equal")
+	assert.Equal(t, 2, members[1].Tasks, "they should be equal")
+
+	// Invited members keep their role, the others of the workspace follow.
+	members = projectMembers(store, "2")
+	assert.Equal(t, 2, len(members), "they should be equal")
+	assert.Equal(t, "READ_WRITE", members[0].Role, "they should be equal")
+	assert.Equal(t, 1, members[0].Tasks, "they should be equal")
+	assert.Equal(t, "Jo Park", members[1].FullName, "they should be equal")
+	assert.Equal(t, "GUEST", members[1].Role, "they should be equal")
+}
@@ -2,21 +2,141 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"strconv"
-	"strings"
 
+	"github.com/sachaos/todoist/lib"
 	"github.com/urfave/cli"
 )
 
+// fieldChange is a field of a task which differs between two versions of
+// it.
+type fieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+func (f fieldChange) String() string {
+	if f.Old == "" {
+		return f.New
+	}
+	return fmt.Sprintf("%s %s -> %s", f.Field, f.Old, f.New)
+}
+
+// modifyFields are the fields of a task modify changes, with their values as
+// shown to the user.
+var modifyFields = []struct {
+	name  string
+	value func(store *todoist.Store, item *todoist.Item) string
+}{
+	{"content", func(store *todoist.Store, item *todoist.Item) string {
+		return strconv.Quote(item.Content)
+	}},
+	{"priority", func(store *todoist.Store, item *todoist.Item) string {
+		return fmt.Sprintf("p%d", priorityMapping[item.Priority])
+	}},
+	{"due", func(store *todoist.Store, item *todoist.Item) string {
+		return dueString(item)
+	}},
+	{"project", func(store *todoist.Store, item *todoist.Item) string {
+		return projectName(store, item.ProjectID)
+	}},
+	{"labels", func(store *todoist.Store, item *todoist.Item) string {
+		return item.LabelsString(store)
+	}},
+}
+
+// fetchStale fetches the account and returns it with the fields of the
+// cached item which were changed on Todoist since the last sync. A task
+// completed, reopened or deleted meanwhile is an error.
+func fetchStale(ctx context.Context, client *todoist.Client, item *todoist.Item) (*todoist.Store, []fieldChange, error) {
+	remote, err := client.Fetch(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	current := remote.FindItem(item.ID)
+	if current == nil {
+		return nil, nil, StaleTask(item.ID, "completed or deleted")
+	}
+	if current.Checked != item.Checked {
+		return nil, nil, StaleTask(item.ID, diffItem(client.Store, remote, item, current)[0].Change)
+	}
+	changes := []fieldChange{}
+	for _, field := range modifyFields {
+		old, new := field.value(client.Store, item), field.value(remote, current)
+		if old != new {
+			changes = append(changes, fieldChange{Field: field.name, Old: old, New: new})
+		}
+	}
+	if len(changes) == 0 && item.UpdatedAt != "" && current.UpdatedAt != item.UpdatedAt {
+		changes = append(changes, fieldChange{Field: "task", New: "updated at " + current.UpdatedAt})
+	}
+	return remote, changes, nil
+}
+
+// resolveConflict shows the changes made on Todoist to task id since the
+// last sync and asks which of edits, the values given to modify by field, to
+// make: all of them, none to keep the task as it is on Todoist, or for each
+// field changed on both sides the one chosen.
+func resolveConflict(id string, changes []fieldChange, edits map[string]string) (map[string]string, error) {
+	if !isTerminal(os.Stdin) {
+		return nil, StaleTask(id, changes[0].String())
+	}
+
+	fmt.Fprintf(os.Stderr, "Task %s was changed on Todoist since the last sync:\n", id)
+	for _, change := range changes {
+		line := "  " + change.String()
+		if edit, ok := edits[change.Field]; ok {
+			line += ", you give " + edit
+		}
+		fmt.Fprintln(os.Stderr, line)
+	}
+	choice, err := promptChoice("Resolve it by", []string{
+		"keeping local: make your changes over those on Todoist",
+		"keeping remote: leave the task as it is on Todoist",
+		"merging: choose for each field changed on both sides",
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch choice {
+	case 0:
+		return edits, nil
+	case 1:
+		return map[string]string{}, nil
+	}
+
+	merged := map[string]string{}
+	for field, edit := range edits {
+		merged[field] = edit
+	}
+	for _, change := range changes {
+		edit, ok := edits[change.Field]
+		if !ok {
+			continue
+		}
+		i, err := promptChoice(change.Field, []string{"yours: " + edit, "Todoist's: " + change.New})
+		if err != nil {
+			return nil, err
+		}
+		if i == 1 {
+			delete(merged, change.Field)
+		}
+	}
+	return merged, nil
+}
+
 func Modify(c *cli.Context) error {
 	client := GetClient(c)
 
 	if !c.Args().Present() {
-		return CommandFailed
+		return ArgumentRequired
 	}
 
 	var err error
-	item_id, err := client.CompleteItemIDByPrefix(c.Args().First())
+	item_id, err := ResolveItemID(client, c.Args().First())
 	if err != nil {
 		return err
 	}
@@ -24,39 +144,97 @@ func Modify(c *cli.Context) error {
 	if item == nil {
 		return IdNotFound
 	}
-	item.Content = c.String("content")
-	item.Priority = priorityMapping[c.Int("priority")]
-	item.LabelIDs = func(str string) []int {
-		stringIDs := strings.Split(str, ",")
-		ids := []int{}
-		for _, stringID := range stringIDs {
-			id, err := strconv.Atoi(stringID)
-			if err != nil {
-				continue
-			}
-			ids = append(ids, id)
+
+	priority := 0
+	if flagIsSet(c, "priority", "p") {
+		if priority, err = parsePriority(c.String("priority")); err != nil {
+			return err
+		}
+	}
+	labelNames := labelNamesByIDs(client.Store, c.String("label-ids"))
+	projectID := c.String("project-id")
+	if projectID == "" && c.String("project-name") != "" {
+		projectID = client.Store.Projects.GetIDByName(c.String("project-name"))
+		if projectID == "" {
+			return ProjectNotFound(c.String("project-name"))
 		}
-		return ids
-	}(c.String("label-ids"))
+	}
 
-	item.DateString = c.String("date")
+	// The changes asked for by field, in the values shown for conflicts.
+	edits := map[string]string{}
+	if c.String("content") != "" {
+		edits["content"] = strconv.Quote(c.String("content"))
+	}
+	if priority != 0 {
+		edits["priority"] = fmt.Sprintf("p%d", priorityMapping[priority])
+	}
+	if c.String("date") != "" {
+		edits["due"] = c.String("date")
+	}
+	if projectID != "" {
+		edits["project"] = projectName(client.Store, projectID)
+	}
+	if len(labelNames) > 0 {
+		edits["labels"] = todoist.Item{LabelNames: labelNames}.LabelsString(client.Store)
+	}
 
-	projectID := c.Int("project-id")
-	if projectID == 0 {
-		projectID = client.Store.Projects.GetIDByName(c.String("project-name"))
+	ctx := GetContext(c)
+	if !c.Bool("force") {
+		remote, changes, err := fetchStale(ctx, client, item)
+		if err != nil {
+			return err
+		}
+		*client.Store = *remote
+		if len(changes) > 0 {
+			if edits, err = resolveConflict(item.ID, changes, edits); err != nil {
+				return err
+			}
+			if len(edits) == 0 {
+				fmt.Fprintf(os.Stderr, "Left task %s as it is on Todoist\n", item.ID)
+				return WriteCache(default_cache_path, client.Store)
+			}
+		}
+		item = client.Store.FindItem(item.ID)
 	}
 
-	if !c.Args().Present() {
-		return CommandFailed
+	// Only the fields asked for are sent, so those changed on Todoist meanwhile
+	// are kept. The due date is for rescheduling recurring tasks.
+	updated := todoist.Item{Due: item.Due}
+	updated.ID = item.ID
+	if _, ok := edits["content"]; ok {
+		updated.Content = c.String("content")
+	}
+	if _, ok := edits["priority"]; ok {
+		updated.Priority = priority
+	}
+	if _, ok := edits["labels"]; ok {
+		updated.LabelNames = labelNames
+	}
+	if _, ok := edits["due"]; ok {
+		if err := updated.Reschedule(c.String("date"), c.Bool("remove-recurrence")); err != nil {
+			return RecurrenceWouldBeLost(updated.Due.String)
+		}
 	}
 
-	if err := client.UpdateItem(context.Background(), *item); err != nil {
+	client.Buffer()
+	if err := client.UpdateItem(ctx, updated); err != nil {
 		return err
 	}
 
-	if err := client.MoveItem(context.Background(), item, projectID); err != nil {
+	if _, ok := edits["project"]; ok {
+		if err := client.MoveItem(ctx, &updated, projectID); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Flush(ctx); err != nil {
 		return err
 	}
 
+	if !c.Bool("force") {
+		// The store was fetched just before and has the edit applied, so a
+		// sync would only fetch it again.
+		return WriteCache(default_cache_path, client.Store)
+	}
 	return Sync(c)
 }
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/sachaos/todoist/lib/todoisttest"
+)
+
+func TestFetchStaleGone(t *testing.T) {
+	sandbox, err := todoist.NewSandbox([]byte(`{"items": [
+		{"id": "1", "content": "same", "priority": 1},
+		{"id": "2", "content": "closed on mobile", "priority": 1, "checked": true}
+	]}`))
+	assert.NoError(t, err)
+	client := todoist.NewClient(&todoist.Config{})
+	client.Transport = sandbox
+	client.Store = testStore(t, `{"items": [
+		{"id": "1", "content": "same", "priority": 1},
+		{"id": "2", "content": "closed on mobile", "priority": 1},
+		{"id": "3", "content": "deleted on mobile", "priority": 1}
+	]}`)
+	ctx := context.Background()
+
+	_, _, err = fetchStale(ctx, client, client.Store.FindItem("2"))
+	assert.Equal(t, "stale_task", AsError(err).Code, "they should be equal")
+
+	_, _, err = fetchStale(ctx, client, client.Store.FindItem("3"))
+	assert.Equal(t, "stale_task", AsError(err).Code, "they should be equal")
+
+	remote, changes, err := fetchStale(ctx, client, client.Store.FindItem("1"))
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+	assert.Nil(t, remote.FindItem("3"))
+}
+
+func TestFetchStale(t *testing.T) {
+	sandbox, err := todoist.NewSandbox([]byte(`{"items": [
+		{"id": "1", "content": "changed on mobile", "priority": 4, "updated_at": "2"},
+		{"id": "2", "content": "same", "priority": 1, "updated_at": "2"}
+	]}`))
+	assert.NoError(t, err)
+	client := todoist.NewClient(&todoist.Config{})
+	client.Transport = sandbox
+	client.Store = testStore(t, `{"items": [
+		{"id": "1", "content": "cached", "priority": 1, "updated_at": "1"},
+		{"id": "2", "content": "same", "priority": 1, "updated_at": "1"}
+	]}`)
+	ctx := context.Background()
+
+	_, changes, err := fetchStale(ctx, client, client.Store.FindItem("1"))
+	assert.NoError(t, err)
+	assert.Equal(t, []fieldChange{
+		{Field: "content", Old: `"cached"`, New: `"changed on mobile"`},
+		{Field: "priority", Old: "p4", New: "p1"},
+	}, changes, "they should be equal")
+
+	_, changes, err = fetchStale(ctx, client, client.Store.FindItem("2"))
+	assert.NoError(t, err)
+	assert.Equal(t, []fieldChange{{Field: "task", New: "updated at 2"}}, changes, "they should be equal")
+
+	if isTerminal(os.Stdin) {
+		t.Skip("stdin is a terminal, resolveConflict would ask")
+	}
+	// Without a terminal to ask on, nothing is overwritten.
+	_, err = resolveConflict("2", changes, map[string]string{"priority": "p1"})
+	assert.Equal(t, "stale_task", AsError(err).Code, "they should be equal")
+}
+
+func TestModifyConflict(t *testing.T) {
+	server := todoisttest.NewServer(t, `{
+		"projects": [{"id": "1", "name": "Inbox", "inbox_project": true}],
+		"items": [{"id": "10", "project_id": "1", "content": "Write the report", "priority": 1}]
+	}`)
+	run := runTodoist(t, server)
+	_, err := run("sync")
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	remote := server.NewClient()
+	assert.NoError(t, remote.Sync(ctx))
+	assert.NoError(t, remote.UpdateItem(ctx, todoist.Item{BaseItem: todoist.BaseItem{HaveID: todoist.HaveID{ID: "10"}, Content: "Write the annual report"}}))
+
+	// Without a terminal to ask on, nothing is overwritten.
+	_, err = run("modify", "--priority", "p1", "10")
+	assert.Equal(t, "stale_task", AsError(err).Code, "they should be equal")
+
+	_, err = run("modify", "--force", "--priority", "p1", "10")
+	assert.NoError(t, err)
+	assert.NoError(t, remote.Sync(ctx))
+	assert.Equal(t, "Write the annual report", remote.Store.FindItem("10").Content, "they should be equal")
+	assert.Equal(t, 4, remote.Store.FindItem("10").Priority, "they should be equal")
+
+	// Only the fields given are changed, and the account fetched to look
+	// for conflicts isn't fetched again.
+	record := t.TempDir()
+	_, err = run("--record", record, "modify", "--content", "Write the summary", "10")
+	assert.NoError(t, err)
+	recordings, err := ioutil.ReadDir(record)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(recordings), "they should be equal")
+	assert.NoError(t, remote.Sync(ctx))
+	assert.Equal(t, "Write the summary", remote.Store.FindItem("10").Content, "they should be equal")
+	assert.Equal(t, 4, remote.Store.FindItem("10").Priority, "they should be equal")
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/urfave/cli"
+)
+
+// MSTodoExport is the lists of Microsoft To Do with their tasks, as fetched
+// from Microsoft Graph with the tasks and their checklist items expanded.
+// Outlook tasks are in the same lists.
+type MSTodoExport struct {
+	Lists []MSTodoList `json:"lists"`
+}
+
+type MSTodoList struct {
+	DisplayName string `json:"displayName"`
+	// WellknownListName is defaultList for the Tasks list.
+	WellknownListName string       `json:"wellknownListName"`
+	Tasks             []MSTodoTask `json:"tasks"`
+}
+
+type MSTodoTask struct {
+	Title            string                `json:"title"`
+	Importance       string                `json:"importance"`
+	Status           string                `json:"status"`
+	Body             *MSTodoBody           `json:"body"`
+	DueDateTime      *MSTodoDateTime       `json:"dueDateTime"`
+	IsReminderOn     bool                  `json:"isReminderOn"`
+	ReminderDateTime *MSTodoDateTime       `json:"reminderDateTime"`
+	Categories       []string              `json:"categories"`
+	ChecklistItems   []MSTodoChecklistItem `json:"checklistItems"`
+}
+
+type MSTodoBody struct {
+	Content     string `json:"content"`
+	ContentType string `json:"contentType"`
+}
+
+// MSTodoDateTime is a time without offset, in TimeZone.
+type MSTodoDateTime struct {
+	DateTime string `json:"dateTime"`
+	TimeZone string `json:"timeZone"`
+}
+
+type MSTodoChecklistItem struct {
+	DisplayName string `json:"displayName"`
+	IsChecked   bool   `json:"isChecked"`
+}
+
+// Time returns the time d stands for. Time zones Go doesn't know, like the
+// Windows ones, are taken as UTC, which Graph uses unless told otherwise.
+func (d MSTodoDateTime) Time() (time.Time, error) {
+	location, err := time.LoadLocation(d.TimeZone)
+	if err != nil {
+		location = time.UTC
+	}
+	return time.ParseInLocation("2006-01-02T15:04:05.9999999", d.DateTime, location)
+}
+
+// msTodoPriorities maps the importance of a task to a priority. Important
+// tasks, starred in To Do, are p1.
+var msTodoPriorities = map[string]int{"high": 4, "normal": 1, "low": 1}
+
+// msTodoCommands adds the tasks of each list of export to the project of the
+// same name, created if there is none, and those of the Tasks list to the
+// inbox. Importance becomes the priority, notes the description, categories
+// labels, and checklist items subtasks. Completed tasks are added and
+// closed, and reminders are only added with reminders.
+func msTodoCommands(store *todoist.Store, export MSTodoExport, reminders bool) (todoist.Commands, int) {
+	commands := todoist.Commands{}
+	tasks := 0
+
+	for _, list := range export.Lists {
+		var projectID interface{} = store.User.InboxProjectID
+		if list.WellknownListName != "defaultList" {
+			if id := store.Projects.GetIDByName(list.DisplayName); id != "" {
+				projectID = id
+			} else {
+				command := todoist.NewCommand("project_add", todoist.Project{Name: list.DisplayName}.AddParam())
+				commands = append(commands, command)
+				projectID = command.TempID
+			}
+		}
+
+		for _, task := range list.Tasks {
+			item := todoist.Item{BaseItem: todoist.BaseItem{Content: task.Title}, Priority: msTodoPriorities[task.Importance]}
+			// HTML notes are left out, the app keeps a plain copy in text.
+			if task.Body != nil && task.Body.ContentType != "html" {
+				item.Description = strings.TrimSpace(task.Body.Content)
+			}
+			for _, category := range task.Categories {
+				item.LabelNames = append(item.LabelNames, strings.ReplaceAll(category, " ", "_"))
+			}
+			param := item.AddParam().(map[string]interface{})
+			param["project_id"] = projectID
+			if task.DueDateTime != nil {
+				// Due dates of To Do are days, at midnight.
+				if t, err := task.DueDateTime.Time(); err == nil {
+					param["due"] = &todoist.Due{Date: t.Format(todoist.RFC3339Date)}
+				}
+			}
+			add := todoist.NewCommand("item_add", param)
+			commands = append(commands, add)
+			tasks++
+
+			if reminders && task.IsReminderOn && task.ReminderDateTime != nil && task.Status != "completed" {
+				if t, err := task.ReminderDateTime.Time(); err == nil {
+					commands = append(commands, todoist.NewCommand("reminder_add", map[string]interface{}{
+						"item_id": add.TempID,
+						"type":    "absolute",
+						"due":     &todoist.Due{Date: t.Local().Format(todoist.RFC3339DateTime)},
+					}))
+				}
+			}
+
+			for _, checklistItem := range task.ChecklistItems {
+				subtask := todoist.NewCommand("item_add", map[string]interface{}{"content": checklistItem.DisplayName, "project_id": projectID, "parent_id": add.TempID})
+				commands = append(commands, subtask)
+				tasks++
+				if checklistItem.IsChecked {
+					commands = append(commands, todoist.NewCommand("item_close", map[string]interface{}{"id": subtask.TempID}))
+				}
+			}
+
+			if task.Status == "completed" {
+				commands = append(commands, todoist.NewCommand("item_close", map[string]interface{}{"id": add.TempID}))
+			}
+		}
+	}
+	return commands, tasks
+}
+
+func ImportMSTodo(c *cli.Context) error {
+	client := GetClient(c)
+
+	if !c.Args().Present() {
+		return ArgumentRequired
+	}
+	buf, err := ioutil.ReadFile(c.Args().First())
+	if err != nil {
+		return err
+	}
+	var export MSTodoExport
+	if err := json.Unmarshal(buf, &export); err != nil || export.Lists == nil {
+		return &Error{Code: "invalid_argument", Message: fmt.Sprintf("%s is not a Microsoft To Do export", c.Args().First()), Hint: "export the lists with their tasks as JSON from Microsoft Graph, see the README"}
+	}
+
+	commands, tasks := msTodoCommands(client.Store, export, !c.Bool("no-reminders"))
+	if len(commands) == 0 {
+		fmt.Fprintln(os.Stderr, "There are no tasks to import.")
+		return nil
+	}
+	if err := client.ExecCommands(GetContext(c), commands); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Imported %d task(s) from %d list(s)\n", tasks, len(export.Lists))
+	return Sync(c)
+}
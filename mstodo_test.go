@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMSTodoCommands(t *testing.T) {
+	store := testStore(t, `{
+		"user": {"inbox_project_id": "1"},
+		"projects": [{"id": "1", "name": "Inbox"}, {"id": "2", "name": "Work"}]
+	}`)
+	var export MSTodoExport
+	assert.NoError(t, json.Unmarshal([]byte(`{"lists": [
+		{"displayName": "Tasks", "wellknownListName": "defaultList", "tasks": [
+			{"title": "Renew passport", "importance": "high", "status": "notStarted",
+			 "body": {"content": "Photos first\r\n", "contentType": "text"},
+			 "dueDateTime": {"dateTime": "2020-11-01T00:00:00.0000000", "timeZone": "UTC"},
+			 "isReminderOn": true, "reminderDateTime": {"dateTime": "2020-10-30T08:00:00.0000000", "timeZone": "UTC"},
+			 "categories": ["Red category"],
+			 "checklistItems": [{"displayName": "Book photos", "isChecked": true}]}
+		]},
+		{"displayName": "Work", "tasks": [{"title": "Expense report", "importance": "normal", "status": "completed"}]},
+		{"displayName": "Reading", "tasks": [{"title": "Dune", "importance": "low", "body": {"content": "<p>x</p>", "contentType": "html"}}]}
+	]}`), &export))
+
+	commands, tasks := msTodoCommands(store, export, true)
+	assert.Equal(t, 4, tasks, "they should be equal")
+	types := []string{}
+	for _, command := range commands {
+		types = append(types, command.Type)
+	}
+	assert.Equal(t, []string{
+		"item_add", "reminder_add", "item_add", "item_close",
+		"item_add", "item_close",
+		"project_add", "item_add",
+	}, types, "they should be equal")
+
+	passport := commands[0].Args.(map[string]interface{})
+	assert.Equal(t, "1", passport["project_id"], "they should be equal")
+	assert.Equal(t, 4, passport["priority"], "they should be equal")
+	assert.Equal(t, "Photos first", passport["description"], "they should be equal")
+	assert.Equal(t, []string{"Red_category"}, passport["labels"], "they should be equal")
+	assert.Equal(t, "2020-11-01", passport["due"].(*todoist.Due).Date, "they should be equal")
+	assert.Equal(t, commands[0].TempID, commands[1].Args.(map[string]interface{})["item_id"], "they should be equal")
+	assert.Equal(t, commands[0].TempID, commands[2].Args.(map[string]interface{})["parent_id"], "they should be equal")
+
+	assert.Equal(t, "2", commands[4].Args.(map[string]interface{})["project_id"], "they should be equal")
+	dune := commands[7].Args.(map[string]interface{})
+	assert.Equal(t, commands[6].TempID, dune["project_id"], "they should be equal")
+	assert.Nil(t, dune["description"])
+
+	commands, _ = msTodoCommands(store, export, false)
+	assert.Equal(t, 7, len(commands), "they should be equal")
+}
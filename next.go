@@ -0,0 +1,63 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/urfave/cli"
+)
+
+// nextBefore reports whether a is to be done before b at now: overdue tasks
+// first, then by due time, undated tasks last, then by priority. Otherwise
+// the order of the tasks is kept.
+func nextBefore(a *todoist.Item, b *todoist.Item, now time.Time) bool {
+	aDue, bDue := a.DateTime(), b.DateTime()
+	if aDue.IsZero() != bDue.IsZero() {
+		return !aDue.IsZero()
+	}
+	if !aDue.IsZero() {
+		// Due dates without time are all day, whatever all_day says.
+		aOverdue := dueClass(aDue, !strings.Contains(a.Due.Date, "T"), now) == "overdue"
+		bOverdue := dueClass(bDue, !strings.Contains(b.Due.Date, "T"), now) == "overdue"
+		if aOverdue != bOverdue {
+			return aOverdue
+		}
+		if !aDue.Equal(bDue) {
+			return aDue.Before(bDue)
+		}
+	}
+	return a.Priority > b.Priority
+}
+
+// NextItem returns the one of items to do right now, or nil if there is
+// none.
+func NextItem(items []*todoist.Item, now time.Time) *todoist.Item {
+	if len(items) == 0 {
+		return nil
+	}
+	sorted := append([]*todoist.Item{}, items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return nextBefore(sorted[i], sorted[j], now)
+	})
+	return sorted[0]
+}
+
+func Next(c *cli.Context) error {
+	client := GetClient(c)
+
+	filter, err := ApplyContext(c.String("filter"))
+	if err != nil {
+		return err
+	}
+	item := NextItem(FilterItems(client.Store, Filter(filter)), time.Now())
+	if item == nil {
+		if filter == "" {
+			return &Error{Code: "no_matching_task", Message: "there is no open task", Hint: "run `todoist sync` or add one with `todoist add`"}
+		}
+		return NoMatchingTask(filter)
+	}
+	writeTaskLine(c, client.Store, item)
+	return nil
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextItem(t *testing.T) {
+	store := testStore(t, `{"items": [
+		{"id": "1", "content": "undated p1", "priority": 4},
+		{"id": "2", "content": "later p1", "priority": 4, "due": {"date": "2020-01-05"}},
+		{"id": "3", "content": "today p4", "priority": 1, "due": {"date": "2020-01-02"}},
+		{"id": "4", "content": "today p2", "priority": 3, "due": {"date": "2020-01-02"}},
+		{"id": "5", "content": "this morning", "priority": 1, "due": {"date": "2020-01-02T09:00:00"}},
+		{"id": "6", "content": "yesterday", "priority": 1, "due": {"date": "2020-01-01"}}
+	]}`)
+	items := FilterItems(store, Filter(""))
+	now := time.Date(2020, 1, 2, 12, 0, 0, 0, time.Local)
+
+	assert.Equal(t, "6", NextItem(items, now).ID, "they should be equal")
+	// Past its time, a task due today is overdue, unlike one due all day.
+	assert.Equal(t, "5", NextItem(items[:5], now).ID, "they should be equal")
+	assert.Equal(t, "4", NextItem(items[:4], now).ID, "they should be equal")
+	assert.Equal(t, "2", NextItem(items[:2], now).ID, "they should be equal")
+	assert.Nil(t, NextItem(nil, now))
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pkg/browser"
+	"github.com/urfave/cli"
+)
+
+// Open opens a task in the Todoist app, or with --attachment one of the
+// files attached to its comments, numbered like in `show`.
+func Open(c *cli.Context) error {
+	client := GetClient(c)
+
+	if !c.Args().Present() {
+		return ArgumentRequired
+	}
+	id, err := ResolveItemID(client, c.Args().First())
+	if err != nil {
+		return err
+	}
+	if client.Store.FindItem(id) == nil {
+		return IdNotFound
+	}
+
+	if !flagIsSet(c, "attachment") {
+		return browser.OpenURL(taskURL(id))
+	}
+
+	attachments := client.Store.ItemAttachments(id)
+	n := c.Int("attachment")
+	if n < 1 || n > len(attachments) {
+		hint := "the task has no attachments"
+		switch {
+		case len(attachments) == 1:
+			hint = "use 1, as numbered by `todoist show`"
+		case len(attachments) > 1:
+			hint = "use 1 to " + strconv.Itoa(len(attachments)) + ", as numbered by `todoist show`"
+		}
+		return &Error{Code: "attachment_not_found", Message: fmt.Sprintf("no attachment %d", n), Hint: hint}
+	}
+	attachment := attachments[n-1]
+
+	if !c.Bool("download") {
+		return browser.OpenURL(attachment.FileURL)
+	}
+
+	path := c.String("out")
+	if path == "" {
+		// The name comes from the API, only its base is used so that it
+		// can't point outside the current directory.
+		path = filepath.Base(attachment.FileName)
+		if path == "." || path == string(filepath.Separator) {
+			path = "attachment"
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := client.Download(GetContext(c), attachment.FileURL, f); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "Saved", path)
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"os"
+)
+
+const defaultPager = "less -R"
+
+// Pager buffers output and, when it is longer than the terminal, shows it
+// through $PAGER instead of writing it directly. While it is open, os.Stdout
+// is a pipe into the buffer, so that what commands print directly is paged
+// along with the output of the writer, in the order it was written.
+type Pager struct {
+	buffer bytes.Buffer
+	out    *os.File
+	pipe   *os.File
+	copied chan error
+	// stdout is os.Stdout before the pager was opened.
+	stdout *os.File
+}
+
+func NewPager(out *os.File) (*Pager, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	p := &Pager{out: out, pipe: w, copied: make(chan error, 1), stdout: os.Stdout}
+	go func() {
+		_, err := p.buffer.ReadFrom(r)
+		r.Close()
+		p.copied <- err
+	}()
+	os.Stdout = w
+	return p, nil
+}
+
+func (p *Pager) Write(b []byte) (int, error) {
+	return p.pipe.Write(b)
+}
+
+func (p *Pager) Close() error {
+	os.Stdout = p.stdout
+	p.pipe.Close()
+	if err := <-p.copied; err != nil {
+		return err
+	}
+
+	height := terminalHeight()
+	if height == 0 || bytes.Count(p.buffer.Bytes(), []byte("\n")) < height {
+		_, err := p.buffer.WriteTo(p.out)
+		return err
+	}
+
+	command := os.Getenv("PAGER")
+	if command == "" {
+		command = defaultPager
+	}
+
+	cmd := shellCommand(command)
+	cmd.Stdin = &p.buffer
+	cmd.Stdout = p.out
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		// Fall back to plain output when the pager is unavailable.
+		_, err = p.buffer.WriteTo(p.out)
+		return err
+	}
+	return nil
+}
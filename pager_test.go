@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPagerPagesStdout(t *testing.T) {
+	t.Setenv("LINES", "")
+	out, err := ioutil.TempFile("", "todoist")
+	assert.NoError(t, err)
+	defer os.Remove(out.Name())
+	defer out.Close()
+
+	saved := os.Stdout
+	pager, err := NewPager(out)
+	assert.NoError(t, err)
+	fmt.Fprintln(pager, "written")
+	fmt.Println("printed")
+	fmt.Fprintln(pager, "written again")
+	assert.NoError(t, pager.Close())
+	assert.Equal(t, saved, os.Stdout, "they should be equal")
+
+	buf, err := ioutil.ReadFile(out.Name())
+	assert.NoError(t, err)
+	assert.Equal(t, "written\nprinted\nwritten again\n", string(buf), "they should be equal")
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/urfave/cli"
+)
+
+// pickWeight is how likely item is to be picked when weighted: its API
+// priority, 4 being p1, doubled when it is overdue.
+func pickWeight(item *todoist.Item, now time.Time) int {
+	weight := item.Priority
+	if weight < 1 {
+		weight = 1
+	}
+	if item.Due != nil && item.DateTime().Before(now) {
+		weight *= 2
+	}
+	return weight
+}
+
+// PickItem returns one of items at random, or nil if there is none. With
+// weighted, urgent and overdue tasks come up more often.
+func PickItem(items []*todoist.Item, weighted bool, now time.Time, rnd *rand.Rand) *todoist.Item {
+	if len(items) == 0 {
+		return nil
+	}
+	if !weighted {
+		return items[rnd.Intn(len(items))]
+	}
+	total := 0
+	for _, item := range items {
+		total += pickWeight(item, now)
+	}
+	n := rnd.Intn(total)
+	for _, item := range items {
+		n -= pickWeight(item, now)
+		if n < 0 {
+			return item
+		}
+	}
+	return items[len(items)-1]
+}
+
+func Pick(c *cli.Context) error {
+	client := GetClient(c)
+
+	filter, err := ApplyContext(c.String("filter"))
+	if err != nil {
+		return err
+	}
+	item := PickItem(FilterItems(client.Store, Filter(filter)), c.Bool("weighted"), time.Now(), rand.New(rand.NewSource(time.Now().UnixNano())))
+	if item == nil {
+		if filter == "" {
+			return &Error{Code: "no_matching_task", Message: "there is no open task", Hint: "run `todoist sync` or add one with `todoist add`"}
+		}
+		return NoMatchingTask(filter)
+	}
+
+	writeTaskLine(c, client.Store, item)
+	return nil
+}
+
+// writeTaskLine writes item on a line of its own, like in a list, for
+// commands showing one task.
+func writeTaskLine(c *cli.Context, store *todoist.Store, item *todoist.Item) {
+	colorList := ColorList()
+	projectIds := []string{}
+	for _, project := range store.Projects {
+		projectIds = append(projectIds, project.GetID())
+	}
+	projectColorHash := GenerateColorHash(projectIds, colorList)
+
+	defer writer.Flush()
+
+	writer.Write([]string{
+		IdFormat(item),
+		PriorityFormat(item.Priority),
+		DueDateFormat(item.DateTime(), item.AllDay),
+		ProjectFormat(item.ProjectID, store, projectColorHash, c),
+		LabelsFormat(item, store),
+		ContentFormat(item),
+	})
+}
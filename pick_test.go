@@ -0,0 +1,30 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPickItem(t *testing.T) {
+	store := testStore(t, `{"items": [
+		{"id": "1", "content": "a", "priority": 1},
+		{"id": "2", "content": "b", "priority": 4, "due": {"date": "2020-01-01"}}
+	]}`)
+	items := FilterItems(store, Filter(""))
+	now := time.Date(2020, 1, 2, 0, 0, 0, 0, time.Local)
+
+	assert.Equal(t, 1, pickWeight(items[0], now), "they should be equal")
+	assert.Equal(t, 8, pickWeight(items[1], now), "they should be equal")
+
+	rnd := rand.New(rand.NewSource(1))
+	picked := map[string]int{}
+	for i := 0; i < 900; i++ {
+		picked[PickItem(items, true, now, rnd).ID]++
+	}
+	assert.True(t, picked["2"] > picked["1"]*4, "overdue p1 should be picked more often")
+
+	assert.Nil(t, PickItem(nil, false, now, rnd), "they should be equal")
+}
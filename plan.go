@@ -0,0 +1,217 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/urfave/cli"
+)
+
+// planEntry is a task of the plan of a day, from Start to End.
+type planEntry struct {
+	Item       *todoist.Item
+	Start, End time.Time
+}
+
+// dayPlan is the time of a day from start to end, and what of it is taken.
+type dayPlan struct {
+	start, end time.Time
+	entries    []planEntry
+}
+
+// slot returns the earliest start of d free in p, or false if there is no
+// room left for it.
+func (p *dayPlan) slot(d time.Duration) (time.Time, bool) {
+	candidates := []time.Time{p.start}
+	for _, entry := range p.entries {
+		if entry.End.After(p.start) {
+			candidates = append(candidates, entry.End)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+	for _, start := range candidates {
+		if start.Add(d).After(p.end) {
+			break
+		}
+		if p.free(start, start.Add(d)) {
+			return start, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// free reports whether no entry of p overlaps start to end.
+func (p *dayPlan) free(start time.Time, end time.Time) bool {
+	for _, entry := range p.entries {
+		if start.Before(entry.End) && entry.Start.Before(end) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *dayPlan) add(entry planEntry) {
+	p.entries = append(p.entries, entry)
+	sort.SliceStable(p.entries, func(i, j int) bool { return p.entries[i].Start.Before(p.entries[j].Start) })
+}
+
+// planDuration is how long item takes, d if it doesn't say.
+func planDuration(item *todoist.Item, d time.Duration) time.Duration {
+	if minutes, ok := item.Duration.Minutes(); ok && minutes > 0 {
+		return time.Duration(minutes) * time.Minute
+	}
+	return d
+}
+
+// newDayPlan returns the plan of the day of start until end, taken by the
+// open tasks of store due at a time between them, and the others of items to
+// plan in order of priority. Tasks of the day whose time has passed are to
+// plan again.
+func newDayPlan(store *todoist.Store, items []*todoist.Item, start time.Time, end time.Time, d time.Duration) (*dayPlan, []*todoist.Item) {
+	plan := &dayPlan{start: start, end: end}
+	dayEnd := startOfDay(start).AddDate(0, 0, 1)
+	fixed := func(item *todoist.Item) bool {
+		due := item.DateTime()
+		return item.Due != nil && strings.Contains(item.Due.Date, "T") && !due.Before(start) && due.Before(dayEnd)
+	}
+	for i := range store.Items {
+		item := &store.Items[i]
+		if !item.Checked && !item.IsDeleted && fixed(item) {
+			plan.add(planEntry{Item: item, Start: item.DateTime(), End: item.DateTime().Add(planDuration(item, d))})
+		}
+	}
+	unplanned := []*todoist.Item{}
+	for _, item := range items {
+		if !fixed(item) {
+			unplanned = append(unplanned, item)
+		}
+	}
+	sort.SliceStable(unplanned, func(i, j int) bool {
+		return unplanned[i].Priority > unplanned[j].Priority
+	})
+	return plan, unplanned
+}
+
+// planClock returns the time of day clock, like "9:00" or "6pm", on the day
+// of t.
+func planClock(t time.Time, clock string) (time.Time, error) {
+	hour, minute, ok := parseTimeOfDay(strings.ToLower(clock), true)
+	if !ok {
+		return time.Time{}, &Error{Code: "invalid_argument", Message: fmt.Sprintf("invalid time %q", clock), Hint: "give a time like 9:00, 18:30 or 6pm"}
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), hour, minute, 0, 0, t.Location()), nil
+}
+
+// askPlanStart asks when to start item, proposed at start: Enter takes it, a
+// time changes it, and "s" skips the task. ok is false for a skipped task.
+func askPlanStart(item *todoist.Item, d time.Duration, start time.Time) (time.Time, bool, error) {
+	for {
+		fmt.Fprintf(os.Stderr, "%s (%s) at %s? [Enter, a time or s to skip]: ", todoist.GetContentTitle(item), d, start.Format("15:04"))
+		line, err := readLine()
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		switch strings.ToLower(line) {
+		case "":
+			return start, true, nil
+		case "s", "skip":
+			return time.Time{}, false, nil
+		}
+		if t, err := planClock(start, line); err == nil {
+			return t, true, nil
+		}
+	}
+}
+
+func PlanToday(c *cli.Context) error {
+	client := GetClient(c)
+	ctx := GetContext(c)
+
+	now := time.Now()
+	start, err := planClock(now, c.String("from"))
+	if err != nil {
+		return err
+	}
+	end, err := planClock(now, c.String("to"))
+	if err != nil {
+		return err
+	}
+	// What is left of today, from the next five minutes on.
+	if next := now.Truncate(5 * time.Minute).Add(5 * time.Minute); next.After(start) {
+		start = next
+	}
+	d := c.Duration("duration")
+	if d <= 0 {
+		return &Error{Code: "invalid_argument", Message: fmt.Sprintf("invalid duration %s", d), Hint: "give the time of tasks without duration like --duration 30m"}
+	}
+
+	filter, err := ApplyContext(c.String("filter"))
+	if err != nil {
+		return err
+	}
+	plan, unplanned := newDayPlan(client.Store, FilterItems(client.Store, Filter(filter)), start, end, d)
+	if len(unplanned) == 0 {
+		fmt.Fprintln(os.Stderr, "There is no task to plan today.")
+	}
+
+	interactive := !c.Bool("yes")
+	if interactive && len(unplanned) > 0 && !isTerminal(os.Stdin) {
+		return ConfirmationRequired
+	}
+	planned := []planEntry{}
+	for _, item := range unplanned {
+		duration := planDuration(item, d)
+		slot, ok := plan.slot(duration)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "No time left today for %s (%s)\n", todoist.GetContentTitle(item), duration)
+			continue
+		}
+		if interactive {
+			if slot, ok, err = askPlanStart(item, duration, slot); err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+		}
+		entry := planEntry{Item: item, Start: slot, End: slot.Add(duration)}
+		plan.add(entry)
+		planned = append(planned, entry)
+	}
+
+	if len(planned) > 0 {
+		client.Buffer()
+		for _, entry := range planned {
+			updated := todoist.Item{Due: entry.Item.Due}
+			updated.ID = entry.Item.ID
+			date := entry.Start.Format(todoist.RFC3339DateTime)
+			if entry.Item.Due != nil && entry.Item.Due.IsRecurring {
+				// Keeps the recurrence, as the date is concrete.
+				updated.Reschedule(date, false)
+			} else {
+				updated.NewDue = &todoist.Due{Date: date}
+			}
+			updated.Duration = &todoist.Duration{Amount: int(entry.End.Sub(entry.Start) / time.Minute), Unit: "minute"}
+			if err := client.UpdateItem(ctx, updated); err != nil {
+				return err
+			}
+		}
+		if err := client.Flush(ctx); err != nil {
+			return err
+		}
+		if err := Sync(c); err != nil {
+			return err
+		}
+	}
+
+	defer writer.Flush()
+	writer.WriteHeader([]string{"Start", "End", "ID", "Content"})
+	for _, entry := range plan.entries {
+		writer.Write([]string{entry.Start.Format("15:04"), entry.End.Format("15:04"), IdFormat(entry.Item), ContentFormat(entry.Item)})
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sachaos/todoist/lib/todoisttest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDayPlan(t *testing.T) {
+	store := testStore(t, `{"items": [
+		{"id": "1", "content": "call", "priority": 1, "due": {"date": "2020-01-02"}},
+		{"id": "2", "content": "meeting", "priority": 1, "due": {"date": "2020-01-02T10:00:00"}, "duration": {"amount": 60, "unit": "minute"}},
+		{"id": "3", "content": "report", "priority": 4, "due": {"date": "2020-01-01"}, "duration": {"amount": 90, "unit": "minute"}},
+		{"id": "4", "content": "missed", "priority": 1, "due": {"date": "2020-01-02T08:00:00"}},
+		{"id": "5", "content": "tomorrow", "priority": 1, "due": {"date": "2020-01-03T10:00:00"}}
+	]}`)
+	at := func(hour int, minute int) time.Time {
+		return time.Date(2020, 1, 2, hour, minute, 0, 0, time.Local)
+	}
+
+	plan, unplanned := newDayPlan(store, FilterItems(store, Filter("")), at(9, 0), at(13, 0), 30*time.Minute)
+	if assert.Len(t, plan.entries, 1) {
+		assert.Equal(t, "2", plan.entries[0].Item.ID, "they should be equal")
+		assert.Equal(t, at(11, 0), plan.entries[0].End, "they should be equal")
+	}
+	ids := []string{}
+	for _, item := range unplanned {
+		ids = append(ids, item.ID)
+	}
+	// The meeting is fixed, and p1 goes first.
+	assert.Equal(t, []string{"3", "1", "4", "5"}, ids, "they should be equal")
+
+	// 90 minutes don't fit before the meeting, but do after it.
+	start, ok := plan.slot(90 * time.Minute)
+	assert.True(t, ok)
+	assert.Equal(t, at(11, 0), start, "they should be equal")
+	plan.add(planEntry{Item: unplanned[0], Start: start, End: start.Add(90 * time.Minute)})
+
+	start, ok = plan.slot(30 * time.Minute)
+	assert.True(t, ok)
+	assert.Equal(t, at(9, 0), start, "they should be equal")
+	plan.add(planEntry{Item: unplanned[1], Start: start, End: start.Add(30 * time.Minute)})
+
+	_, ok = plan.slot(time.Hour)
+	assert.False(t, ok, "there are only half hours left")
+}
+
+func TestPlanToday(t *testing.T) {
+	server := todoisttest.NewServer(t, `{
+		"projects": [{"id": "1", "name": "Inbox", "inbox_project": true}],
+		"items": [{"id": "10", "project_id": "1", "content": "Write the report", "priority": 1, "due": {"date": "2000-01-01"}, "duration": {"amount": 45, "unit": "minute"}}]
+	}`)
+	run := runTodoist(t, server)
+
+	_, err := run("sync")
+	assert.NoError(t, err)
+	_, err = run("plan", "today")
+	assert.Equal(t, ConfirmationRequired, err, "they should be equal")
+
+	if time.Now().Add(50*time.Minute).Day() != time.Now().Day() {
+		t.Skip("too late in the day to plan 45 minutes")
+	}
+	out, err := run("plan", "today", "--from", "0:00", "--to", "23:59", "--yes")
+	assert.NoError(t, err)
+	assert.Contains(t, out, "Write the report")
+
+	client := server.NewClient()
+	assert.NoError(t, client.Sync(context.Background()))
+	item := client.Store.FindItem("10")
+	assert.Equal(t, time.Now().Format("2006-01-02"), item.DateTime().Format("2006-01-02"), "they should be equal")
+	assert.True(t, item.DateTime().After(time.Now()))
+	minutes, _ := item.Duration.Minutes()
+	assert.Equal(t, 45, minutes, "they should be equal")
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// spinnerDelay is how long a request runs before the spinner shows, so quick
+// ones don't make it flicker.
+var spinnerDelay = 500 * time.Millisecond
+
+var progressMessages = map[string]string{
+	"sync":                               "Syncing",
+	"tasks/completed/by_completion_date": "Fetching completed tasks",
+	"projects/archived":                  "Fetching archived projects",
+	"tasks":                              "Fetching tasks",
+	"tasks/quick":                        "Adding task",
+}
+
+// progressMessage describes the request to endpoint for page, 0 for an
+// endpoint without pages.
+func progressMessage(endpoint string, page int) string {
+	message, ok := progressMessages[endpoint]
+	if !ok {
+		message = "Waiting for " + endpoint
+	}
+	if page > 0 {
+		message += fmt.Sprintf(", page %d", page)
+	}
+	return message + ellipsis
+}
+
+// Spinner shows on out what todoist is waiting for, so that long syncs and
+// downloads of many pages don't look frozen. The line is cleared whenever no
+// request is running, so that it doesn't get mixed into other output.
+type Spinner struct {
+	out     io.Writer
+	frames  []string
+	mutex   sync.Mutex
+	message string
+	// running is the number of requests running, since when they started.
+	running int
+	since   time.Time
+	frame   int
+	// width is that of the line shown, 0 if none is.
+	width int
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+func NewSpinner(out io.Writer) *Spinner {
+	frames := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	if !utf8Locale() {
+		frames = []string{"|", "/", "-", "\\"}
+	}
+	return &Spinner{out: out, frames: frames}
+}
+
+// Progress is the hook of todoist.Client, showing the spinner while the
+// request runs.
+func (s *Spinner) Progress(endpoint string, page int) func() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.message = progressMessage(endpoint, page)
+	if s.running == 0 {
+		s.since = time.Now()
+	}
+	s.running++
+	if s.stop == nil {
+		s.stop = make(chan struct{})
+		s.done = make(chan struct{})
+		go s.run(s.stop, s.done)
+	}
+	return func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		s.running--
+		if s.running == 0 {
+			s.clear()
+		}
+	}
+}
+
+// draw shows the next frame, with the mutex held.
+func (s *Spinner) draw() {
+	line := s.frames[s.frame%len(s.frames)] + " " + s.message
+	s.frame++
+	// Spaces rather than escape sequences clear what is left of a longer
+	// line, which works on every terminal.
+	padding := ""
+	if width := visibleWidth(line); width < s.width {
+		padding = strings.Repeat(" ", s.width-width)
+	} else {
+		s.width = width
+	}
+	fmt.Fprint(s.out, "\r"+line+padding)
+}
+
+// clear removes the line shown, if any, with the mutex held.
+func (s *Spinner) clear() {
+	if s.width > 0 {
+		fmt.Fprint(s.out, "\r"+strings.Repeat(" ", s.width)+"\r")
+		s.width = 0
+	}
+}
+
+func (s *Spinner) run(stop chan struct{}, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+		s.mutex.Lock()
+		if s.running > 0 && time.Since(s.since) >= spinnerDelay {
+			s.draw()
+		}
+		s.mutex.Unlock()
+	}
+}
+
+// Stop removes the spinner for good.
+func (s *Spinner) Stop() {
+	s.mutex.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mutex.Unlock()
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+	s.mutex.Lock()
+	s.clear()
+	s.mutex.Unlock()
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressMessage(t *testing.T) {
+	assert.Equal(t, "Syncing…", progressMessage("sync", 0), "they should be equal")
+	assert.Equal(t, "Fetching completed tasks, page 3…", progressMessage("tasks/completed/by_completion_date", 3), "they should be equal")
+	assert.Equal(t, "Waiting for labels…", progressMessage("labels", 0), "they should be equal")
+}
+
+func TestSpinner(t *testing.T) {
+	saved := spinnerDelay
+	defer func() { spinnerDelay = saved }()
+
+	// Quick requests show nothing.
+	spinnerDelay = time.Hour
+	var out bytes.Buffer
+	spinner := NewSpinner(&out)
+	spinner.Progress("sync", 0)()
+	spinner.Stop()
+	assert.Equal(t, "", out.String(), "they should be equal")
+
+	spinnerDelay = 0
+	spinner = NewSpinner(&out)
+	done := spinner.Progress("tasks/completed/by_completion_date", 2)
+	time.Sleep(250 * time.Millisecond)
+	done()
+	shown := out.String()
+	assert.Contains(t, shown, "Fetching completed tasks, page 2…")
+	// The line is cleared once the request is done.
+	assert.True(t, strings.HasSuffix(shown, "\r"), shown)
+	spinner.Stop()
+	assert.Equal(t, shown, out.String(), "they should be equal")
+}
@@ -1,6 +1,11 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
 	"github.com/sachaos/todoist/lib"
 	"github.com/urfave/cli"
 )
@@ -9,7 +14,7 @@ func traverseProjects(pjt *todoist.Project, f func(pjt *todoist.Project, depth i
 	f(pjt, depth)
 
 	if pjt.ChildProject != nil {
-		traverseProjects(pjt.ChildProject, f, depth + 1)
+		traverseProjects(pjt.ChildProject, f, depth+1)
 	}
 
 	if pjt.BrotherProject != nil {
@@ -17,11 +22,67 @@ func traverseProjects(pjt *todoist.Project, f func(pjt *todoist.Project, depth i
 	}
 }
 
+// traverseProjectTree calls f with every project and the tree drawing which
+// goes before its name.
+func traverseProjectTree(pjt *todoist.Project, f func(pjt *todoist.Project, prefix string), indent string, root bool) {
+	for ; pjt != nil; pjt = pjt.BrotherProject {
+		branch, next := "├─ ", "│  "
+		if pjt.BrotherProject == nil {
+			branch, next = "└─ ", "   "
+		}
+		if root {
+			branch, next = "", ""
+		}
+		f(pjt, indent+branch)
+		traverseProjectTree(pjt.ChildProject, f, indent+next, false)
+	}
+}
+
+func countChildProjects(pjt *todoist.Project) int {
+	count := 0
+	for child := pjt.ChildProject; child != nil; child = child.BrotherProject {
+		count++
+	}
+	return count
+}
+
+// includeArchived adds the archived projects, and with items their tasks,
+// to the store of client for this invocation. The cache is left as it is.
+func includeArchived(ctx context.Context, client *todoist.Client, items bool) error {
+	projects, err := client.ArchivedProjects(ctx)
+	if err != nil {
+		return err
+	}
+	store := client.Store
+	for _, project := range projects {
+		if store.FindProject(project.ID) != nil {
+			continue
+		}
+		store.Projects = append(store.Projects, project)
+		if !items {
+			continue
+		}
+		projectItems, err := client.ProjectItems(ctx, project.ID)
+		if err != nil {
+			return err
+		}
+		store.Items = append(store.Items, projectItems...)
+	}
+	store.ConstructItemTree()
+	return nil
+}
+
 func Projects(c *cli.Context) error {
 	client := GetClient(c)
 
+	if c.Bool("archived") {
+		if err := includeArchived(GetContext(c), client, false); err != nil {
+			return err
+		}
+	}
+
 	colorList := ColorList()
-	var projectIds []int
+	var projectIds []string
 	for _, project := range client.Store.Projects {
 		projectIds = append(projectIds, project.GetID())
 	}
@@ -30,19 +91,168 @@ func Projects(c *cli.Context) error {
 	itemList := [][]string{}
 	project := client.Store.RootProject
 
-	traverseProjects(project, func(pjt *todoist.Project, depth int) {
-		itemList = append(itemList, []string{IdFormat(pjt), ProjectFormat(pjt.ID, client.Store, projectColorHash, c)})
-	}, 0)
+	if project == nil {
+		fmt.Fprintln(os.Stderr, "There is no project. You can fetch latest projects by `todoist sync`.")
+		return nil
+	}
 
-	defer writer.Flush()
+	groups := workspaceGroups(client.Store)
+	id, scoped, err := workspaceScope(client.Store, c.String("workspace"))
+	if err != nil {
+		return err
+	}
+	if scoped {
+		groups = []string{id}
+	}
+	listed := func(pjt *todoist.Project, group string) bool {
+		return pjt.WorkspaceID == group && (!c.Bool("favorites") || pjt.IsFavorite)
+	}
+
+	// The projects of each workspace are listed separately, after the
+	// personal ones. Only the outputs for reading get the tree and the
+	// workspace names, with the names in the others staying usable as they
+	// are.
+	for _, group := range groups {
+		if c.Bool("flat") || (outputFormat != "tsv" && outputFormat != "table") {
+			traverseProjects(project, func(pjt *todoist.Project, depth int) {
+				if !listed(pjt, group) {
+					return
+				}
+				itemList = append(itemList, []string{IdFormat(pjt), ProjectFormat(pjt.ID, client.Store, projectColorHash, c) + FavoriteFormat(pjt.IsFavorite) + ArchivedFormat(pjt.IsArchived)})
+			}, 0)
+			continue
+		}
 
-	if c.GlobalBool("header") {
-		writer.Write([]string{"ID", "Name"})
+		indent := ""
+		if workspace := client.Store.FindWorkspace(group); workspace != nil && len(groups) > 1 {
+			itemList = append(itemList, []string{"", WorkspaceFormat(workspace)})
+			indent = "   "
+		}
+		traverseProjectTree(project, func(pjt *todoist.Project, prefix string) {
+			if !listed(pjt, group) {
+				return
+			}
+			name := prefix + ProjectFormat(pjt.ID, client.Store, projectColorHash, c) + FavoriteFormat(pjt.IsFavorite) + ArchivedFormat(pjt.IsArchived)
+			if count := countChildProjects(pjt); count > 0 {
+				name += fmt.Sprintf(" (%d)", count)
+			}
+			itemList = append(itemList, []string{IdFormat(pjt), name})
+		}, indent, true)
 	}
 
+	defer writer.Flush()
+
+	writer.WriteHeader([]string{"ID", "Name"})
+
 	for _, strings := range itemList {
 		writer.Write(strings)
 	}
 
 	return nil
 }
+
+// projectRootItems returns the open items of a project which have no parent
+// in the same project.
+func projectRootItems(store *todoist.Store, projectID string) []*todoist.Item {
+	items := []*todoist.Item{}
+	for i := range store.Items {
+		item := &store.Items[i]
+		if item.ProjectID != projectID || item.Checked {
+			continue
+		}
+		if item.ParentID != nil {
+			if parent := store.FindItem(*item.ParentID); parent != nil && parent.ProjectID == projectID {
+				continue
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func CopyProject(c *cli.Context) error {
+	client := GetClient(c)
+	store := client.Store
+
+	if len(c.Args()) != 2 {
+		return ArgumentRequired
+	}
+	src, name := c.Args().Get(0), c.Args().Get(1)
+
+	srcID := store.Projects.GetIDByName(src)
+	if srcID == "" {
+		return ProjectNotFound(src)
+	}
+	source := store.FindProject(srcID)
+
+	projectCommand := todoist.NewCommand("project_add", todoist.Project{Name: name, Color: source.Color}.AddParam())
+	commands := todoist.Commands{projectCommand}
+	opts := todoist.CopyOptions{
+		ProjectID:  projectCommand.TempID,
+		SectionIDs: map[string]interface{}{},
+		SkipNotes:  !c.Bool("include-comments"),
+	}
+
+	sections := store.ProjectSections(srcID)
+	sort.Sort(sections)
+	for _, section := range sections {
+		command := todoist.NewCommand("section_add", map[string]interface{}{
+			"name":       section.Name,
+			"project_id": projectCommand.TempID,
+		})
+		opts.SectionIDs[section.ID] = command.TempID
+		commands = append(commands, command)
+	}
+
+	for _, item := range projectRootItems(store, srcID) {
+		commands = append(commands, store.CopyItemCommands(item, opts)...)
+	}
+
+	if c.Bool("include-completed") {
+		var completed todoist.Completed
+		if err := client.CompletedAll(GetContext(c), &completed); err != nil {
+			return err
+		}
+		for _, item := range completed.Items {
+			if item.ProjectID != srcID {
+				continue
+			}
+			add := todoist.NewCommand("item_add", map[string]interface{}{
+				"content":    item.Content,
+				"project_id": projectCommand.TempID,
+			})
+			commands = append(commands, add, todoist.NewCommand("item_close", map[string]interface{}{"id": add.TempID}))
+		}
+	}
+
+	if err := client.ExecCommands(GetContext(c), commands); err != nil {
+		return err
+	}
+
+	return Sync(c)
+}
+
+func SetProjectColor(c *cli.Context) error {
+	client := GetClient(c)
+
+	if len(c.Args()) != 2 {
+		return ArgumentRequired
+	}
+	name, colorName := c.Args().Get(0), c.Args().Get(1)
+
+	if AppColor(colorName) == nil {
+		return InvalidColor(colorName)
+	}
+	project := client.Store.FindProject(client.Store.Projects.GetIDByName(name))
+	if project == nil {
+		return ProjectNotFound(name)
+	}
+
+	updated := *project
+	updated.Color = colorName
+	if err := client.UpdateProject(GetContext(c), updated); err != nil {
+		return err
+	}
+
+	return Sync(c)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sachaos/todoist/lib"
+)
+
+func TestTraverseProjectTree(t *testing.T) {
+	store := testStore(t, `{"projects": [
+		{"id": "1", "name": "Inbox", "parent_id": null},
+		{"id": "2", "name": "Work", "parent_id": null},
+		{"id": "3", "name": "Website", "parent_id": "2"},
+		{"id": "4", "name": "Design", "parent_id": "3"},
+		{"id": "5", "name": "Hiring", "parent_id": "2"}
+	]}`)
+
+	lines := []string{}
+	traverseProjectTree(store.RootProject, func(pjt *todoist.Project, prefix string) {
+		lines = append(lines, prefix+pjt.Name)
+	}, "", true)
+	assert.Equal(t, []string{
+		"Inbox",
+		"Work",
+		"├─ Website",
+		"│  └─ Design",
+		"└─ Hiring",
+	}, lines, "they should be equal")
+	assert.Equal(t, 2, countChildProjects(store.FindProject("2")), "they should be equal")
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sachaos/todoist/lib"
+)
+
+var stdin = bufio.NewReader(os.Stdin)
+
+func readLine() (string, error) {
+	line, err := stdin.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// promptChoice asks the user to pick one of options and returns its index.
+func promptChoice(question string, options []string) (int, error) {
+	if !isTerminal(os.Stdin) {
+		return 0, NotInteractive
+	}
+
+	fmt.Fprintln(os.Stderr, question)
+	for i, option := range options {
+		fmt.Fprintf(os.Stderr, "  %d) %s\n", i+1, option)
+	}
+	for {
+		fmt.Fprintf(os.Stderr, "Select [1-%d]: ", len(options))
+		line, err := readLine()
+		if err != nil {
+			return 0, err
+		}
+		n, err := strconv.Atoi(line)
+		if err == nil && n >= 1 && n <= len(options) {
+			return n - 1, nil
+		}
+	}
+}
+
+// confirmItems shows the items an action is about to affect and asks the user
+// to confirm it. yes skips the question.
+func confirmItems(store *todoist.Store, action string, ids []string, yes bool) error {
+	if yes {
+		return nil
+	}
+	if !isTerminal(os.Stdin) {
+		return ConfirmationRequired
+	}
+
+	for _, id := range ids {
+		content := ""
+		if item := store.FindItem(id); item != nil {
+			content = todoist.GetContentTitle(item)
+		}
+		fmt.Fprintf(os.Stderr, "  %s %s\n", id, content)
+	}
+	return confirm(fmt.Sprintf("%s %d task(s)?", action, len(ids)), false)
+}
+
+// confirm asks the user a yes or no question, returning Aborted unless the
+// answer is yes. yes skips the question.
+func confirm(question string, yes bool) error {
+	if yes {
+		return nil
+	}
+	if !isTerminal(os.Stdin) {
+		return ConfirmationRequired
+	}
+
+	fmt.Fprintf(os.Stderr, "%s [y/N]: ", question)
+	line, err := readLine()
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(line) {
+	case "y", "yes":
+		return nil
+	default:
+		return Aborted
+	}
+}
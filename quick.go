@@ -1,19 +1,59 @@
 package main
 
 import (
-	"context"
+	"io/ioutil"
+	"os"
+	"strings"
 
 	"github.com/urfave/cli"
 )
 
+// quickLines returns the texts to quick add: the argument, the lines written
+// in the editor, or the lines read from stdin.
+func quickLines(c *cli.Context) ([]string, error) {
+	var text string
+	switch {
+	case c.Bool("editor"):
+		edited, err := editText("")
+		if err != nil {
+			return nil, err
+		}
+		text = edited
+	case c.Args().Present():
+		return []string{c.Args().First()}, nil
+	case !isTerminal(os.Stdin):
+		buf, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		text = string(buf)
+	}
+
+	lines := []string{}
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
 func Quick(c *cli.Context) error {
 	client := GetClient(c)
 
-	if !c.Args().Present() {
-		return CommandFailed
+	lines, err := quickLines(c)
+	if err != nil {
+		return err
+	}
+	if len(lines) == 0 {
+		return ArgumentRequired
 	}
 
-	client.QuickCommand(context.Background(), c.Args().First())
+	for _, line := range lines {
+		if err := client.QuickCommand(GetContext(c), line); err != nil {
+			return err
+		}
+	}
 
 	return Sync(c)
 }
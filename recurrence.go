@@ -0,0 +1,414 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/urfave/cli"
+)
+
+// recurrence is a recurring due date like those of Todoist, "every 2nd
+// friday" or "every other monday at 9am", understood locally so that its
+// dates can be previewed without a task. Only the English patterns used most
+// are understood, Todoist itself has many more.
+type recurrence struct {
+	// unit is "hour", "day", "week", "month" or "year", repeated every
+	// interval of them.
+	unit     string
+	interval int
+	// weekdays are the days of a weekly recurrence, its first day by
+	// default.
+	weekdays []time.Weekday
+	// monthDays are the days of a monthly recurrence, -1 for the last one;
+	// or nth, -1 for the last, is the week of weekday in the month.
+	monthDays []int
+	nth       int
+	weekday   time.Weekday
+	// month and day are those of a yearly recurrence, else those of its
+	// first day.
+	month time.Month
+	day   int
+
+	hasTime      bool
+	hour, minute int
+	// start and until bound the days of the recurrence, from today on by
+	// default.
+	start, until time.Time
+}
+
+var (
+	recurrenceUnits = map[string]string{
+		"hour": "hour", "hours": "hour",
+		"day": "day", "days": "day",
+		"week": "week", "weeks": "week",
+		"month": "month", "months": "month",
+		"year": "year", "years": "year",
+	}
+	recurrenceAdverbs = map[string]string{
+		"hourly": "hour", "daily": "day", "weekly": "week", "monthly": "month", "yearly": "year", "annually": "year",
+	}
+	recurrenceOrdinals = map[string]int{
+		"first": 1, "second": 2, "third": 3, "fourth": 4, "fifth": 5, "last": -1,
+	}
+	recurrenceOrdinalRegex = regexp.MustCompile(`^(\d{1,2})(st|nd|rd|th)$`)
+	recurrenceTimeRegex    = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+)
+
+// parseWeekday returns the day of the week named by s, like "mon" or
+// "monday".
+func parseWeekday(s string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		if s == name || s == name[:3] {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// parseMonth returns the month named by s, like "jan" or "january".
+func parseMonth(s string) (time.Month, bool) {
+	for month := time.January; month <= time.December; month++ {
+		name := strings.ToLower(month.String())
+		if s == name || s == name[:3] {
+			return month, true
+		}
+	}
+	return 0, false
+}
+
+// parseOrdinal returns the number of "2nd" or "second", -1 for "last".
+func parseOrdinal(s string) (int, bool) {
+	if n, ok := recurrenceOrdinals[s]; ok {
+		return n, true
+	}
+	if match := recurrenceOrdinalRegex.FindStringSubmatch(s); match != nil {
+		n, _ := strconv.Atoi(match[1])
+		return n, n >= 1 && n <= 31
+	}
+	return 0, false
+}
+
+// parseTimeOfDay returns the time of "9am", "9:30pm" or "14:00". A bare
+// number is a time only when required is set, as after "at".
+func parseTimeOfDay(s string, required bool) (hour int, minute int, ok bool) {
+	match := recurrenceTimeRegex.FindStringSubmatch(s)
+	if match == nil || (!required && match[2] == "" && match[3] == "") {
+		return 0, 0, false
+	}
+	hour, _ = strconv.Atoi(match[1])
+	if match[2] != "" {
+		minute, _ = strconv.Atoi(match[2])
+	}
+	switch match[3] {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+		hour %= 12
+		if match[3] == "pm" {
+			hour += 12
+		}
+	}
+	return hour, minute, hour < 24 && minute < 60
+}
+
+// parseRecurrence parses s, like "every day", "every 3 weeks", "every mon,
+// fri at 9am", "every other monday", "every weekday", "every 1st, 15th",
+// "every last day", "every 2nd friday", "every jan 15" or "monthly",
+// optionally followed by "starting 2006-01-02" and "until 2006-01-02".
+// "every!", repeating from the day a task is completed, is the same here.
+func parseRecurrence(s string) (*recurrence, error) {
+	r := &recurrence{interval: 1}
+	words := []string{}
+	fields := strings.Fields(strings.ToLower(strings.Replace(s, ",", " , ", -1)))
+	for i := 0; i < len(fields); i++ {
+		word := fields[i]
+		switch word {
+		case "starting", "from", "until", "ending":
+			if i+1 == len(fields) {
+				return nil, fmt.Errorf("%q needs a date", word)
+			}
+			i++
+			date, err := time.ParseInLocation(todoist.RFC3339Date, fields[i], time.Local)
+			if err != nil {
+				return nil, fmt.Errorf("%q is not a date like 2006-01-02", fields[i])
+			}
+			if word == "starting" || word == "from" {
+				r.start = date
+			} else {
+				r.until = date
+			}
+			continue
+		case "at":
+			if i+1 == len(fields) {
+				return nil, fmt.Errorf("%q needs a time", word)
+			}
+			i++
+			hour, minute, ok := parseTimeOfDay(fields[i], true)
+			if !ok {
+				return nil, fmt.Errorf("%q is not a time like 9am or 14:00", fields[i])
+			}
+			r.hasTime, r.hour, r.minute = true, hour, minute
+			continue
+		case "and", ",", "on", "the", "of":
+			continue
+		case "month":
+			// As in "every last day of the month".
+			if i > 0 && fields[i-1] == "the" {
+				continue
+			}
+		}
+		if hour, minute, ok := parseTimeOfDay(word, false); ok {
+			r.hasTime, r.hour, r.minute = true, hour, minute
+			continue
+		}
+		words = append(words, word)
+	}
+
+	if len(words) == 0 {
+		return nil, fmt.Errorf("no recurrence")
+	}
+	if unit, ok := recurrenceAdverbs[words[0]]; ok && len(words) == 1 {
+		r.unit = unit
+		return r, nil
+	}
+	if words[0] != "every" && words[0] != "every!" {
+		return nil, fmt.Errorf("%q is not a recurrence, which starts with every", s)
+	}
+	words = words[1:]
+
+	// A date of the year, "jan 15" or "15th january".
+	for i, word := range words {
+		if month, ok := parseMonth(word); ok && len(words) == 2 {
+			day, err := strconv.Atoi(words[1-i])
+			if err != nil {
+				if day, ok = parseOrdinal(words[1-i]); !ok || day < 1 {
+					return nil, fmt.Errorf("%q is not a day of %s", words[1-i], month)
+				}
+			}
+			if day < 1 || day > 31 {
+				return nil, fmt.Errorf("%q is not a day of %s", words[1-i], month)
+			}
+			r.unit, r.month, r.day = "year", month, day
+			return r, nil
+		}
+	}
+
+	if len(words) > 0 && words[0] == "other" {
+		r.interval = 2
+		words = words[1:]
+	} else if len(words) > 0 {
+		if n, err := strconv.Atoi(words[0]); err == nil {
+			if n < 1 {
+				return nil, fmt.Errorf("%q is not an interval", words[0])
+			}
+			r.interval = n
+			words = words[1:]
+		}
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("%q has no day or unit", s)
+	}
+
+	if unit, ok := recurrenceUnits[words[0]]; ok {
+		if len(words) > 1 {
+			return nil, fmt.Errorf("unexpected %q", strings.Join(words[1:], " "))
+		}
+		r.unit = unit
+		return r, nil
+	}
+
+	switch words[0] {
+	case "weekday", "workday":
+		r.unit = "week"
+		r.weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+		words = words[1:]
+	case "weekend":
+		r.unit = "week"
+		r.weekdays = []time.Weekday{time.Saturday, time.Sunday}
+		words = words[1:]
+	}
+	if r.unit == "" {
+		if n, ok := parseOrdinal(words[0]); ok && len(words) == 2 {
+			// "2nd friday" or "last day" of the month.
+			if day, ok := parseWeekday(words[1]); ok && n <= 5 {
+				r.unit, r.nth, r.weekday = "month", n, day
+				return r, nil
+			}
+			if words[1] == "day" && n == -1 {
+				r.unit, r.monthDays = "month", []int{-1}
+				return r, nil
+			}
+		}
+	}
+	for _, word := range words {
+		if day, ok := parseWeekday(word); ok && r.unit != "month" {
+			r.unit = "week"
+			r.weekdays = append(r.weekdays, day)
+		} else if n, ok := parseOrdinal(word); ok && n > 0 && r.unit != "week" {
+			r.unit = "month"
+			r.monthDays = append(r.monthDays, n)
+		} else {
+			return nil, fmt.Errorf("unexpected %q", word)
+		}
+	}
+	return r, nil
+}
+
+func invalidRecurrence(s string, err error) *Error {
+	return &Error{Code: "invalid_argument", Message: fmt.Sprintf("invalid recurrence %q: %s", s, err), Hint: "give a recurrence like \"every day\", \"every other monday at 9am\", \"every 2nd friday\" or \"every jan 15\""}
+}
+
+// dayNumber counts the days of the calendar, so that days between dates don't
+// depend on daylight saving time.
+func dayNumber(t time.Time) int {
+	return int(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / (24 * 60 * 60))
+}
+
+// monthDay is day of the month of t, the last one for -1, or for days it
+// doesn't have like the 31st of April.
+func monthDay(t time.Time, day int) int {
+	last := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day == -1 || day > last {
+		return last
+	}
+	return day
+}
+
+// matches reports whether day is one of r, which starts on first.
+func (r *recurrence) matches(day time.Time, first time.Time) bool {
+	switch r.unit {
+	case "day":
+		return (dayNumber(day)-dayNumber(first))%r.interval == 0
+	case "week":
+		weekdays := r.weekdays
+		if len(weekdays) == 0 {
+			weekdays = []time.Weekday{first.Weekday()}
+		}
+		// Weeks start on Monday, like in Todoist.
+		monday := func(t time.Time) int { return dayNumber(t) - (int(t.Weekday())+6)%7 }
+		if (monday(day)-monday(first))/7%r.interval != 0 {
+			return false
+		}
+		for _, weekday := range weekdays {
+			if day.Weekday() == weekday {
+				return true
+			}
+		}
+		return false
+	case "month":
+		months := (day.Year()-first.Year())*12 + int(day.Month()) - int(first.Month())
+		if months%r.interval != 0 {
+			return false
+		}
+		if r.nth != 0 {
+			if day.Weekday() != r.weekday {
+				return false
+			}
+			if r.nth == -1 {
+				return day.AddDate(0, 0, 7).Month() != day.Month()
+			}
+			return (day.Day()-1)/7+1 == r.nth
+		}
+		monthDays := r.monthDays
+		if len(monthDays) == 0 {
+			monthDays = []int{first.Day()}
+		}
+		for _, n := range monthDays {
+			if day.Day() == monthDay(day, n) {
+				return true
+			}
+		}
+		return false
+	case "year":
+		month, n := r.month, r.day
+		if month == 0 {
+			month, n = first.Month(), first.Day()
+		}
+		return (day.Year()-first.Year())%r.interval == 0 && day.Month() == month && day.Day() == monthDay(day, n)
+	}
+	return false
+}
+
+// Dates returns the next count dates of r from now on, fewer if it ends
+// before, and whether they are all day rather than at a time.
+func (r *recurrence) Dates(now time.Time, count int) ([]time.Time, bool) {
+	first := r.start
+	if first.IsZero() {
+		first = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	}
+	lastDay := -1
+	if !r.until.IsZero() {
+		lastDay = dayNumber(r.until)
+	}
+	dates := []time.Time{}
+
+	if r.unit == "hour" {
+		t := first
+		if r.hasTime {
+			t = time.Date(first.Year(), first.Month(), first.Day(), r.hour, r.minute, 0, 0, first.Location())
+		} else if r.start.IsZero() {
+			// From the next full hour.
+			t = now.Truncate(time.Hour)
+			if t.Before(now) {
+				t = t.Add(time.Hour)
+			}
+		}
+		for len(dates) < count && (lastDay < 0 || dayNumber(t) <= lastDay) {
+			if !t.Before(now) {
+				dates = append(dates, t)
+			}
+			t = t.Add(time.Duration(r.interval) * time.Hour)
+		}
+		return dates, false
+	}
+
+	// A century is enough for any recurrence with dates.
+	for day := first; len(dates) < count && day.Before(first.AddDate(100, 0, 0)); day = day.AddDate(0, 0, 1) {
+		if lastDay >= 0 && dayNumber(day) > lastDay {
+			break
+		}
+		if !r.matches(day, first) {
+			continue
+		}
+		date := day
+		if r.hasTime {
+			date = time.Date(day.Year(), day.Month(), day.Day(), r.hour, r.minute, 0, 0, day.Location())
+			if date.Before(now) {
+				continue
+			}
+		} else if dayNumber(day) < dayNumber(now) {
+			continue
+		}
+		dates = append(dates, date)
+	}
+	return dates, !r.hasTime
+}
+
+func RecurrencePreview(c *cli.Context) error {
+	if !c.Args().Present() {
+		return ArgumentRequired
+	}
+	spec := strings.Join(c.Args(), " ")
+	r, err := parseRecurrence(spec)
+	if err != nil {
+		return invalidRecurrence(spec, err)
+	}
+	count := c.Int("count")
+	if count < 1 {
+		return &Error{Code: "invalid_argument", Message: fmt.Sprintf("invalid count %d", count), Hint: "give a count of 1 or more"}
+	}
+
+	dates, allDay := r.Dates(time.Now(), count)
+	defer writer.Flush()
+	writer.WriteHeader([]string{"Date"})
+	for _, date := range dates {
+		writer.Write([]string{DueDateFormat(date, allDay)})
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	for _, s := range []string{"", "tomorrow", "every", "every 0 days", "every foo", "every 3 days at noon", "every day until soon", "every jan 32", "every 6th friday", "every monday, 15th"} {
+		_, err := parseRecurrence(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestRecurrenceDates(t *testing.T) {
+	// 2020-01-06 is a Monday.
+	now := time.Date(2020, 1, 6, 10, 0, 0, 0, time.Local)
+	day := func(month time.Month, day int) time.Time {
+		return time.Date(2020, month, day, 0, 0, 0, 0, time.Local)
+	}
+	at := func(month time.Month, day int, hour int) time.Time {
+		return time.Date(2020, month, day, hour, 0, 0, 0, time.Local)
+	}
+	for _, tc := range []struct {
+		recurrence string
+		expected   []time.Time
+		allDay     bool
+	}{
+		{"every day", []time.Time{day(1, 6), day(1, 7), day(1, 8)}, true},
+		{"every! 3 days", []time.Time{day(1, 6), day(1, 9), day(1, 12)}, true},
+		{"every mon, fri", []time.Time{day(1, 6), day(1, 10), day(1, 13)}, true},
+		{"every other wednesday", []time.Time{day(1, 8), day(1, 22), day(2, 5)}, true},
+		{"every weekday", []time.Time{day(1, 6), day(1, 7), day(1, 8)}, true},
+		{"every weekend", []time.Time{day(1, 11), day(1, 12), day(1, 18)}, true},
+		{"every monday at 9am", []time.Time{at(1, 13, 9), at(1, 20, 9), at(1, 27, 9)}, false},
+		{"every day 14:00", []time.Time{at(1, 6, 14), at(1, 7, 14), at(1, 8, 14)}, false},
+		{"every 4 hours", []time.Time{at(1, 6, 10), at(1, 6, 14), at(1, 6, 18)}, false},
+		{"every 2nd friday", []time.Time{day(1, 10), day(2, 14), day(3, 13)}, true},
+		{"every last friday", []time.Time{day(1, 31), day(2, 28), day(3, 27)}, true},
+		{"every 1st and 15th", []time.Time{day(1, 15), day(2, 1), day(2, 15)}, true},
+		{"every 31st", []time.Time{day(1, 31), day(2, 29), day(3, 31)}, true},
+		{"every last day of the month", []time.Time{day(1, 31), day(2, 29), day(3, 31)}, true},
+		{"every 2 months", []time.Time{day(1, 6), day(3, 6), day(5, 6)}, true},
+		{"every feb 29", []time.Time{day(2, 29), time.Date(2021, 2, 28, 0, 0, 0, 0, time.Local), time.Date(2022, 2, 28, 0, 0, 0, 0, time.Local)}, true},
+		{"weekly", []time.Time{day(1, 6), day(1, 13), day(1, 20)}, true},
+		{"every 3 days starting 2020-01-01", []time.Time{day(1, 7), day(1, 10), day(1, 13)}, true},
+		{"every day until 2020-01-07", []time.Time{day(1, 6), day(1, 7)}, true},
+	} {
+		r, err := parseRecurrence(tc.recurrence)
+		if !assert.NoError(t, err, tc.recurrence) {
+			continue
+		}
+		dates, allDay := r.Dates(now, 3)
+		assert.Equal(t, tc.expected, dates, tc.recurrence)
+		assert.Equal(t, tc.allDay, allDay, tc.recurrence)
+	}
+}
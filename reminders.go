@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/urfave/cli"
+)
+
+// ReminderTypeFormat describes when a reminder of a task goes off.
+func ReminderTypeFormat(reminder todoist.Reminder) string {
+	switch reminder.Type {
+	case "relative":
+		return fmt.Sprintf("%d min before", reminder.MinuteOffset)
+	case "location":
+		return "at " + reminder.Name
+	}
+	return reminder.Type
+}
+
+func Reminders(c *cli.Context) error {
+	client := GetClient(c)
+	now := time.Now()
+	endOfToday := startOfDay(now).AddDate(0, 0, 1)
+
+	defer writer.Flush()
+
+	writer.WriteHeader([]string{"ID", "Time", "Type", "Task", "Content"})
+
+	for _, reminder := range client.Store.UpcomingReminders(now) {
+		t, ok := reminder.Time(client.Store)
+		if c.Bool("today") && (!ok || !t.Before(endOfToday)) {
+			continue
+		}
+		item := client.Store.FindItem(reminder.ItemID)
+		writer.Write([]string{
+			IdFormat(reminder),
+			dueDateString(t, false),
+			ReminderTypeFormat(reminder),
+			IdFormat(item),
+			ContentFormat(item),
+		})
+	}
+	return nil
+}
+
+// SnoozeReminder makes a reminder go off again after a while, counted from
+// when it was due or from now if that has passed.
+func SnoozeReminder(c *cli.Context) error {
+	client := GetClient(c)
+
+	if len(c.Args()) != 2 {
+		return ArgumentRequired
+	}
+	reminder := client.Store.FindReminder(c.Args().Get(0))
+	if reminder == nil {
+		return &Error{Code: "reminder_not_found", Message: fmt.Sprintf("reminder %q not found", c.Args().Get(0)), Hint: "see the ids of `todoist reminders`"}
+	}
+	after, err := parseDuration(c.Args().Get(1))
+	if err != nil {
+		return err
+	}
+
+	at, ok := reminder.Time(client.Store)
+	if !ok {
+		return &Error{Code: "invalid_argument", Message: "the reminder has no time to snooze", Hint: "only reminders at a time can be snoozed"}
+	}
+	if now := time.Now(); at.Before(now) {
+		at = now
+	}
+	if err := client.SnoozeReminder(GetContext(c), *reminder, at.Add(after)); err != nil {
+		return err
+	}
+	return Sync(c)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/sachaos/todoist/lib"
+)
+
+// looksLikeID reports whether s can be a task id, which are made of digits
+// and lowercase letters and never contain spaces.
+func looksLikeID(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsDigit(r) && !unicode.IsLower(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// fuzzyMatch reports whether the characters of query appear in order in s.
+func fuzzyMatch(s string, query string) bool {
+	runes := []rune(query)
+	i := 0
+	for _, r := range s {
+		if i < len(runes) && r == runes[i] {
+			i++
+		}
+	}
+	return i == len(runes)
+}
+
+// MatchItems returns the unchecked items whose content contains query, or
+// fuzzily matches it when none contains it, ignoring case.
+func MatchItems(store *todoist.Store, query string) []*todoist.Item {
+	query = strings.ToLower(query)
+	contains := []*todoist.Item{}
+	fuzzy := []*todoist.Item{}
+	for i := range store.Items {
+		item := &store.Items[i]
+		if item.Checked || item.IsDeleted {
+			continue
+		}
+		content := strings.ToLower(todoist.GetContentTitle(item))
+		if strings.Contains(content, query) {
+			contains = append(contains, item)
+		} else if fuzzyMatch(content, query) {
+			fuzzy = append(fuzzy, item)
+		}
+	}
+	if len(contains) > 0 {
+		return contains
+	}
+	return fuzzy
+}
+
+// ResolveItemID turns a command argument into a task id. Task ids (or unique
+// prefixes of one) are used as they are; anything else is matched against
+// task contents, asking the user when several tasks match.
+func ResolveItemID(client *todoist.Client, arg string) (string, error) {
+	if looksLikeID(arg) {
+		if id := client.CompleteItemIDByPrefix(arg); client.Store.FindItem(id) != nil {
+			return id, nil
+		}
+	}
+
+	items := MatchItems(client.Store, arg)
+	switch len(items) {
+	case 0:
+		return "", NoMatchingTask(arg)
+	case 1:
+		return items[0].ID, nil
+	}
+
+	options := make([]string, len(items))
+	for i, item := range items {
+		options[i] = fmt.Sprintf("%s %s", item.ID, todoist.GetContentTitle(item))
+	}
+	i, err := promptChoice(fmt.Sprintf("%d tasks match %q:", len(items), arg), options)
+	if err == NotInteractive {
+		return "", AmbiguousTask(arg, len(items))
+	}
+	if err != nil {
+		return "", err
+	}
+	return items[i].ID, nil
+}
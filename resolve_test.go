@@ -0,0 +1,25 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sachaos/todoist/lib"
+)
+
+func TestMatchItems(t *testing.T) {
+	store := &todoist.Store{Items: todoist.Items{
+		todoist.Item{BaseItem: todoist.BaseItem{HaveID: todoist.HaveID{ID: "1"}, Content: "Pay rent"}},
+		todoist.Item{BaseItem: todoist.BaseItem{HaveID: todoist.HaveID{ID: "2"}, Content: "Pay phone bill"}},
+		todoist.Item{BaseItem: todoist.BaseItem{HaveID: todoist.HaveID{ID: "3"}, Content: "Paint rent house"}, Checked: true},
+	}}
+
+	matches := MatchItems(store, "pay rent")
+	assert.Equal(t, 1, len(matches), "they should be equal")
+	assert.Equal(t, "1", matches[0].ID, "they should be equal")
+
+	assert.Equal(t, 2, len(MatchItems(store, "pay")), "they should be equal")
+	assert.Equal(t, 1, len(MatchItems(store, "phbill")), "they should be equal")
+	assert.Equal(t, 0, len(MatchItems(store, "groceries")), "they should be equal")
+}
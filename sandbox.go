@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"time"
+
+	"github.com/sachaos/todoist/lib"
+)
+
+var sandboxCachePath = filepath.Join(configPath, ".todoist.sandbox.json")
+
+// sandboxEmail is the email of the user of the demo data, which tells the
+// caches of the sandbox apart from those of accounts.
+const sandboxEmail = "sandbox@example.com"
+
+// sandboxDemoState returns the data a new sandbox starts with.
+func sandboxDemoState() ([]byte, error) {
+	now := time.Now()
+	date := func(days int) map[string]interface{} {
+		return map[string]interface{}{"date": now.AddDate(0, 0, days).Format(todoist.RFC3339Date), "string": "", "is_recurring": false}
+	}
+	order := 0
+	item := func(id, projectID string, content string, priority int, labels []string, due interface{}, parentID interface{}) map[string]interface{} {
+		order++
+		return map[string]interface{}{
+			"id": id, "project_id": projectID, "content": content, "priority": priority,
+			"labels": labels, "due": due, "parent_id": parentID, "checked": false, "child_order": order,
+		}
+	}
+	assign := func(item map[string]interface{}, uid string) map[string]interface{} {
+		item["responsible_uid"] = uid
+		return item
+	}
+	state := map[string]interface{}{
+		"user": map[string]interface{}{"id": "1", "full_name": "Sandbox User", "email": sandboxEmail, "inbox_project_id": "1", "karma": 1000, "karma_trend": "up"},
+		"projects": []interface{}{
+			map[string]interface{}{"id": "1", "name": "Inbox", "inbox_project": true, "parent_id": nil, "color": "grey", "child_order": 1},
+			map[string]interface{}{"id": "2", "name": "Work", "parent_id": nil, "color": "blue", "child_order": 2, "is_favorite": true},
+			map[string]interface{}{"id": "3", "name": "Website", "parent_id": "2", "color": "teal", "child_order": 1},
+			map[string]interface{}{"id": "4", "name": "Home", "parent_id": nil, "color": "green", "child_order": 3},
+			map[string]interface{}{"id": "6", "name": "Roadmap", "parent_id": nil, "color": "violet", "child_order": 5, "workspace_id": "51"},
+		},
+		"workspaces": []interface{}{
+			map[string]interface{}{"id": "51", "name": "Acme"},
+		},
+		"workspace_users": []interface{}{
+			map[string]interface{}{"user_id": "1", "workspace_id": "51", "full_name": "Sandbox User", "email": sandboxEmail, "role": "ADMIN"},
+			map[string]interface{}{"user_id": "4", "workspace_id": "51", "full_name": "Jo Park", "email": "jo@example.com", "role": "MEMBER"},
+		},
+		"labels": []interface{}{
+			map[string]interface{}{"id": "11", "name": "office", "color": "blue"},
+			map[string]interface{}{"id": "12", "name": "errand", "color": "green"},
+			map[string]interface{}{"id": "13", "name": "waiting", "color": "charcoal"},
+		},
+		"filters": []interface{}{
+			map[string]interface{}{"id": "21", "name": "Urgent", "query": "p1 & (today | overdue)", "color": "red"},
+		},
+		"sections": []interface{}{
+			map[string]interface{}{"id": "31", "project_id": "3", "name": "Launch", "section_order": 1},
+		},
+		"items": []interface{}{
+			item("101", "1", "Try the todoist CLI sandbox", 4, []string{}, date(0), nil),
+			assign(item("102", "2", "Prepare weekly report", 3, []string{"office"}, date(1), nil), "1"),
+			assign(item("103", "2", "Reply to customer emails", 2, []string{"office"}, date(-1), nil), "2"),
+			item("104", "3", "Write release notes", 1, []string{}, map[string]interface{}{"date": now.AddDate(0, 0, 3).Format(todoist.RFC3339Date) + "T15:00:00", "string": "", "is_recurring": false}, nil),
+			item("105", "3", "Check links on the landing page", 1, []string{"waiting"}, nil, "104"),
+			item("106", "4", "Buy groceries", 1, []string{"errand"}, date(0), nil),
+			item("110", "6", "Plan the next quarter", 2, []string{}, date(7), nil),
+			item("107", "4", "Water the plants", 1, []string{}, map[string]interface{}{"date": now.Format(todoist.RFC3339Date), "string": "every 3 days", "is_recurring": true}, nil),
+		},
+		"reminders": []interface{}{
+			map[string]interface{}{"id": "41", "item_id": "102", "type": "absolute", "due": map[string]interface{}{"date": now.AddDate(0, 0, 1).Format(todoist.RFC3339Date) + "T09:00:00"}},
+			map[string]interface{}{"id": "42", "item_id": "106", "type": "location", "name": "Supermarket"},
+			map[string]interface{}{"id": "43", "item_id": "104", "type": "relative", "minute_offset": 30},
+		},
+		"collaborators": []interface{}{
+			map[string]interface{}{"id": "1", "full_name": "Sandbox User", "email": sandboxEmail},
+			map[string]interface{}{"id": "2", "full_name": "Alex Kim", "email": "alex@example.com"},
+			map[string]interface{}{"id": "3", "full_name": "Sam Lee", "email": "sam@example.com"},
+		},
+		"collaborator_states": []interface{}{
+			map[string]interface{}{"project_id": "2", "user_id": "1", "state": "active", "role": "CREATOR"},
+			map[string]interface{}{"project_id": "2", "user_id": "2", "state": "active", "role": "READ_WRITE"},
+			map[string]interface{}{"project_id": "2", "user_id": "3", "state": "active", "role": "READ_ONLY"},
+		},
+		"notes": []interface{}{
+			map[string]interface{}{"id": "201", "item_id": "102", "project_id": "2", "content": "Numbers are in the shared spreadsheet"},
+			map[string]interface{}{"id": "202", "item_id": "103", "project_id": "2", "content": "The customer sent this", "file_attachment": map[string]interface{}{
+				"file_name": "screenshot.png", "file_type": "image/png", "file_url": "https://files.todoist.com/sandbox/screenshot.png", "resource_type": "image",
+			}},
+		},
+	}
+	return json.Marshal(state)
+}
+
+// withSandboxArchive adds an archived project with tasks to the demo data
+// of state. Syncing leaves them out, so they are not in the cache the
+// sandbox starts from.
+func withSandboxArchive(state []byte) ([]byte, error) {
+	var data map[string]interface{}
+	if err := json.Unmarshal(state, &data); err != nil {
+		return nil, err
+	}
+	projects, _ := data["projects"].([]interface{})
+	items, _ := data["items"].([]interface{})
+	data["projects"] = append(projects,
+		map[string]interface{}{"id": "5", "name": "Garden", "parent_id": nil, "color": "olive_green", "child_order": 4, "is_archived": true},
+	)
+	data["items"] = append(items,
+		map[string]interface{}{"id": "108", "project_id": "5", "content": "Plant tulip bulbs", "priority": 1, "labels": []string{}, "parent_id": nil, "checked": false, "child_order": 8},
+		map[string]interface{}{"id": "109", "project_id": "5", "content": "Fix the fence", "priority": 2, "labels": []string{}, "parent_id": nil, "checked": false, "child_order": 9},
+	)
+	return json.Marshal(data)
+}
+
+// NewSandboxClient returns a client backed by a sandbox which starts from
+// store, or from demo data if store has never been synced.
+func NewSandboxClient(config *todoist.Config, store *todoist.Store) (*todoist.Client, error) {
+	var state []byte
+	var err error
+	if store.SyncToken == "" {
+		state, err = sandboxDemoState()
+	} else {
+		state, err = json.Marshal(store)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The sandbox may start from the cache of an account, whose ids the
+	// archive could clash with.
+	if store.SyncToken == "" || store.User.Email == sandboxEmail {
+		if state, err = withSandboxArchive(state); err != nil {
+			return nil, err
+		}
+	}
+	sandbox, err := todoist.NewSandbox(state)
+	if err != nil {
+		return nil, err
+	}
+	client := todoist.NewClient(config)
+	client.Transport = sandbox
+	return client, nil
+}
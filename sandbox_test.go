@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSandboxArchive(t *testing.T) {
+	ctx := context.Background()
+	archived := func(store *todoist.Store) []string {
+		client, err := NewSandboxClient(&todoist.Config{}, store)
+		assert.NoError(t, err)
+		projects, err := client.ArchivedProjects(ctx)
+		assert.NoError(t, err)
+		names := []string{}
+		for _, project := range projects {
+			names = append(names, project.Name)
+		}
+		return names
+	}
+
+	// The demo data, and the cache of the sandbox synced from it.
+	assert.Equal(t, []string{"Garden"}, archived(&todoist.Store{}), "they should be equal")
+	client, err := NewSandboxClient(&todoist.Config{}, &todoist.Store{})
+	assert.NoError(t, err)
+	assert.NoError(t, client.Sync(ctx))
+	assert.Equal(t, []string{"Garden"}, archived(client.Store), "they should be equal")
+
+	// The cache of an account.
+	store := testStore(t, `{"sync_token": "abc", "user": {"id": "7", "email": "me@example.com"}, "projects": [{"id": "5", "name": "Work"}]}`)
+	assert.Equal(t, []string{}, archived(store), "they should be equal")
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli"
+)
+
+var schedulePath = filepath.Join(configPath, ".todoist.schedule.json")
+
+// ScheduledJob is a command line of todoist run by `schedule run` on Cron.
+type ScheduledJob struct {
+	ID      int       `json:"id"`
+	Cron    string    `json:"cron"`
+	Command string    `json:"command"`
+	LastRun time.Time `json:"last_run"`
+	// LastError is why the last run failed, or "".
+	LastError string `json:"last_error,omitempty"`
+}
+
+type ScheduleState struct {
+	Jobs []ScheduledJob `json:"jobs"`
+}
+
+func invalidSchedule(spec string, err error) *Error {
+	return &Error{Code: "invalid_argument", Message: fmt.Sprintf("invalid schedule %q: %s", spec, err), Hint: "give minute, hour, day of month, month and day of week like \"0 8 * * mon-fri\", or @daily"}
+}
+
+// splitCommandLine splits line into arguments at spaces outside of single
+// or double quotes, as a shell would.
+func splitCommandLine(line string) ([]string, error) {
+	args := []string{}
+	var arg strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			arg.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("unterminated quote or escape in %q", line)
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
+// dueJobs returns the jobs of state scheduled at the minute of t.
+func dueJobs(state ScheduleState, t time.Time) []ScheduledJob {
+	jobs := []ScheduledJob{}
+	for _, job := range state.Jobs {
+		schedule, err := parseCron(job.Cron)
+		if err == nil && schedule.Matches(t) {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
+
+func ListSchedule(c *cli.Context) error {
+	var state ScheduleState
+	if err := readJSONFile(schedulePath, &state); err != nil {
+		return err
+	}
+
+	defer writer.Flush()
+
+	writer.WriteHeader([]string{"ID", "Cron", "Command", "Next", "LastRun", "Error"})
+	now := time.Now()
+	for _, job := range state.Jobs {
+		next, lastRun := "", ""
+		if schedule, err := parseCron(job.Cron); err == nil {
+			if t := schedule.Next(now); !t.IsZero() {
+				next = t.Format(ShortDateTimeFormat)
+			}
+		}
+		if !job.LastRun.IsZero() {
+			lastRun = job.LastRun.Local().Format(ShortDateTimeFormat)
+		}
+		writer.Write([]string{strconv.Itoa(job.ID), job.Cron, job.Command, next, lastRun, job.LastError})
+	}
+	return nil
+}
+
+func AddSchedule(c *cli.Context) error {
+	if len(c.Args()) < 2 {
+		return ArgumentRequired
+	}
+	spec := c.Args().Get(0)
+	if _, err := parseCron(spec); err != nil {
+		return invalidSchedule(spec, err)
+	}
+	command := strings.Join(c.Args().Tail(), " ")
+	args, err := splitCommandLine(command)
+	if err != nil || len(args) == 0 {
+		return &Error{Code: "invalid_argument", Message: fmt.Sprintf("invalid command %q", command), Hint: "quote the command like \"sync\" or \"add 'Water plants'\""}
+	}
+	// Subcommands run in an app of their own, the commands are in the top one.
+	root := c
+	for root.Parent() != nil {
+		root = root.Parent()
+	}
+	if args[0] == "schedule" || root.App.Command(args[0]) == nil {
+		return &Error{Code: "invalid_argument", Message: fmt.Sprintf("unknown command %q", args[0]), Hint: "give a todoist command without todoist, see `todoist help`"}
+	}
+
+	var state ScheduleState
+	if err := readJSONFile(schedulePath, &state); err != nil {
+		return err
+	}
+	job := ScheduledJob{ID: 1, Cron: spec, Command: command}
+	for _, existing := range state.Jobs {
+		if existing.ID >= job.ID {
+			job.ID = existing.ID + 1
+		}
+	}
+	state.Jobs = append(state.Jobs, job)
+	if err := writeJSONFile(schedulePath, state); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Added job %d, run by `todoist schedule run`\n", job.ID)
+	return nil
+}
+
+func RemoveSchedule(c *cli.Context) error {
+	if !c.Args().Present() {
+		return ArgumentRequired
+	}
+	notFound := &Error{Code: "id_not_found", Message: fmt.Sprintf("no job %s", c.Args().First()), Hint: "see the jobs with `todoist schedule`"}
+	id, err := strconv.Atoi(c.Args().First())
+	if err != nil {
+		return notFound
+	}
+
+	var state ScheduleState
+	if err := readJSONFile(schedulePath, &state); err != nil {
+		return err
+	}
+	for i, job := range state.Jobs {
+		if job.ID == id {
+			state.Jobs = append(state.Jobs[:i], state.Jobs[i+1:]...)
+			return writeJSONFile(schedulePath, state)
+		}
+	}
+	return notFound
+}
+
+// runJob runs the command line of job with executable, passing on the
+// global flags which change where todoist keeps its data.
+func runJob(c *cli.Context, executable string, job ScheduledJob) error {
+	args, err := splitCommandLine(job.Command)
+	if err != nil {
+		return err
+	}
+	if path := c.GlobalString("cache-path"); path != "" {
+		args = append([]string{"--cache-path", path}, args...)
+	}
+	if c.GlobalBool("sandbox") {
+		args = append([]string{"--sandbox"}, args...)
+	}
+	cmd := exec.CommandContext(GetContext(c), executable, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func RunSchedule(c *cli.Context) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "Running the scheduled jobs, see them with `todoist schedule`")
+
+	for {
+		// Wake up at the start of each minute, which is when jobs are due.
+		now := time.Now()
+		wait := now.Truncate(time.Minute).Add(time.Minute).Sub(now)
+		select {
+		case <-time.After(wait):
+		case <-GetContext(c).Done():
+			return nil
+		}
+
+		// The jobs are read each minute, so that changes apply without a
+		// restart.
+		minute := time.Now().Truncate(time.Minute)
+		var state ScheduleState
+		if err := readJSONFile(schedulePath, &state); err != nil {
+			fmt.Fprintln(os.Stderr, "reading the schedule failed:", err)
+			continue
+		}
+		results := map[int]string{}
+		for _, job := range dueJobs(state, minute) {
+			fmt.Fprintf(os.Stderr, "%s running job %d: todoist %s\n", minute.Format(ShortDateTimeFormat), job.ID, job.Command)
+			results[job.ID] = ""
+			if err := runJob(c, executable, job); err != nil {
+				fmt.Fprintf(os.Stderr, "job %d failed: %s\n", job.ID, err)
+				results[job.ID] = err.Error()
+			}
+		}
+		if len(results) == 0 {
+			continue
+		}
+
+		// Jobs may have been changed while running.
+		state = ScheduleState{}
+		if err := readJSONFile(schedulePath, &state); err != nil {
+			fmt.Fprintln(os.Stderr, "reading the schedule failed:", err)
+			continue
+		}
+		for i, job := range state.Jobs {
+			if result, ok := results[job.ID]; ok {
+				state.Jobs[i].LastRun = minute
+				state.Jobs[i].LastError = result
+			}
+		}
+		if err := writeJSONFile(schedulePath, state); err != nil {
+			fmt.Fprintln(os.Stderr, "writing the schedule failed:", err)
+		}
+	}
+}
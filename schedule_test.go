@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitCommandLine(t *testing.T) {
+	args, err := splitCommandLine(`add  --date "today 5pm" 'Buy "milk"' it\'s`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"add", "--date", "today 5pm", `Buy "milk"`, "it's"}, args, "they should be equal")
+
+	args, err = splitCommandLine(`list --filter ""`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"list", "--filter", ""}, args, "they should be equal")
+
+	_, err = splitCommandLine(`add "unterminated`)
+	assert.Error(t, err)
+}
+
+func TestDueJobs(t *testing.T) {
+	state := ScheduleState{Jobs: []ScheduledJob{
+		{ID: 1, Cron: "0 8 * * *", Command: "sync"},
+		{ID: 2, Cron: "*/5 * * * *", Command: "sync"},
+		{ID: 3, Cron: "invalid", Command: "sync"},
+	}}
+	jobs := dueJobs(state, time.Date(2020, 1, 6, 8, 0, 0, 0, time.Local))
+	assert.Equal(t, 2, len(jobs), "they should be equal")
+	jobs = dueJobs(state, time.Date(2020, 1, 6, 8, 5, 0, 0, time.Local))
+	assert.Equal(t, 1, len(jobs), "they should be equal")
+	assert.Equal(t, 2, jobs[0].ID, "they should be equal")
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/urfave/cli"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema is the part of JSON Schema describing the JSON output.
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	AnyOf                []*JSONSchema          `json:"anyOf,omitempty"`
+	MinItems             int                    `json:"minItems,omitempty"`
+	MaxItems             int                    `json:"maxItems,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+}
+
+// tableSchema is the schema of the rows of a table as the JSON writer
+// writes them, objects keyed by the header, with the columns required and
+// optional.
+func tableSchema(required []string, optional ...string) *JSONSchema {
+	noOthers := false
+	row := &JSONSchema{Type: "object", Properties: map[string]*JSONSchema{}, Required: required, AdditionalProperties: &noOthers}
+	for _, key := range append(append([]string{}, required...), optional...) {
+		row.Properties[key] = &JSONSchema{Type: "string"}
+	}
+	return row
+}
+
+// recordSchema is the schema of the rows written without a header, arrays
+// of n strings.
+func recordSchema(n int) *JSONSchema {
+	return &JSONSchema{Type: "array", Items: &JSONSchema{Type: "string"}, MinItems: n, MaxItems: n}
+}
+
+// listSchema is the schema of the rows of list, with any of the columns of
+// listColumns, which also head the groups.
+func listSchema() *JSONSchema {
+	headers := []string{}
+	for _, column := range listColumns(nil, &todoist.Store{}) {
+		headers = append(headers, column.header)
+	}
+	sort.Strings(headers)
+	return tableSchema(nil, headers...)
+}
+
+// commandSchemas are the schemas of the rows each command writes, by the
+// full name of the command.
+var commandSchemas = map[string]*JSONSchema{
+	"api-status":         recordSchema(2),
+	"completed-list":     tableSchema([]string{"ID", "CompletedDate", "Project", "Content"}),
+	"context":            tableSchema([]string{"Name", "Filter"}),
+	"context list":       tableSchema([]string{"Name", "Filter", "Current"}),
+	"diff":               tableSchema([]string{"Change", "ID", "Content", "Details"}),
+	"filters":            tableSchema([]string{"ID", "Name", "Query"}),
+	"filters explain":    tableSchema([]string{"Clause", "Tasks"}),
+	"history":            tableSchema([]string{"Time", "Command", "Change", "ID", "Before", "After"}),
+	"labels":             tableSchema([]string{"ID", "Name"}),
+	"labels stats":       tableSchema([]string{"Name", "Open", "Overdue", "Completed"}),
+	"list":               listSchema(),
+	"next":               recordSchema(6),
+	"pick":               recordSchema(6),
+	"plan today":         tableSchema([]string{"Start", "End", "ID", "Content"}),
+	"projects":           tableSchema([]string{"ID", "Name"}),
+	"projects members":   tableSchema([]string{"ID", "Name", "Email", "Role", "Tasks"}),
+	"recurrence preview": tableSchema([]string{"Date"}),
+	"reminders":          tableSchema([]string{"ID", "Time", "Type", "Task", "Content"}),
+	"schedule":           tableSchema([]string{"ID", "Cron", "Command", "Next", "LastRun", "Error"}),
+	"show":               recordSchema(2),
+	"stats flow":         tableSchema([]string{"Week", "Added", "Completed", "Net", "Backlog"}),
+	"suggest-schedule":   tableSchema([]string{"ID", "DueDate", "Content", "Reason"}),
+	// The views without a name, the tasks of one with it.
+	"view":           {AnyOf: []*JSONSchema{tableSchema([]string{"Name", "Filter"}), listSchema()}},
+	"workspace":      tableSchema([]string{"ID", "Name"}),
+	"workspace list": tableSchema([]string{"ID", "Name", "Projects", "Members", "Current"}),
+}
+
+// CommandSchema returns the JSON Schema of the JSON output of the command
+// args start with, its aliases and subcommands resolved like urfave/cli
+// does.
+func CommandSchema(app *cli.App, args []string) (*JSONSchema, error) {
+	names := []string{}
+	commands := app.Commands
+	for _, arg := range args {
+		var found *cli.Command
+		for i := range commands {
+			if commands[i].HasName(arg) {
+				found = &commands[i]
+				break
+			}
+		}
+		if found == nil {
+			break
+		}
+		names = append(names, found.Name)
+		commands = found.Subcommands
+	}
+	if len(names) == 0 {
+		return commandSchema(strings.Join(args, " "))
+	}
+	return commandSchema(strings.Join(names, " "))
+}
+
+// commandSchema returns the JSON Schema of the JSON output of the command
+// with the full name.
+func commandSchema(name string) (*JSONSchema, error) {
+	rows, ok := commandSchemas[name]
+	if !ok {
+		known := []string{}
+		for name := range commandSchemas {
+			known = append(known, name)
+		}
+		sort.Strings(known)
+		return nil, &Error{Code: "invalid_argument", Message: fmt.Sprintf("no JSON output to describe for %q", name), Hint: "use any of " + strings.Join(known, ", ")}
+	}
+	return &JSONSchema{Schema: jsonSchemaDraft, Title: "todoist " + name, Type: "array", Items: rows}, nil
+}
+
+func writeSchema(c *cli.Context, schema *JSONSchema) error {
+	buf, _ := json.MarshalIndent(schema, "", "  ")
+	_, err := c.App.Writer.Write(append(buf, '\n'))
+	return err
+}
+
+// schemaActions has the actions of commands, and of their subcommands,
+// write their schema instead of running with --schema.
+func schemaActions(commands []cli.Command, parents []string) []cli.Command {
+	for i := range commands {
+		path := append(append([]string{}, parents...), commands[i].Name)
+		if action, ok := commands[i].Action.(func(*cli.Context) error); ok {
+			commands[i].Action = func(c *cli.Context) error {
+				if c.GlobalBool("schema") {
+					// Subcommands run in an app of their own, so they are
+					// known by their path.
+					schema, err := commandSchema(strings.Join(path, " "))
+					if err != nil {
+						return err
+					}
+					return writeSchema(c, schema)
+				}
+				return action(c)
+			}
+		}
+		commands[i].Subcommands = schemaActions(commands[i].Subcommands, path)
+	}
+	return commands
+}
+
+func Schema(c *cli.Context) error {
+	if !c.Args().Present() {
+		return ArgumentRequired
+	}
+	schema, err := CommandSchema(c.App, c.Args())
+	if err != nil {
+		return err
+	}
+	return writeSchema(c, schema)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/sachaos/todoist/lib/todoisttest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandSchema(t *testing.T) {
+	app := newApp()
+
+	schema, err := CommandSchema(app, []string{"l", "--filter", "p1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "todoist list", schema.Title, "they should be equal")
+	assert.Equal(t, "array", schema.Type, "they should be equal")
+	assert.Equal(t, "object", schema.Items.Type, "they should be equal")
+	assert.Empty(t, schema.Items.Required)
+	for _, column := range listColumns(nil, testStore(t, `{}`)) {
+		assert.Equal(t, "string", schema.Items.Properties[column.header].Type, "they should be equal")
+	}
+	assert.False(t, *schema.Items.AdditionalProperties)
+
+	schema, err = CommandSchema(app, []string{"labels", "stats"})
+	assert.NoError(t, err)
+	assert.Equal(t, "todoist labels stats", schema.Title, "they should be equal")
+	assert.Equal(t, []string{"Name", "Open", "Overdue", "Completed"}, schema.Items.Required, "they should be equal")
+
+	schema, err = CommandSchema(app, []string{"show", "1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "array", schema.Items.Type, "they should be equal")
+	assert.Equal(t, "string", schema.Items.Items.Type, "they should be equal")
+	assert.Equal(t, 2, schema.Items.MinItems, "they should be equal")
+	assert.Equal(t, 2, schema.Items.MaxItems, "they should be equal")
+
+	_, err = CommandSchema(app, []string{"sync"})
+	assert.Equal(t, "invalid_argument", AsError(err).Code, "they should be equal")
+}
+
+func TestSchemaRunsNothing(t *testing.T) {
+	server := todoisttest.NewServer(t, `{
+		"user": {"id": "1", "inbox_project_id": "1"},
+		"projects": [{"id": "1", "name": "Inbox", "inbox_project": true}]
+	}`)
+	run := runTodoist(t, server)
+
+	for _, args := range [][]string{{"--schema", "projects"}, {"schema", "projects"}} {
+		out, err := run(args...)
+		assert.NoError(t, err)
+		var schema JSONSchema
+		assert.NoError(t, json.Unmarshal([]byte(out), &schema))
+		assert.Equal(t, "todoist projects", schema.Title, "they should be equal")
+	}
+
+	// Had sync run, it would have written the cache.
+	_, err := run("--schema", "sync")
+	assert.Error(t, err)
+	_, err = os.Stat(default_cache_path)
+	assert.True(t, os.IsNotExist(err))
+}
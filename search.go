@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/urfave/cli"
+)
+
+// searchIndexPath returns the path of the search index kept next to the
+// cache at cachePath.
+func searchIndexPath(cachePath string) string {
+	return cachePath + ".index"
+}
+
+func readSearchIndex(cachePath string) *todoist.Index {
+	index := todoist.NewIndex()
+	if err := readJSONFile(searchIndexPath(cachePath), index); err != nil {
+		// A broken index is built again.
+		return todoist.NewIndex()
+	}
+	return index
+}
+
+// updateSearchIndex brings the index of the cache at cachePath in line with
+// store, only indexing the tasks which changed.
+func updateSearchIndex(cachePath string, store *todoist.Store) error {
+	index := readSearchIndex(cachePath)
+	if !index.Update(store) {
+		return nil
+	}
+	buf, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(searchIndexPath(cachePath), buf)
+}
+
+// regexMatches returns the ids of the items of store whose content,
+// description or comments e matches.
+func regexMatches(e RegexExpr, store *todoist.Store) map[string]bool {
+	matches := map[string]bool{}
+	if e.re == nil {
+		return matches
+	}
+	for i := range store.Items {
+		item := &store.Items[i]
+		if e.re.MatchString(item.Content) || e.re.MatchString(item.Description) {
+			matches[item.ID] = true
+		}
+	}
+	for _, note := range store.Notes {
+		if !note.IsDeleted && e.re.MatchString(note.Content) {
+			matches[note.ItemID] = true
+		}
+	}
+	return matches
+}
+
+// resolveSearch looks up the search expressions in e with the index of the
+// cache, so they don't have to be evaluated against every task, and matches
+// regular expressions against the comments of tasks, which Eval doesn't see.
+func resolveSearch(e Expression, store *todoist.Store) Expression {
+	var index *todoist.Index
+	var resolve func(e Expression) Expression
+	resolve = func(e Expression) Expression {
+		switch e := e.(type) {
+		case BoolInfixOpExpr:
+			e.left = resolve(e.left)
+			e.right = resolve(e.right)
+			return e
+		case NotOpExpr:
+			e.expr = resolve(e.expr)
+			return e
+		case SearchExpr:
+			if index == nil {
+				index = readSearchIndex(default_cache_path)
+				// The index may lag behind, or be missing when the cache
+				// was written by an older version.
+				index.Update(store)
+			}
+			e.matches = index.Search(e.query)
+			return e
+		case RegexExpr:
+			e.matches = regexMatches(e, store)
+			return e
+		}
+		return e
+	}
+	return resolve(e)
+}
+
+func Search(c *cli.Context) error {
+	if !c.Args().Present() {
+		return ArgumentRequired
+	}
+	query := strings.Join(c.Args(), " ")
+	if c.Bool("regex") {
+		return ShowView(c, View{Filter: "regex: " + strconv.Quote(query)})
+	}
+	return ShowView(c, View{Filter: "search: " + strconv.Quote(query)})
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli"
+)
+
+const latestReleaseURL = "https://api.github.com/repos/sachaos/todoist/releases/latest"
+
+// digestAssetNames are the files of a release listing the SHA-256 digests of
+// its binaries.
+var digestAssetNames = []string{"checksums.txt", "SHA256SUMS"}
+
+type release struct {
+	TagName string `json:"tag_name"`
+	Assets  []struct {
+		Name string `json:"name"`
+		URL  string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+func (r release) assetURL(name string) string {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			return asset.URL
+		}
+	}
+	return ""
+}
+
+// compareVersions compares dotted version strings like "v0.15.0", returning
+// -1, 0 or 1.
+func compareVersions(a string, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x < y {
+			return -1
+		}
+		if x > y {
+			return 1
+		}
+	}
+	return 0
+}
+
+func fetch(url string) (io.ReadCloser, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+func latestRelease() (release, error) {
+	var r release
+	body, err := fetch(latestReleaseURL)
+	if err != nil {
+		return r, err
+	}
+	defer body.Close()
+	err = json.NewDecoder(body).Decode(&r)
+	return r, err
+}
+
+// releaseDigest looks up the SHA-256 digest of asset in the digests of the
+// release. It only catches a corrupted download: the digests come from the
+// same release as the binary, so they don't prove who built it.
+func releaseDigest(r release, asset string) (string, error) {
+	for _, name := range digestAssetNames {
+		url := r.assetURL(name)
+		if url == "" {
+			continue
+		}
+		body, err := fetch(url)
+		if err != nil {
+			return "", err
+		}
+		defer body.Close()
+
+		scanner := bufio.NewScanner(body)
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == asset {
+				return strings.ToLower(fields[0]), nil
+			}
+		}
+		return "", fmt.Errorf("no digest for %s in %s", asset, name)
+	}
+	return "", errors.New("release lists no digests, refusing to update")
+}
+
+func SelfUpdate(c *cli.Context) error {
+	r, err := latestRelease()
+	if err != nil {
+		return err
+	}
+
+	current := c.App.Version
+	if compareVersions(current, r.TagName) >= 0 {
+		fmt.Printf("todoist %s is up to date\n", current)
+		return nil
+	}
+	if c.Bool("check") {
+		fmt.Printf("todoist %s is available (current: %s)\n", r.TagName, current)
+		return nil
+	}
+
+	asset := fmt.Sprintf("todoist_%s_%s", runtime.GOOS, runtime.GOARCH)
+	url := r.assetURL(asset)
+	if url == "" {
+		return fmt.Errorf("release %s has no binary for %s/%s", r.TagName, runtime.GOOS, runtime.GOARCH)
+	}
+	digest, err := releaseDigest(r, asset)
+	if err != nil {
+		return err
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	executable, err = filepath.EvalSymlinks(executable)
+	if err != nil {
+		return err
+	}
+
+	body, err := fetch(url)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	tmp, err := ioutil.TempFile(filepath.Dir(executable), ".todoist-update")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hash), body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if sum := hex.EncodeToString(hash.Sum(nil)); sum != digest {
+		return fmt.Errorf("corrupted download of %s: expected SHA-256 %s, got %s", asset, digest, sum)
+	}
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		return err
+	}
+
+	// A running executable cannot be overwritten on every platform, but it
+	// can be moved out of the way.
+	old := executable + ".old"
+	os.Remove(old)
+	if err := os.Rename(executable, old); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), executable); err != nil {
+		os.Rename(old, executable)
+		return err
+	}
+	os.Remove(old)
+
+	fmt.Printf("Updated todoist %s -> %s\n", current, r.TagName)
+	return nil
+}
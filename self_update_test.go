@@ -0,0 +1,14 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, 0, compareVersions("0.15.0", "v0.15.0"), "they should be equal")
+	assert.Equal(t, -1, compareVersions("0.15.0", "v0.16.0"), "they should be equal")
+	assert.Equal(t, -1, compareVersions("0.9.0", "v0.10.0"), "they should be equal")
+	assert.Equal(t, 1, compareVersions("1.0", "v0.99.9"), "they should be equal")
+}
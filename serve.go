@@ -0,0 +1,316 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/spf13/viper"
+	"github.com/urfave/cli"
+)
+
+// storeServer serves requests on the store of client, one at a time as the
+// client changes its store in place.
+type storeServer struct {
+	mutex  sync.Mutex
+	client *todoist.Client
+}
+
+func (s *storeServer) serialize(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// listen serves handler on the address given by the host and port flags,
+// syncing the store every interval, until interrupted.
+func (s *storeServer) listen(c *cli.Context, handler http.Handler, what string) error {
+	if interval := c.Duration("interval"); interval <= 0 {
+		return &Error{Code: "invalid_argument", Message: fmt.Sprintf("invalid interval %s", interval), Hint: "give how often to sync like --interval 5m"}
+	}
+	server := &http.Server{
+		Addr:    fmt.Sprintf("%s:%d", c.String("host"), c.Int("port")),
+		Handler: s.serialize(handler),
+	}
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.ListenAndServe()
+	}()
+	fmt.Fprintf(os.Stderr, "Serving %s on http://%s/\n", what, server.Addr)
+
+	interval := time.NewTicker(c.Duration("interval"))
+	defer interval.Stop()
+	for {
+		select {
+		case <-interval.C:
+			// Requests go on while the store is fetched.
+			store, err := s.client.Fetch(GetContext(c))
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "sync failed:", err)
+				continue
+			}
+			s.mutex.Lock()
+			s.client.Store = store
+			s.mutex.Unlock()
+		case err := <-errs:
+			return err
+		case <-GetContext(c).Done():
+			return server.Close()
+		}
+	}
+}
+
+// writeJSON writes v as the JSON response with status.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeJSONError writes err as the JSON response, in the shape of the errors
+// of the JSON output.
+func writeJSONError(w http.ResponseWriter, err error) {
+	e := AsError(err)
+	status := http.StatusBadGateway
+	switch e.Code {
+	case "invalid_filter", "invalid_argument", "argument_required":
+		status = http.StatusBadRequest
+	case "id_not_found", "not_found":
+		status = http.StatusNotFound
+	case "read_only", "cross_origin", "invalid_host":
+		status = http.StatusForbidden
+	case "unauthorized":
+		status = http.StatusUnauthorized
+	case "unsupported_media_type":
+		status = http.StatusUnsupportedMediaType
+	}
+	writeJSON(w, status, map[string]*Error{"error": e})
+}
+
+// apiTask is the body of a request adding a task.
+type apiTask struct {
+	Content     string   `json:"content"`
+	Description string   `json:"description"`
+	ProjectID   string   `json:"project_id"`
+	Priority    int      `json:"priority"`
+	Labels      []string `json:"labels"`
+	DueString   string   `json:"due_string"`
+}
+
+// writeAllowed reports whether r may write, writing the error if not. Writes
+// need token, "" for none allowed, as a bearer token and a JSON body, which
+// a web page can't send to another origin without it agreeing, and requests
+// from pages of other origins are turned down all the same.
+func writeAllowed(w http.ResponseWriter, r *http.Request, token string) bool {
+	if token == "" {
+		writeJSONError(w, todoist.ReadOnly)
+		return false
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		if u, err := url.Parse(origin); err != nil || u.Host != r.Host {
+			writeJSONError(w, &Error{Code: "cross_origin", Message: fmt.Sprintf("writes from %s are not allowed", origin), Hint: "send writes from the same origin or outside of a browser"})
+			return false
+		}
+	}
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		writeJSONError(w, &Error{Code: "unsupported_media_type", Message: "writes must be sent as application/json", Hint: "set Content-Type: application/json"})
+		return false
+	}
+	return authorized(w, r, token)
+}
+
+// authorized reports whether r has token as its bearer token, writing the
+// error if not.
+func authorized(w http.ResponseWriter, r *http.Request, token string) bool {
+	given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+		writeJSONError(w, &Error{Code: "unauthorized", Message: "missing or wrong token", Hint: "send Authorization: Bearer <serve_token of the config>"})
+		return false
+	}
+	return true
+}
+
+// hostAllowed reports whether r is for host, the host listened on, or for
+// the local host, writing the error if not. A page of another site can
+// point its own name at the local host, but can't send its requests for
+// any of those names.
+func hostAllowed(w http.ResponseWriter, r *http.Request, host string) bool {
+	name := r.Host
+	if h, _, err := net.SplitHostPort(name); err == nil {
+		name = h
+	}
+	name = strings.Trim(name, "[]")
+	switch {
+	case name == "localhost", name == "127.0.0.1", name == "::1":
+		return true
+	case host != "" && strings.EqualFold(name, host):
+		return true
+	}
+	writeJSONError(w, &Error{Code: "invalid_host", Message: fmt.Sprintf("requests for %s are not allowed", r.Host), Hint: "send requests to localhost or the --host of serve"})
+	return false
+}
+
+// apiHandler serves the tasks, projects and labels of the store of client
+// as JSON, in the shape of the API, to the requests for host with token, ""
+// for none needed, and with allowWrites adds and closes tasks.
+func apiHandler(client *todoist.Client, host string, token string, allowWrites bool) http.Handler {
+	writeToken := ""
+	if allowWrites {
+		writeToken = token
+	}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/tasks", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			filter, err := ApplyContext(r.URL.Query().Get("filter"))
+			if err != nil {
+				writeJSONError(w, err)
+				return
+			}
+			ex, err := ParseFilter(filter)
+			if err != nil {
+				writeJSONError(w, &Error{Code: "invalid_filter", Message: err.Error(), Hint: "run `todoist filters check` on it"})
+				return
+			}
+			writeJSON(w, http.StatusOK, FilterItems(client.Store, ex))
+		case http.MethodPost:
+			if !writeAllowed(w, r, writeToken) {
+				return
+			}
+			var task apiTask
+			if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
+				writeJSONError(w, &Error{Code: "invalid_argument", Message: fmt.Sprintf("invalid task: %s", err), Hint: "send a JSON object with the content of the task"})
+				return
+			}
+			if strings.TrimSpace(task.Content) == "" {
+				writeJSONError(w, ArgumentRequired)
+				return
+			}
+			item := todoist.Item{
+				BaseItem:    todoist.BaseItem{Content: task.Content, HaveProjectID: todoist.HaveProjectID{ProjectID: task.ProjectID}},
+				Description: task.Description,
+				Priority:    task.Priority,
+				LabelNames:  task.Labels,
+				DateString:  task.DueString,
+			}
+			add := todoist.NewCommand("item_add", item.AddParam())
+			id, err := execCommand(client, r, add)
+			if err != nil {
+				writeJSONError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusCreated, client.Store.FindItem(id))
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		}
+	})
+
+	// /tasks/<id> is a task, and posting to /tasks/<id>/close closes it.
+	mux.HandleFunc("/tasks/", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/tasks/"), "/")
+		item := client.Store.FindItem(parts[0])
+		switch {
+		case len(parts) == 1 && r.Method == http.MethodGet:
+			if item == nil {
+				writeJSONError(w, IdNotFound)
+				return
+			}
+			writeJSON(w, http.StatusOK, item)
+		case len(parts) == 2 && parts[1] == "close" && r.Method == http.MethodPost:
+			if !writeAllowed(w, r, writeToken) {
+				return
+			}
+			if item == nil {
+				writeJSONError(w, IdNotFound)
+				return
+			}
+			if _, err := execCommand(client, r, todoist.NewCommand("item_close", map[string]interface{}{"id": item.ID})); err != nil {
+				writeJSONError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, client.Store.FindItem(item.ID))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	mux.HandleFunc("/projects", func(w http.ResponseWriter, r *http.Request) {
+		projects := todoist.Projects{}
+		for _, project := range client.Store.Projects {
+			if !project.IsDeleted && !project.IsArchived {
+				projects = append(projects, project)
+			}
+		}
+		writeJSON(w, http.StatusOK, projects)
+	})
+
+	mux.HandleFunc("/labels", func(w http.ResponseWriter, r *http.Request) {
+		labels := todoist.Labels{}
+		for _, label := range client.Store.Labels {
+			if !label.IsDeleted {
+				labels = append(labels, label)
+			}
+		}
+		writeJSON(w, http.StatusOK, labels)
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !hostAllowed(w, r, host) {
+			return
+		}
+		if token != "" && !authorized(w, r, token) {
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// execCommand executes command for the request r, writes the cache, and
+// returns the id of the object it added, if any.
+func execCommand(client *todoist.Client, r *http.Request, command todoist.Command) (string, error) {
+	id := ""
+	executed := client.Executed
+	client.Executed = func(commands todoist.Commands, tempIDs map[string]string, before todoist.Store) {
+		id = tempIDs[command.TempID]
+		if executed != nil {
+			executed(commands, tempIDs, before)
+		}
+	}
+	defer func() { client.Executed = executed }()
+
+	if err := client.ExecCommands(r.Context(), todoist.Commands{command}); err != nil {
+		return "", err
+	}
+	if err := WriteCache(default_cache_path, client.Store); err != nil {
+		fmt.Fprintln(os.Stderr, "writing cache failed:", err)
+	}
+	return id, nil
+}
+
+func Serve(c *cli.Context) error {
+	client := GetClient(c)
+
+	server := &storeServer{client: client}
+	what := "the tasks"
+	token := viper.GetString("serve_token")
+	if c.Bool("allow-writes") {
+		if token == "" {
+			return &Error{Code: "invalid_argument", Message: "--allow-writes needs serve_token in the config", Hint: "set serve_token in the config to a secret for the clients which write"}
+		}
+		what += ", with writes allowed,"
+	}
+	return server.listen(c, apiHandler(client, c.String("host"), token, c.Bool("allow-writes")), what)
+}
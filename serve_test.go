@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIHandler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "todoist")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	savedCache, savedContext := default_cache_path, contextPath
+	default_cache_path = filepath.Join(dir, "cache.json")
+	contextPath = filepath.Join(dir, "context.json")
+	defer func() { default_cache_path, contextPath = savedCache, savedContext }()
+
+	data := `{
+		"projects": [{"id": "1", "name": "Home"}, {"id": "2", "name": "Old", "is_archived": true}],
+		"labels": [{"id": "5", "name": "lights"}],
+		"items": [
+			{"id": "10", "project_id": "1", "content": "Water plants", "priority": 4},
+			{"id": "11", "project_id": "1", "content": "Fix the lamp", "priority": 1}
+		]
+	}`
+	sandbox, err := todoist.NewSandbox([]byte(data))
+	assert.NoError(t, err)
+	client := todoist.NewClient(&todoist.Config{})
+	client.Transport = sandbox
+	client.Store = testStore(t, data)
+
+	headers := map[string]string{"Content-Type": "application/json", "Authorization": "Bearer secret"}
+	request := func(handler http.Handler, method string, path string, body string) (int, string) {
+		recorder := httptest.NewRecorder()
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		for key, value := range headers {
+			r.Header.Set(key, value)
+		}
+		handler.ServeHTTP(recorder, r)
+		return recorder.Code, recorder.Body.String()
+	}
+
+	handler := apiHandler(client, "example.com", "", false)
+	code, body := request(handler, http.MethodGet, "/tasks?filter=p1", "")
+	assert.Equal(t, http.StatusOK, code, "they should be equal")
+	var items []todoist.Item
+	assert.NoError(t, json.Unmarshal([]byte(body), &items))
+	assert.Equal(t, 1, len(items), "they should be equal")
+	assert.Equal(t, "Water plants", items[0].Content, "they should be equal")
+
+	code, body = request(handler, http.MethodGet, "/tasks?filter=p1+%26", "")
+	assert.Equal(t, http.StatusBadRequest, code, "they should be equal")
+	assert.Contains(t, body, `"code":"invalid_filter"`)
+
+	code, body = request(handler, http.MethodGet, "/projects", "")
+	assert.Equal(t, http.StatusOK, code, "they should be equal")
+	var projects []todoist.Project
+	assert.NoError(t, json.Unmarshal([]byte(body), &projects))
+	assert.Equal(t, 1, len(projects), "they should be equal")
+
+	// Pages of other sites may resolve their name to the local host.
+	code, body = request(handler, http.MethodGet, "http://evil.example/tasks", "")
+	assert.Equal(t, http.StatusForbidden, code, "they should be equal")
+	assert.Contains(t, body, `"code":"invalid_host"`)
+	code, _ = request(handler, http.MethodGet, "http://localhost:7000/tasks", "")
+	assert.Equal(t, http.StatusOK, code, "they should be equal")
+
+	code, _ = request(handler, http.MethodGet, "/tasks/12", "")
+	assert.Equal(t, http.StatusNotFound, code, "they should be equal")
+	code, _ = request(handler, http.MethodPost, "/tasks/10/close", "")
+	assert.Equal(t, http.StatusForbidden, code, "they should be equal")
+	code, _ = request(handler, http.MethodPost, "/tasks", `{"content": "Buy bulbs"}`)
+	assert.Equal(t, http.StatusForbidden, code, "they should be equal")
+
+	handler = apiHandler(client, "example.com", "secret", false)
+	code, _ = request(handler, http.MethodGet, "/projects", "")
+	assert.Equal(t, http.StatusOK, code, "they should be equal")
+	code, _ = request(handler, http.MethodPost, "/tasks/10/close", "")
+	assert.Equal(t, http.StatusForbidden, code, "they should be equal")
+	headers["Authorization"] = ""
+	code, _ = request(handler, http.MethodGet, "/projects", "")
+	assert.Equal(t, http.StatusUnauthorized, code, "they should be equal")
+	headers["Authorization"] = "Bearer secret"
+
+	handler = apiHandler(client, "example.com", "secret", true)
+	// Pages of other sites can post forms and text, but not JSON with a token.
+	for _, tc := range []struct {
+		header, value string
+		code          int
+	}{
+		{"Authorization", "", http.StatusUnauthorized},
+		{"Authorization", "Bearer wrong", http.StatusUnauthorized},
+		{"Content-Type", "text/plain", http.StatusUnsupportedMediaType},
+		{"Content-Type", "application/x-www-form-urlencoded", http.StatusUnsupportedMediaType},
+		{"Origin", "http://evil.example", http.StatusForbidden},
+	} {
+		saved := headers[tc.header]
+		headers[tc.header] = tc.value
+		code, _ = request(handler, http.MethodPost, "/tasks/10/close", "")
+		assert.Equal(t, tc.code, code, tc.header+": "+tc.value)
+		headers[tc.header] = saved
+	}
+	assert.False(t, client.Store.FindItem("10").Checked)
+	headers["Origin"] = "http://example.com"
+
+	code, body = request(handler, http.MethodPost, "/tasks", `{"content": "Buy bulbs", "project_id": "1", "labels": ["lights"]}`)
+	assert.Equal(t, http.StatusCreated, code, "they should be equal")
+	var item todoist.Item
+	assert.NoError(t, json.Unmarshal([]byte(body), &item))
+	assert.Equal(t, "Buy bulbs", item.Content, "they should be equal")
+	assert.NotNil(t, client.Store.FindItem(item.ID))
+
+	code, _ = request(handler, http.MethodPost, "/tasks", `{"content": " "}`)
+	assert.Equal(t, http.StatusBadRequest, code, "they should be equal")
+
+	code, _ = request(handler, http.MethodPost, "/tasks/10/close", "")
+	assert.Equal(t, http.StatusOK, code, "they should be equal")
+	assert.True(t, client.Store.FindItem("10").Checked)
+	_, err = os.Stat(default_cache_path)
+	assert.NoError(t, err)
+}
@@ -1,7 +1,7 @@
 package main
 
 import (
-	"strconv"
+	"fmt"
 	"strings"
 
 	"github.com/pkg/browser"
@@ -12,18 +12,21 @@ import (
 func Show(c *cli.Context) error {
 	client := GetClient(c)
 
-	item_id, err := strconv.Atoi(c.Args().First())
-	if err != nil {
-		return CommandFailed
+	if !c.Args().Present() {
+		return ArgumentRequired
 	}
 
-	item := client.Store.FindItem(item_id)
+	if !looksLikeID(c.Args().First()) {
+		return InvalidID(c.Args().First())
+	}
+
+	item := client.Store.FindItem(c.Args().First())
 	if item == nil {
 		return IdNotFound
 	}
 
 	colorList := ColorList()
-	var projectIds []int
+	var projectIds []string
 	for _, project := range client.Store.Projects {
 		projectIds = append(projectIds, project.GetID())
 	}
@@ -31,12 +34,32 @@ func Show(c *cli.Context) error {
 
 	records := [][]string{
 		[]string{"ID", IdFormat(item)},
+	}
+	// Subtasks are given the tasks they are under first, for context.
+	if parents := ParentsFormat(item, client.Store); parents != "" {
+		records = append(records, []string{"Parents", parents})
+	}
+	records = append(records,
 		[]string{"Content", ContentFormat(item)},
-		[]string{"Project", ProjectFormat(item.ProjectID, client.Store, projectColorHash, c)},
-		[]string{"Labels", item.LabelsString(client.Store)},
+		[]string{"Project", ProjectPathFormat(item.ProjectID, client.Store, projectColorHash)},
+		[]string{"Labels", LabelsFormat(item, client.Store)},
 		[]string{"Priority", PriorityFormat(item.Priority)},
 		[]string{"DueDate", DueDateFormat(item.DateTime(), item.AllDay)},
 		[]string{"URL", strings.Join(todoist.GetContentURL(item), ",")},
+	)
+	for i, attachment := range client.Store.ItemAttachments(item.ID) {
+		records = append(records, []string{fmt.Sprintf("Attachment %d", i+1), attachment.FileName + " (" + attachment.FileType + ")"})
+	}
+	if ids := linkedTaskIDs(client.Store, item.ID); len(ids) > 0 {
+		linked := []string{}
+		for _, id := range ids {
+			if task := client.Store.FindItem(id); task != nil {
+				linked = append(linked, IdFormat(task)+" "+ContentFormat(task))
+			} else {
+				linked = append(linked, id)
+			}
+		}
+		records = append(records, []string{"Linked", strings.Join(linked, ", ")})
 	}
 	defer writer.Flush()
 
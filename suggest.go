@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/urfave/cli"
+)
+
+// scheduleHorizon is how many days from today suggestions are spread over.
+const scheduleHorizon = 14
+
+// defaultDailyCapacity is how many tasks a day is planned to fit when there
+// is no completion history, which the API only has for premium users.
+const defaultDailyCapacity = 3
+
+// ScheduleSuggestion is a due date proposed for an undated task.
+type ScheduleSuggestion struct {
+	Item   *todoist.Item
+	Date   time.Time
+	Reason string
+}
+
+// dailyCapacity is how many tasks are usually completed on each weekday.
+type dailyCapacity struct {
+	tasks       [7]int
+	fromHistory bool
+}
+
+// completionCapacity derives the tasks completed per weekday from the tasks
+// completed within the last CompletedDays.
+func completionCapacity(completed todoist.CompletedItems) dailyCapacity {
+	counts := [7]int{}
+	total := 0
+	for _, item := range completed {
+		t := item.DateTime()
+		if t.IsZero() {
+			continue
+		}
+		counts[t.Local().Weekday()]++
+		total++
+	}
+
+	capacity := dailyCapacity{}
+	if total == 0 {
+		for day := range capacity.tasks {
+			capacity.tasks[day] = defaultDailyCapacity
+		}
+		return capacity
+	}
+	weeks := todoist.CompletedDays / 7
+	for day, n := range counts {
+		// Round up, so that a weekday with any completions has room.
+		capacity.tasks[day] = (n + weeks - 1) / weeks
+	}
+	capacity.fromHistory = true
+	return capacity
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// SuggestSchedule proposes due dates for the undated tasks of items within
+// scheduleHorizon days from now. Tasks of higher priority are scheduled
+// first and sooner, p1 from today and p4 from three days on, each on the
+// earliest day whose tasks due, overdue ones counting for today, are fewer
+// than the tasks usually completed on its weekday. When every day is full,
+// the least overloaded one is taken.
+func SuggestSchedule(store *todoist.Store, items []*todoist.Item, capacity dailyCapacity, now time.Time) []ScheduleSuggestion {
+	today := startOfDay(now)
+	day := func(offset int) time.Time {
+		return today.AddDate(0, 0, offset)
+	}
+
+	dueOn := map[string]int{}
+	for i := range store.Items {
+		item := &store.Items[i]
+		if item.Checked || item.Due == nil {
+			continue
+		}
+		date := item.DateTime().In(now.Location())
+		if date.Before(today) {
+			date = today
+		}
+		dueOn[date.Format(todoist.RFC3339Date)]++
+	}
+	load := make([]int, scheduleHorizon)
+	for offset := range load {
+		load[offset] = dueOn[day(offset).Format(todoist.RFC3339Date)]
+	}
+
+	undated := []*todoist.Item{}
+	for _, item := range items {
+		if item.Due == nil {
+			undated = append(undated, item)
+		}
+	}
+	sort.SliceStable(undated, func(i, j int) bool {
+		if undated[i].Priority != undated[j].Priority {
+			return undated[i].Priority > undated[j].Priority
+		}
+		return naturalCompare(undated[i].ID, undated[j].ID) < 0
+	})
+
+	suggestions := []ScheduleSuggestion{}
+	for _, item := range undated {
+		priority := item.Priority
+		if priority < 1 {
+			priority = 1
+		}
+		start := 4 - priority
+
+		chosen := -1
+		for offset := start; offset < scheduleHorizon; offset++ {
+			if load[offset] < capacity.tasks[day(offset).Weekday()] {
+				chosen = offset
+				break
+			}
+		}
+		if chosen < 0 {
+			chosen = start
+			for offset := start; offset < scheduleHorizon; offset++ {
+				over := load[offset] - capacity.tasks[day(offset).Weekday()]
+				if over < load[chosen]-capacity.tasks[day(chosen).Weekday()] {
+					chosen = offset
+				}
+			}
+		}
+
+		date := day(chosen)
+		reason := fmt.Sprintf("p%d, %d other task(s) due", priorityMapping[item.Priority], load[chosen])
+		if capacity.fromHistory {
+			reason += fmt.Sprintf(", about %d usually done on %ss", capacity.tasks[date.Weekday()], date.Weekday())
+		} else {
+			reason += fmt.Sprintf(", %d a day planned", capacity.tasks[date.Weekday()])
+		}
+		suggestions = append(suggestions, ScheduleSuggestion{Item: item, Date: date, Reason: reason})
+		load[chosen]++
+	}
+	return suggestions
+}
+
+// confirmSchedule shows suggestions on out and asks whether to schedule
+// them, unless yes.
+func confirmSchedule(out io.Writer, suggestions []ScheduleSuggestion, yes bool) error {
+	if !yes {
+		for _, suggestion := range suggestions {
+			fmt.Fprintf(out, "  %s %s %s (%s)\n", suggestion.Item.ID, suggestion.Date.Format(todoist.RFC3339Date), todoist.GetContentTitle(suggestion.Item), suggestion.Reason)
+		}
+	}
+	return confirm(fmt.Sprintf("Schedule %d task(s) like this?", len(suggestions)), yes)
+}
+
+func SuggestScheduleCommand(c *cli.Context) error {
+	client := GetClient(c)
+	ctx := GetContext(c)
+
+	filter := c.String("filter")
+	if filter == "" {
+		filter = "no date"
+	}
+	filter, err := ApplyContext(filter)
+	if err != nil {
+		return err
+	}
+	items := FilterItems(client.Store, Filter(filter))
+
+	var completed todoist.Completed
+	if err := client.CompletedAll(ctx, &completed); err != nil {
+		client.Log("no completion history, planning %d tasks a day: %s", defaultDailyCapacity, err)
+	}
+
+	suggestions := SuggestSchedule(client.Store, items, completionCapacity(completed.Items), time.Now())
+	if len(suggestions) == 0 {
+		fmt.Fprintln(os.Stderr, "There is no undated task to schedule.")
+		return nil
+	}
+
+	// The table goes through the pager, which shows nothing until the
+	// command ends, so the preview is asked about on stderr.
+	if err := confirmSchedule(os.Stderr, suggestions, c.Bool("yes")); err != nil {
+		return err
+	}
+
+	client.Buffer()
+	for _, suggestion := range suggestions {
+		item := todoist.Item{BaseItem: todoist.BaseItem{HaveID: todoist.HaveID{ID: suggestion.Item.ID}}}
+		item.DateString = suggestion.Date.Format(todoist.RFC3339Date)
+		if err := client.UpdateItem(ctx, item); err != nil {
+			return err
+		}
+	}
+	if err := client.Flush(ctx); err != nil {
+		return err
+	}
+	if err := Sync(c); err != nil {
+		return err
+	}
+
+	defer writer.Flush()
+	writer.WriteHeader([]string{"ID", "DueDate", "Content", "Reason"})
+	for _, suggestion := range suggestions {
+		writer.Write([]string{
+			IdFormat(suggestion.Item),
+			DueDateFormat(suggestion.Date, true),
+			ContentFormat(suggestion.Item),
+			suggestion.Reason,
+		})
+	}
+	return nil
+}
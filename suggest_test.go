@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompletionCapacity(t *testing.T) {
+	capacity := completionCapacity(todoist.CompletedItems{})
+	assert.Equal(t, defaultDailyCapacity, capacity.tasks[time.Monday], "they should be equal")
+	assert.False(t, capacity.fromHistory, "they should be equal")
+
+	completed := todoist.CompletedItems{}
+	for i := 0; i < 24; i++ {
+		completed = append(completed, todoist.CompletedItem{CompletedAt: "2020-01-06T12:00:00Z"})
+	}
+	capacity = completionCapacity(completed)
+	assert.Equal(t, 2, capacity.tasks[time.Monday], "they should be equal")
+	assert.Equal(t, 0, capacity.tasks[time.Tuesday], "they should be equal")
+	assert.True(t, capacity.fromHistory, "they should be equal")
+}
+
+func TestSuggestSchedule(t *testing.T) {
+	store := testStore(t, `{"items": [
+		{"id": "1", "content": "due", "priority": 1, "due": {"date": "2020-01-06"}},
+		{"id": "2", "content": "overdue", "priority": 1, "due": {"date": "2020-01-01"}},
+		{"id": "3", "content": "p4", "priority": 1},
+		{"id": "4", "content": "p1", "priority": 4},
+		{"id": "5", "content": "p1 too", "priority": 4}
+	]}`)
+	capacity := dailyCapacity{}
+	for day := range capacity.tasks {
+		capacity.tasks[day] = 2
+	}
+	// A Monday, with two tasks due counting the overdue one.
+	now := time.Date(2020, 1, 6, 9, 0, 0, 0, time.Local)
+
+	suggestions := SuggestSchedule(store, FilterItems(store, Filter("")), capacity, now)
+	dates := map[string]string{}
+	for _, suggestion := range suggestions {
+		dates[suggestion.Item.ID] = suggestion.Date.Format(todoist.RFC3339Date)
+	}
+	assert.Equal(t, map[string]string{
+		"3": "2020-01-09",
+		"4": "2020-01-07",
+		"5": "2020-01-07",
+	}, dates, "they should be equal")
+}
+
+func TestConfirmSchedule(t *testing.T) {
+	if isTerminal(os.Stdin) {
+		t.Skip("the question would be asked on the terminal")
+	}
+	item := &todoist.Item{BaseItem: todoist.BaseItem{HaveID: todoist.HaveID{ID: "4"}, Content: "Write the report"}}
+	suggestions := []ScheduleSuggestion{{Item: item, Date: time.Date(2020, 1, 7, 0, 0, 0, 0, time.Local), Reason: "p1, 1 task due"}}
+
+	// The preview is shown before the question, which fails here.
+	var out bytes.Buffer
+	assert.Equal(t, ConfirmationRequired, confirmSchedule(&out, suggestions, false), "they should be equal")
+	assert.Equal(t, "  4 2020-01-07 Write the report (p1, 1 task due)\n", out.String(), "they should be equal")
+
+	out.Reset()
+	assert.NoError(t, confirmSchedule(&out, suggestions, true))
+	assert.Equal(t, "", out.String(), "they should be equal")
+}
@@ -1,15 +1,38 @@
 package main
 
 import (
-	"context"
-
 	"github.com/urfave/cli"
 )
 
 func Sync(c *cli.Context) error {
+	if c.Command.Name == "sync" && c.Bool("daemon") {
+		return SyncDaemon(c)
+	}
+
 	client := GetClient(c)
 
-	err := client.Sync(context.Background())
+	if c.Command.Name != "sync" {
+		// The client applied the commands to its store already, so the
+		// cache is up to date even when the sync below fails.
+		if err := WriteCache(default_cache_path, client.Store); err != nil {
+			return err
+		}
+
+		// Leave the network round trip to the daemon when one is running.
+		if daemonRunning(default_cache_path) {
+			return invalidateCache(default_cache_path)
+		}
+
+		// Only what changed since the last sync is fetched, which brings
+		// what can't be known locally, like the next date of a recurring
+		// task.
+		if err := client.SyncChanges(GetContext(c)); err != nil {
+			return SyncFailed(err)
+		}
+		return WriteCache(default_cache_path, client.Store)
+	}
+
+	err := client.Sync(GetContext(c))
 	if err != nil {
 		return err
 	}
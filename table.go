@@ -0,0 +1,95 @@
+package main
+
+import (
+	"io"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+func init() {
+	RegisterWriter("table", func(w io.Writer, opts WriterOptions) Writer { return NewTableWriter(w, opts.Zebra) })
+}
+
+// TableWriter draws records as a table with box-drawing borders. Records are
+// buffered until Flush because column widths depend on every row.
+type TableWriter struct {
+	w       io.Writer
+	zebra   bool
+	header  []string
+	records [][]string
+}
+
+func NewTableWriter(w io.Writer, zebra bool) *TableWriter {
+	return &TableWriter{w: w, zebra: zebra}
+}
+
+func (w *TableWriter) WriteHeader(record []string) error {
+	w.header = record
+	return nil
+}
+
+func (w *TableWriter) Write(record []string) error {
+	w.records = append(w.records, record)
+	return nil
+}
+
+func (w *TableWriter) Flush() {
+	widths := []int{}
+	for _, record := range append([][]string{w.header}, w.records...) {
+		for i, field := range record {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if width := visibleWidth(field); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+	if len(widths) == 0 {
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString(tableBorder(widths, "┌", "┬", "┐"))
+	if w.header != nil {
+		b.WriteString(tableRow(w.header, widths, color.New(color.Bold)))
+		b.WriteString(tableBorder(widths, "├", "┼", "┤"))
+	}
+	for i, record := range w.records {
+		var style *color.Color
+		if w.zebra && i%2 == 1 {
+			style = color.New(theme.Stripe...)
+		}
+		b.WriteString(tableRow(record, widths, style))
+	}
+	b.WriteString(tableBorder(widths, "└", "┴", "┘"))
+
+	io.WriteString(w.w, b.String())
+	w.header = nil
+	w.records = nil
+}
+
+func tableBorder(widths []int, left string, middle string, right string) string {
+	cells := make([]string, len(widths))
+	for i, width := range widths {
+		cells[i] = strings.Repeat("─", width+2)
+	}
+	return left + strings.Join(cells, middle) + right + "\n"
+}
+
+func tableRow(record []string, widths []int, style *color.Color) string {
+	cells := make([]string, len(widths))
+	for i, width := range widths {
+		var field string
+		if i < len(record) {
+			field = record[i]
+		}
+		cell := " " + field + strings.Repeat(" ", width-visibleWidth(field)) + " "
+		if style != nil {
+			cell = style.Sprint(cell)
+		}
+		cells[i] = cell
+	}
+	return "│" + strings.Join(cells, "│") + "│\n"
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableWriter(t *testing.T) {
+	var b bytes.Buffer
+	w := NewTableWriter(&b, false)
+	w.WriteHeader([]string{"ID", "Content"})
+	w.Write([]string{"1", "buy milk"})
+	w.Write([]string{"22", "x"})
+	w.Flush()
+
+	expected := `┌────┬──────────┐
+│ ID │ Content  │
+├────┼──────────┤
+│ 1  │ buy milk │
+│ 22 │ x        │
+└────┴──────────┘
+`
+	assert.Equal(t, expected, b.String(), "they should be equal")
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/mattn/go-isatty"
+)
+
+// stdout is the stdout todoist was started with, which is still the
+// terminal while os.Stdout goes through the pager.
+var stdout = os.Stdout
+
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// terminalWidth returns the width of the terminal attached to stdout, or 0
+// when it cannot be determined.
+func terminalWidth() int {
+	if width, _ := terminalSize(stdout); width > 0 {
+		return width
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return 0
+}
+
+// terminalHeight returns the height of the terminal attached to stdout, or 0
+// when it cannot be determined.
+func terminalHeight() int {
+	if _, height := terminalSize(stdout); height > 0 {
+		return height
+	}
+	if lines, err := strconv.Atoi(os.Getenv("LINES")); err == nil && lines > 0 {
+		return lines
+	}
+	return 0
+}
@@ -0,0 +1,12 @@
+//go:build windows
+// +build windows
+
+package main
+
+import (
+	"os"
+)
+
+func terminalSize(f *os.File) (width int, height int) {
+	return 0, 0
+}
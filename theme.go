@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// Theme is a color palette for output, which has to be readable on the
+// background of the terminal.
+type Theme struct {
+	Projects []color.Attribute
+	ID       []color.Attribute
+	Unknown  []color.Attribute
+	Favorite []color.Attribute
+	Stripe   []color.Attribute
+	// Priorities are indexed by API priority, 4 being p1.
+	Priorities [5][]color.Attribute
+	Overdue    []color.Attribute
+	DueToday   []color.Attribute
+	DueSoon    []color.Attribute
+	DueLater   []color.Attribute
+}
+
+var darkTheme = &Theme{
+	Projects: []color.Attribute{color.FgHiRed, color.FgHiGreen, color.FgHiYellow, color.FgHiBlue, color.FgHiMagenta, color.FgHiCyan},
+	ID:       []color.Attribute{color.FgBlue},
+	Unknown:  []color.Attribute{color.FgCyan},
+	Favorite: []color.Attribute{color.FgYellow},
+	Stripe:   []color.Attribute{color.BgHiBlack},
+	Priorities: [5][]color.Attribute{
+		1: {color.FgBlue, color.BgBlack},
+		2: {color.FgHiYellow, color.BgBlack},
+		3: {color.FgHiRed, color.BgBlack},
+		4: {color.FgWhite, color.BgRed},
+	},
+	Overdue:  []color.Attribute{color.FgWhite, color.BgRed},
+	DueToday: []color.Attribute{color.FgHiYellow, color.BgBlack},
+	DueSoon:  []color.Attribute{color.FgHiCyan, color.BgBlack},
+	DueLater: []color.Attribute{color.FgHiBlue, color.BgBlack},
+}
+
+// lightTheme avoids the bright and yellow foregrounds which fade into a light
+// background, and the black backgrounds which look like blots on it, so
+// today's tasks are marked with a yellow background instead.
+var lightTheme = &Theme{
+	Projects: []color.Attribute{color.FgRed, color.FgGreen, color.FgBlue, color.FgMagenta, color.FgCyan, color.FgHiBlack},
+	ID:       []color.Attribute{color.FgBlue},
+	Unknown:  []color.Attribute{color.FgHiBlack},
+	Favorite: []color.Attribute{color.FgRed},
+	Stripe:   []color.Attribute{color.BgWhite},
+	Priorities: [5][]color.Attribute{
+		1: {color.FgBlue},
+		2: {color.FgMagenta},
+		3: {color.FgRed},
+		4: {color.FgWhite, color.BgRed},
+	},
+	Overdue:  []color.Attribute{color.FgWhite, color.BgRed},
+	DueToday: []color.Attribute{color.FgBlack, color.BgYellow},
+	DueSoon:  []color.Attribute{color.FgMagenta},
+	DueLater: []color.Attribute{color.FgBlue},
+}
+
+// theme is the palette of this invocation.
+var theme = darkTheme
+
+// backgroundFromEnv guesses the terminal background from COLORFGBG, which
+// terminals like rxvt, Konsole and iTerm2 set to "foreground;background" in
+// the 16 ANSI colors.
+func backgroundFromEnv(colorfgbg string) (string, bool) {
+	fields := strings.Split(colorfgbg, ";")
+	background, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil || background < 0 || background > 15 {
+		return "", false
+	}
+	// White and the bright colors but bright black are light.
+	if background == 7 || background > 8 {
+		return "light", true
+	}
+	return "dark", true
+}
+
+// ThemeFor picks the theme. An explicit flag wins over the config file, and
+// auto detects the background of the terminal, falling back to dark.
+func ThemeFor(flag string, config string) (*Theme, error) {
+	mode := flag
+	if mode == "" {
+		mode = config
+	}
+	if mode == "auto" || mode == "" {
+		mode, _ = backgroundFromEnv(os.Getenv("COLORFGBG"))
+	}
+
+	switch mode {
+	case "dark", "":
+		return darkTheme, nil
+	case "light":
+		return lightTheme, nil
+	default:
+		return nil, &Error{Code: "invalid_argument", Message: fmt.Sprintf("invalid theme %q", mode), Hint: "use auto, dark or light"}
+	}
+}
+
+// WithDueColors returns a copy of t with the colors of due dates given in
+// colors, by overdue, today, tomorrow or later, as color names of the app.
+func (t *Theme) WithDueColors(colors map[string]string) (*Theme, error) {
+	th := *t
+	for key, name := range colors {
+		attributes := AppColor(name)
+		if attributes == nil {
+			return nil, &Error{Code: "invalid_argument", Message: fmt.Sprintf("invalid due color %q for %s", name, key), Hint: "use any of " + strings.Join(AppColorNames(), ", ")}
+		}
+		switch key {
+		case "overdue":
+			th.Overdue = attributes
+		case "today":
+			th.DueToday = attributes
+		case "tomorrow":
+			th.DueSoon = attributes
+		case "later":
+			th.DueLater = attributes
+		default:
+			return nil, &Error{Code: "invalid_argument", Message: fmt.Sprintf("invalid due color key %q", key), Hint: "use overdue, today, tomorrow or later"}
+		}
+	}
+	return &th, nil
+}
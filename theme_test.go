@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackgroundFromEnv(t *testing.T) {
+	for value, expected := range map[string]string{
+		"15;0":         "dark",
+		"0;15":         "light",
+		"default;7":    "light",
+		"7;8":          "dark",
+		"12;default;0": "dark",
+	} {
+		background, ok := backgroundFromEnv(value)
+		assert.True(t, ok, value)
+		assert.Equal(t, expected, background, "they should be equal")
+	}
+	_, ok := backgroundFromEnv("")
+	assert.False(t, ok)
+}
+
+func TestThemeFor(t *testing.T) {
+	os.Setenv("COLORFGBG", "0;15")
+	defer os.Unsetenv("COLORFGBG")
+
+	th, err := ThemeFor("", "")
+	assert.NoError(t, err)
+	assert.Equal(t, lightTheme, th, "they should be equal")
+
+	th, err = ThemeFor("", "dark")
+	assert.NoError(t, err)
+	assert.Equal(t, darkTheme, th, "they should be equal")
+
+	th, err = ThemeFor("light", "dark")
+	assert.NoError(t, err)
+	assert.Equal(t, lightTheme, th, "they should be equal")
+
+	_, err = ThemeFor("solarized", "")
+	assert.Equal(t, "invalid_argument", AsError(err).Code, "they should be equal")
+}
+
+func TestWithDueColors(t *testing.T) {
+	th, err := darkTheme.WithDueColors(map[string]string{"today": "orange", "later": "grey"})
+	assert.NoError(t, err)
+	assert.Equal(t, AppColor("orange"), th.DueToday, "they should be equal")
+	assert.Equal(t, AppColor("grey"), th.DueLater, "they should be equal")
+	assert.Equal(t, darkTheme.Overdue, th.Overdue, "they should be equal")
+	assert.NotEqual(t, AppColor("orange"), darkTheme.DueToday)
+
+	_, err = darkTheme.WithDueColors(map[string]string{"today": "neon"})
+	assert.Equal(t, "invalid_argument", AsError(err).Code, "they should be equal")
+	_, err = darkTheme.WithDueColors(map[string]string{"yesterday": "red"})
+	assert.Equal(t, "invalid_argument", AsError(err).Code, "they should be equal")
+}
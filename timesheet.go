@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/urfave/cli"
+)
+
+// parseDay returns the start of the day s names: today, yesterday, a
+// weekday of the current week, weeks starting on Monday, or 2006-01-02.
+func parseDay(s string, now time.Time) (time.Time, error) {
+	today := startOfDay(now)
+	switch strings.ToLower(s) {
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		if strings.ToLower(s) != name && strings.ToLower(s) != name[:3] {
+			continue
+		}
+		// Days of the week counted from Monday, Sunday being the last.
+		offset := (int(day)+6)%7 - (int(today.Weekday())+6)%7
+		return today.AddDate(0, 0, offset), nil
+	}
+	t, err := time.ParseInLocation(todoist.RFC3339Date, s, now.Location())
+	if err != nil {
+		return time.Time{}, &Error{Code: "invalid_argument", Message: fmt.Sprintf("invalid day %q", s), Hint: "use today, yesterday, a weekday like monday, or a date like 2006-01-02"}
+	}
+	return t, nil
+}
+
+// TimesheetDay is the tasks completed on a day, in the order they were.
+type TimesheetDay struct {
+	Date  time.Time
+	Tasks todoist.CompletedItems
+	// Minutes is the sum of the tracked durations of the tasks.
+	Minutes int
+}
+
+// Timesheet groups the tasks of completed completed in [since, until) per
+// day.
+func Timesheet(completed todoist.CompletedItems, since time.Time, until time.Time) []*TimesheetDay {
+	items := todoist.CompletedItems{}
+	for _, item := range completed {
+		if t := item.DateTime(); !t.Before(since) && t.Before(until) {
+			items = append(items, item)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DateTime().Before(items[j].DateTime())
+	})
+
+	days := []*TimesheetDay{}
+	for _, item := range items {
+		date := startOfDay(item.DateTime().In(since.Location()))
+		if len(days) == 0 || !days[len(days)-1].Date.Equal(date) {
+			days = append(days, &TimesheetDay{Date: date})
+		}
+		day := days[len(days)-1]
+		day.Tasks = append(day.Tasks, item)
+		if minutes, ok := item.Duration.Minutes(); ok {
+			day.Minutes += minutes
+		}
+	}
+	return days
+}
+
+// writeTimesheet writes days as CSV, a row per task followed by one with
+// the total of each day.
+func writeTimesheet(out io.Writer, days []*TimesheetDay, store *todoist.Store) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{"Date", "Completed", "Project", "Task", "Minutes"})
+	for _, day := range days {
+		date := day.Date.Format(todoist.RFC3339Date)
+		for _, item := range day.Tasks {
+			project := ""
+			if p := store.FindProject(item.ProjectID); p != nil {
+				project = p.Name
+			}
+			minutes := ""
+			if m, ok := item.Duration.Minutes(); ok {
+				minutes = fmt.Sprintf("%d", m)
+			}
+			w.Write([]string{date, item.DateTime().Local().Format("15:04"), project, todoist.GetContentTitle(item), minutes})
+		}
+		w.Write([]string{date, "", "", fmt.Sprintf("Total (%d tasks)", len(day.Tasks)), fmt.Sprintf("%d", day.Minutes)})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func ExportTimesheet(c *cli.Context) error {
+	client := GetClient(c)
+	now := time.Now()
+
+	since, err := parseDay(c.String("since"), now)
+	if err != nil {
+		return err
+	}
+	until, err := parseDay(c.String("until"), now)
+	if err != nil {
+		return err
+	}
+	// Until is inclusive.
+	until = until.AddDate(0, 0, 1)
+	if !since.Before(until) {
+		return &Error{Code: "invalid_argument", Message: "--since is after --until"}
+	}
+	if until.Sub(since) > todoist.CompletedDays*24*time.Hour {
+		return &Error{Code: "invalid_argument", Message: fmt.Sprintf("the timesheet spans more than %d days", todoist.CompletedDays), Hint: "export it in several parts"}
+	}
+
+	var completed todoist.Completed
+	if err := client.CompletedBetween(GetContext(c), since, until, &completed); err != nil {
+		return err
+	}
+
+	items := completed.Items
+	if name := c.String("project"); name != "" {
+		projectID := client.Store.Projects.GetIDByName(name)
+		if projectID == "" {
+			return ProjectNotFound(name)
+		}
+		items = todoist.CompletedItems{}
+		for _, item := range completed.Items {
+			if item.ProjectID == projectID {
+				items = append(items, item)
+			}
+		}
+	}
+
+	var out io.Writer = os.Stdout
+	if path := c.String("out"); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	return writeTimesheet(out, Timesheet(items, since, until), client.Store)
+}
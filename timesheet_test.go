@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDay(t *testing.T) {
+	// A Wednesday.
+	now := time.Date(2020, 1, 8, 15, 0, 0, 0, time.Local)
+	for s, expected := range map[string]time.Time{
+		"today":      time.Date(2020, 1, 8, 0, 0, 0, 0, time.Local),
+		"yesterday":  time.Date(2020, 1, 7, 0, 0, 0, 0, time.Local),
+		"monday":     time.Date(2020, 1, 6, 0, 0, 0, 0, time.Local),
+		"Fri":        time.Date(2020, 1, 10, 0, 0, 0, 0, time.Local),
+		"sunday":     time.Date(2020, 1, 12, 0, 0, 0, 0, time.Local),
+		"2019-12-31": time.Date(2019, 12, 31, 0, 0, 0, 0, time.Local),
+	} {
+		day, err := parseDay(s, now)
+		assert.NoError(t, err, s)
+		assert.Equal(t, expected, day, "they should be equal")
+	}
+
+	_, err := parseDay("someday", now)
+	assert.Error(t, err, "they should be equal")
+}
+
+func TestTimesheet(t *testing.T) {
+	store := testStore(t, `{"projects": [{"id": "1", "name": "Client"}]}`)
+	completed := todoist.CompletedItems{}
+	for _, task := range []struct {
+		content     string
+		completedAt time.Time
+		duration    *todoist.Duration
+	}{
+		{"late", time.Date(2020, 1, 7, 17, 0, 0, 0, time.Local), &todoist.Duration{Amount: 30, Unit: "minute"}},
+		{"early", time.Date(2020, 1, 7, 9, 0, 0, 0, time.Local), &todoist.Duration{Amount: 90, Unit: "minute"}},
+		{"next day", time.Date(2020, 1, 8, 9, 0, 0, 0, time.Local), &todoist.Duration{Amount: 2, Unit: "day"}},
+		{"too late", time.Date(2020, 1, 9, 9, 0, 0, 0, time.Local), nil},
+	} {
+		item := todoist.CompletedItem{CompletedAt: task.completedAt.Format(time.RFC3339), Duration: task.duration}
+		item.Content = task.content
+		item.ProjectID = "1"
+		completed = append(completed, item)
+	}
+
+	days := Timesheet(completed, time.Date(2020, 1, 6, 0, 0, 0, 0, time.Local), time.Date(2020, 1, 9, 0, 0, 0, 0, time.Local))
+	out := &bytes.Buffer{}
+	assert.NoError(t, writeTimesheet(out, days, store), "they should be equal")
+	assert.Equal(t, `Date,Completed,Project,Task,Minutes
+2020-01-07,09:00,Client,early,90
+2020-01-07,17:00,Client,late,30
+2020-01-07,,,Total (2 tasks),120
+2020-01-08,09:00,Client,next day,
+2020-01-08,,,Total (1 tasks),0
+`, out.String(), "they should be equal")
+}
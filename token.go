@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
+
+// APIToken returns the API token from $TODOIST_TOKEN, the output of the
+// configured token_command or the config file, in this order.
+func APIToken() (string, error) {
+	if token := os.Getenv("TODOIST_TOKEN"); token != "" {
+		return token, nil
+	}
+
+	command := viper.GetString("token_command")
+	if command == "" {
+		return viper.GetString("token"), nil
+	}
+
+	var stdout bytes.Buffer
+	cmd := shellCommand(command)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", &Error{
+			Code:    "token_command_failed",
+			Message: fmt.Sprintf("token_command %q failed: %s", command, err),
+			Hint:    "check the token_command in the config",
+		}
+	}
+	token := strings.TrimSpace(stdout.String())
+	if token == "" {
+		return "", &Error{
+			Code:    "token_command_failed",
+			Message: fmt.Sprintf("token_command %q printed no token", command),
+			Hint:    "check the token_command in the config",
+		}
+	}
+	return token, nil
+}
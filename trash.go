@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/spf13/viper"
+	"github.com/urfave/cli"
+)
+
+// trashPath returns the path of the trash of the account of the cache at
+// cachePath, kept next to it as ids differ between accounts.
+func trashPath(cachePath string) string {
+	return cachePath + ".trash.json"
+}
+
+// TrashEntry remembers where a trashed task came from.
+type TrashEntry struct {
+	ProjectID string    `json:"project_id"`
+	TrashedAt time.Time `json:"trashed_at"`
+}
+
+type Trash map[string]TrashEntry
+
+func trashProjectName() string {
+	return viper.GetString("trash_project")
+}
+
+// ensureTrashProject returns the id of the trash project, adding it first if
+// needed. A new project is referred to by the temp id of its command, so it
+// has to be sent in the same batch as the commands using it.
+func ensureTrashProject(ctx context.Context, client *todoist.Client) (string, error) {
+	name := trashProjectName()
+	if id := client.Store.Projects.GetIDByName(name); id != "" {
+		return id, nil
+	}
+	command := todoist.NewCommand("project_add", todoist.Project{Name: name}.AddParam())
+	if err := client.ExecCommands(ctx, todoist.Commands{command}); err != nil {
+		return "", err
+	}
+	return command.TempID, nil
+}
+
+func moveItems(ctx context.Context, client *todoist.Client, ids []string, projectID func(id string) string) error {
+	var commands todoist.Commands
+	for _, id := range ids {
+		item := todoist.Item{BaseItem: todoist.BaseItem{HaveID: todoist.HaveID{ID: id}}}
+		commands = append(commands, todoist.NewCommand("item_move", item.MoveParam(projectID(id))))
+	}
+	return client.ExecCommands(ctx, commands)
+}
+
+// purgeTrash deletes tasks which have been in the trash for longer than the
+// configured number of days.
+func purgeTrash(ctx context.Context, client *todoist.Client, trash Trash) error {
+	days := viper.GetInt("trash_purge_days")
+	if days <= 0 {
+		return nil
+	}
+	trashID := client.Store.Projects.GetIDByName(trashProjectName())
+	deadline := time.Now().AddDate(0, 0, -days)
+
+	ids := []string{}
+	for id, entry := range trash {
+		if entry.TrashedAt.After(deadline) {
+			continue
+		}
+		if item := client.Store.FindItem(id); item != nil && item.ProjectID == trashID {
+			ids = append(ids, id)
+		}
+		delete(trash, id)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return client.DeleteItem(ctx, ids)
+}
+
+func TrashItems(c *cli.Context, ids []string) error {
+	client := GetClient(c)
+	ctx := GetContext(c)
+
+	trash := Trash{}
+	if err := readJSONFile(trashPath(default_cache_path), &trash); err != nil {
+		return err
+	}
+
+	client.Buffer()
+	trashID, err := ensureTrashProject(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	if err := moveItems(ctx, client, ids, func(string) string { return trashID }); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		entry := TrashEntry{TrashedAt: time.Now()}
+		if item := client.Store.FindItem(id); item != nil {
+			entry.ProjectID = item.ProjectID
+		}
+		trash[id] = entry
+	}
+
+	if err := purgeTrash(ctx, client, trash); err != nil {
+		return err
+	}
+	if err := client.Flush(ctx); err != nil {
+		return err
+	}
+	if err := writeJSONFile(trashPath(default_cache_path), trash); err != nil {
+		return err
+	}
+	return Sync(c)
+}
+
+func Restore(c *cli.Context) error {
+	client := GetClient(c)
+	ctx := GetContext(c)
+
+	trash := Trash{}
+	if err := readJSONFile(trashPath(default_cache_path), &trash); err != nil {
+		return err
+	}
+
+	ids := []string{}
+	for _, arg := range c.Args() {
+		id, err := ResolveItemID(client, arg)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return ArgumentRequired
+	}
+
+	client.Buffer()
+	err := moveItems(ctx, client, ids, func(id string) string {
+		if entry, ok := trash[id]; ok && client.Store.FindProject(entry.ProjectID) != nil {
+			return entry.ProjectID
+		}
+		return client.Store.User.InboxProjectID
+	})
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		delete(trash, id)
+	}
+
+	if err := purgeTrash(ctx, client, trash); err != nil {
+		return err
+	}
+	if err := client.Flush(ctx); err != nil {
+		return err
+	}
+	if err := writeJSONFile(trashPath(default_cache_path), trash); err != nil {
+		return err
+	}
+	return Sync(c)
+}
@@ -0,0 +1,476 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/urfave/cli"
+)
+
+// TrelloBoard is a board in the JSON Trello exports boards as, and which
+// its importers read.
+type TrelloBoard struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Desc       string            `json:"desc"`
+	Closed     bool              `json:"closed"`
+	Labels     []TrelloLabel     `json:"labels"`
+	Lists      []TrelloList      `json:"lists"`
+	Cards      []TrelloCard      `json:"cards"`
+	Checklists []TrelloChecklist `json:"checklists"`
+	Actions    []TrelloAction    `json:"actions"`
+}
+
+type TrelloLabel struct {
+	ID      string `json:"id"`
+	IDBoard string `json:"idBoard"`
+	Name    string `json:"name"`
+	Color   string `json:"color,omitempty"`
+}
+
+type TrelloList struct {
+	ID      string  `json:"id"`
+	IDBoard string  `json:"idBoard"`
+	Name    string  `json:"name"`
+	Closed  bool    `json:"closed"`
+	Pos     float64 `json:"pos"`
+}
+
+type TrelloCard struct {
+	ID           string        `json:"id"`
+	IDBoard      string        `json:"idBoard"`
+	IDList       string        `json:"idList"`
+	Name         string        `json:"name"`
+	Desc         string        `json:"desc"`
+	Closed       bool          `json:"closed"`
+	Pos          float64       `json:"pos"`
+	Due          *string       `json:"due"`
+	DueComplete  bool          `json:"dueComplete"`
+	IDLabels     []string      `json:"idLabels"`
+	Labels       []TrelloLabel `json:"labels"`
+	IDChecklists []string      `json:"idChecklists"`
+}
+
+type TrelloChecklist struct {
+	ID         string            `json:"id"`
+	IDBoard    string            `json:"idBoard"`
+	IDCard     string            `json:"idCard"`
+	Name       string            `json:"name"`
+	Pos        float64           `json:"pos"`
+	CheckItems []TrelloCheckItem `json:"checkItems"`
+}
+
+type TrelloCheckItem struct {
+	ID          string  `json:"id"`
+	IDChecklist string  `json:"idChecklist"`
+	Name        string  `json:"name"`
+	State       string  `json:"state"`
+	Pos         float64 `json:"pos"`
+}
+
+// TrelloAction is an event of a board. Only comments, of type commentCard,
+// are exported.
+type TrelloAction struct {
+	ID            string           `json:"id"`
+	Type          string           `json:"type"`
+	Date          string           `json:"date,omitempty"`
+	Data          TrelloActionData `json:"data"`
+	MemberCreator *TrelloMember    `json:"memberCreator,omitempty"`
+}
+
+type TrelloActionData struct {
+	Text  string     `json:"text"`
+	Card  *TrelloRef `json:"card,omitempty"`
+	List  *TrelloRef `json:"list,omitempty"`
+	Board *TrelloRef `json:"board,omitempty"`
+}
+
+type TrelloRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type TrelloMember struct {
+	ID       string `json:"id"`
+	FullName string `json:"fullName"`
+}
+
+// trelloColors maps the app colors to the closest of the few label colors
+// of Trello. The greys have none.
+var trelloColors = map[string]string{
+	"berry_red":   "red",
+	"red":         "red",
+	"orange":      "orange",
+	"yellow":      "yellow",
+	"olive_green": "lime",
+	"lime_green":  "lime",
+	"green":       "green",
+	"mint_green":  "green",
+	"teal":        "sky",
+	"sky_blue":    "sky",
+	"light_blue":  "sky",
+	"blue":        "blue",
+	"grape":       "purple",
+	"violet":      "purple",
+	"lavender":    "purple",
+	"magenta":     "pink",
+	"salmon":      "pink",
+	"charcoal":    "black",
+}
+
+// trelloPos spaces out positions the way Trello does.
+func trelloPos(i int) float64 {
+	return float64(16384 * (i + 1))
+}
+
+// trelloID makes a Trello id, 24 hexadecimal digits, out of the kind and id
+// of what is exported, so that exporting again gives the same ids.
+func trelloID(kind string, id string) string {
+	sum := sha1.Sum([]byte(kind + ":" + id))
+	return hex.EncodeToString(sum[:])[:24]
+}
+
+// trelloTime formats t the way Trello's JSON does.
+func trelloTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+// trelloBoard maps a project to a board: its sections to lists, after a list
+// for the tasks without a section, its tasks to cards, their subtasks to a
+// checklist, and their labels and comments to labels and comments.
+func trelloBoard(store *todoist.Store, project *todoist.Project) TrelloBoard {
+	board := TrelloBoard{
+		ID:         trelloID("project", project.ID),
+		Name:       project.Name,
+		Closed:     project.IsArchived,
+		Labels:     []TrelloLabel{},
+		Lists:      []TrelloList{},
+		Cards:      []TrelloCard{},
+		Checklists: []TrelloChecklist{},
+		Actions:    []TrelloAction{},
+	}
+	boardRef := &TrelloRef{ID: board.ID, Name: board.Name}
+
+	items := []*todoist.Item{}
+	children := map[string][]*todoist.Item{}
+	for i := range store.Items {
+		item := &store.Items[i]
+		if item.ProjectID != project.ID || item.IsDeleted {
+			continue
+		}
+		if item.ParentID != nil {
+			if parent := store.FindItem(*item.ParentID); parent != nil && parent.ProjectID == project.ID {
+				children[parent.ID] = append(children[parent.ID], item)
+				continue
+			}
+		}
+		items = append(items, item)
+	}
+	byOrder := func(items []*todoist.Item) {
+		sort.SliceStable(items, func(i, j int) bool { return items[i].ChildOrder < items[j].ChildOrder })
+	}
+	byOrder(items)
+
+	lists := map[string]*TrelloRef{}
+	addList := func(key string, name string, closed bool) {
+		list := TrelloList{ID: trelloID("section", key), IDBoard: board.ID, Name: name, Closed: closed, Pos: trelloPos(len(board.Lists))}
+		board.Lists = append(board.Lists, list)
+		lists[key] = &TrelloRef{ID: list.ID, Name: list.Name}
+	}
+	for _, item := range items {
+		if item.SectionID == nil || *item.SectionID == "" {
+			addList(project.ID, "No section", false)
+			break
+		}
+	}
+	sections := store.ProjectSections(project.ID)
+	sort.Sort(sections)
+	for _, section := range sections {
+		addList(section.ID, section.Name, section.IsArchived)
+	}
+
+	labels := map[string]TrelloLabel{}
+	label := func(name string) TrelloLabel {
+		if found, ok := labels[name]; ok {
+			return found
+		}
+		found := TrelloLabel{ID: trelloID("label", name), IDBoard: board.ID, Name: name}
+		if id := store.Labels.GetIDByName(name); id != "" {
+			found.Color = trelloColors[store.FindLabel(id).Color]
+		}
+		labels[name] = found
+		board.Labels = append(board.Labels, found)
+		return found
+	}
+
+	for i, item := range items {
+		list := lists[project.ID]
+		if item.SectionID != nil && lists[*item.SectionID] != nil {
+			list = lists[*item.SectionID]
+		}
+		if list == nil {
+			// The section of the task is not in the cache.
+			addList(project.ID, "No section", false)
+			list = lists[project.ID]
+		}
+
+		card := TrelloCard{
+			ID:           trelloID("item", item.ID),
+			IDBoard:      board.ID,
+			IDList:       list.ID,
+			Name:         item.Content,
+			Desc:         item.Description,
+			Closed:       item.Checked,
+			Pos:          trelloPos(i),
+			DueComplete:  item.Checked,
+			IDLabels:     []string{},
+			Labels:       []TrelloLabel{},
+			IDChecklists: []string{},
+		}
+		if item.Due != nil {
+			// Trello only has times, all day tasks are due at noon UTC, which
+			// is the same day in most time zones.
+			due := item.Due.Date + "T12:00:00.000Z"
+			if strings.Contains(item.Due.Date, "T") {
+				due = trelloTime(item.DateTime())
+			}
+			card.Due = &due
+		}
+		for _, name := range item.LabelNames {
+			found := label(name)
+			card.IDLabels = append(card.IDLabels, found.ID)
+			card.Labels = append(card.Labels, found)
+		}
+
+		// Trello has no subtasks, the whole subtree goes into one checklist.
+		checkItems := []TrelloCheckItem{}
+		checklistID := trelloID("checklist", item.ID)
+		var addSubtasks func(parentID string)
+		addSubtasks = func(parentID string) {
+			subtasks := children[parentID]
+			byOrder(subtasks)
+			for _, subtask := range subtasks {
+				state := "incomplete"
+				if subtask.Checked {
+					state = "complete"
+				}
+				checkItems = append(checkItems, TrelloCheckItem{ID: trelloID("item", subtask.ID), IDChecklist: checklistID, Name: subtask.Content, State: state, Pos: trelloPos(len(checkItems))})
+				addSubtasks(subtask.ID)
+			}
+		}
+		addSubtasks(item.ID)
+		if len(checkItems) > 0 {
+			board.Checklists = append(board.Checklists, TrelloChecklist{ID: checklistID, IDBoard: board.ID, IDCard: card.ID, Name: "Subtasks", Pos: trelloPos(0), CheckItems: checkItems})
+			card.IDChecklists = append(card.IDChecklists, checklistID)
+		}
+
+		for _, note := range store.ItemNotes(item.ID) {
+			action := TrelloAction{
+				ID:   trelloID("note", note.ID),
+				Type: "commentCard",
+				Data: TrelloActionData{Text: note.Content, Card: &TrelloRef{ID: card.ID, Name: card.Name}, List: list, Board: boardRef},
+			}
+			if t, err := time.Parse(time.RFC3339, note.PostedAt); err == nil {
+				action.Date = trelloTime(t)
+			}
+			if collaborator := store.FindCollaborator(note.PostedUID); collaborator != nil {
+				action.MemberCreator = &TrelloMember{ID: trelloID("user", collaborator.ID), FullName: collaborator.FullName}
+			}
+			board.Actions = append(board.Actions, action)
+		}
+
+		board.Cards = append(board.Cards, card)
+	}
+	return board
+}
+
+func ExportTrello(c *cli.Context) error {
+	client := GetClient(c)
+
+	name := c.String("project")
+	if name == "" {
+		return &Error{Code: "argument_required", Message: "missing --project", Hint: "give the name of the project with --project"}
+	}
+	project := client.Store.FindProject(client.Store.Projects.GetIDByName(name))
+	if project == nil {
+		return ProjectNotFound(name)
+	}
+
+	var out io.Writer = os.Stdout
+	if path := c.String("out"); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(trelloBoard(client.Store, project))
+}
+
+// trelloDue turns the due time of a card into a due date. Noon UTC, as
+// written by trelloBoard, is an all day date.
+func trelloDue(due string) *todoist.Due {
+	t, err := time.Parse(time.RFC3339, due)
+	if err != nil {
+		return nil
+	}
+	if t.UTC().Format("15:04:05.000") == "12:00:00.000" {
+		return &todoist.Due{Date: t.UTC().Format(todoist.RFC3339Date)}
+	}
+	return &todoist.Due{Date: t.Local().Format(todoist.RFC3339DateTime)}
+}
+
+// trelloCommands creates a project called name out of board: its open lists
+// become sections, except a "No section" list, its open cards tasks with
+// their description, due date, labels and comments, and the items of their
+// checklists subtasks. Done cards and items are added and closed.
+func trelloCommands(board TrelloBoard, name string) (todoist.Commands, int) {
+	project := todoist.NewCommand("project_add", todoist.Project{Name: name}.AddParam())
+	commands := todoist.Commands{project}
+	tasks := 0
+
+	lists := append([]TrelloList{}, board.Lists...)
+	sort.SliceStable(lists, func(i, j int) bool { return lists[i].Pos < lists[j].Pos })
+	sections := map[string]interface{}{}
+	order := map[string]int{}
+	for i, list := range lists {
+		if list.Closed {
+			continue
+		}
+		order[list.ID] = i
+		if list.Name == "No section" {
+			sections[list.ID] = nil
+			continue
+		}
+		command := todoist.NewCommand("section_add", map[string]interface{}{"name": list.Name, "project_id": project.TempID})
+		sections[list.ID] = command.TempID
+		commands = append(commands, command)
+	}
+
+	labelNames := map[string]string{}
+	for _, label := range board.Labels {
+		labelNames[label.ID] = label.Name
+	}
+	checklists := map[string][]TrelloChecklist{}
+	for _, checklist := range board.Checklists {
+		checklists[checklist.IDCard] = append(checklists[checklist.IDCard], checklist)
+	}
+	comments := map[string][]TrelloAction{}
+	for _, action := range board.Actions {
+		if action.Type == "commentCard" && action.Data.Card != nil {
+			comments[action.Data.Card.ID] = append(comments[action.Data.Card.ID], action)
+		}
+	}
+
+	cards := []TrelloCard{}
+	for _, card := range board.Cards {
+		if _, ok := order[card.IDList]; ok && !card.Closed {
+			cards = append(cards, card)
+		}
+	}
+	sort.SliceStable(cards, func(i, j int) bool {
+		if order[cards[i].IDList] != order[cards[j].IDList] {
+			return order[cards[i].IDList] < order[cards[j].IDList]
+		}
+		return cards[i].Pos < cards[j].Pos
+	})
+
+	for _, card := range cards {
+		labels := []string{}
+		for _, id := range card.IDLabels {
+			// Labels without a name only have a color in Trello.
+			if name := strings.ReplaceAll(labelNames[id], " ", "_"); name != "" {
+				labels = append(labels, name)
+			}
+		}
+		param := todoist.Item{BaseItem: todoist.BaseItem{Content: card.Name}, Description: card.Desc, LabelNames: labels}.AddParam().(map[string]interface{})
+		param["project_id"] = project.TempID
+		if section := sections[card.IDList]; section != nil {
+			param["section_id"] = section
+		}
+		if card.Due != nil {
+			if due := trelloDue(*card.Due); due != nil {
+				param["due"] = due
+			}
+		}
+		add := todoist.NewCommand("item_add", param)
+		commands = append(commands, add)
+		tasks++
+
+		cardComments := comments[card.ID]
+		// Trello lists actions newest first.
+		sort.SliceStable(cardComments, func(i, j int) bool { return cardComments[i].Date < cardComments[j].Date })
+		for _, comment := range cardComments {
+			note := todoist.Note{Content: comment.Data.Text}.AddParam().(map[string]interface{})
+			note["item_id"] = add.TempID
+			commands = append(commands, todoist.NewCommand("note_add", note))
+		}
+
+		cardChecklists := checklists[card.ID]
+		sort.SliceStable(cardChecklists, func(i, j int) bool { return cardChecklists[i].Pos < cardChecklists[j].Pos })
+		for _, checklist := range cardChecklists {
+			items := append([]TrelloCheckItem{}, checklist.CheckItems...)
+			sort.SliceStable(items, func(i, j int) bool { return items[i].Pos < items[j].Pos })
+			for _, checkItem := range items {
+				subtask := todoist.NewCommand("item_add", map[string]interface{}{"content": checkItem.Name, "project_id": project.TempID, "parent_id": add.TempID})
+				commands = append(commands, subtask)
+				tasks++
+				if checkItem.State == "complete" {
+					commands = append(commands, todoist.NewCommand("item_close", map[string]interface{}{"id": subtask.TempID}))
+				}
+			}
+		}
+
+		if card.DueComplete {
+			commands = append(commands, todoist.NewCommand("item_close", map[string]interface{}{"id": add.TempID}))
+		}
+	}
+	return commands, tasks
+}
+
+func ImportTrello(c *cli.Context) error {
+	client := GetClient(c)
+
+	if !c.Args().Present() {
+		return ArgumentRequired
+	}
+	buf, err := ioutil.ReadFile(c.Args().First())
+	if err != nil {
+		return err
+	}
+	var board TrelloBoard
+	if err := json.Unmarshal(buf, &board); err != nil {
+		return &Error{Code: "invalid_argument", Message: fmt.Sprintf("%s is not a Trello board: %s", c.Args().First(), err), Hint: "export the board as JSON from its menu in Trello"}
+	}
+
+	name := c.String("project")
+	if name == "" {
+		name = board.Name
+	}
+	if name == "" {
+		return &Error{Code: "argument_required", Message: "the board has no name", Hint: "name the project with --project"}
+	}
+	if client.Store.Projects.GetIDByName(name) != "" {
+		return &Error{Code: "invalid_argument", Message: fmt.Sprintf("project %q already exists", name), Hint: "name the new project with --project"}
+	}
+
+	commands, tasks := trelloCommands(board, name)
+	if err := client.ExecCommands(GetContext(c), commands); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Imported %d task(s) into #%s\n", tasks, name)
+	return Sync(c)
+}
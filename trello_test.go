@@ -0,0 +1,120 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrelloBoard(t *testing.T) {
+	store := testStore(t, `{
+		"projects": [{"id": "1", "name": "Launch"}],
+		"sections": [{"id": "31", "project_id": "1", "name": "Doing", "section_order": 1}],
+		"labels": [{"id": "11", "name": "office", "color": "berry_red"}],
+		"collaborators": [{"id": "7", "full_name": "Alex Kim"}],
+		"items": [
+			{"id": "10", "project_id": "1", "content": "second", "child_order": 2, "section_id": "31", "labels": ["office", "shared"], "due": {"date": "2020-01-02"}},
+			{"id": "11", "project_id": "1", "content": "first", "child_order": 1, "description": "details"},
+			{"id": "12", "project_id": "1", "content": "subtask", "parent_id": "10", "child_order": 1, "checked": true},
+			{"id": "13", "project_id": "1", "content": "subsubtask", "parent_id": "12", "child_order": 1},
+			{"id": "14", "project_id": "2", "content": "other project"}
+		],
+		"notes": [{"id": "20", "item_id": "10", "content": "a comment", "posted_uid": "7", "posted_at": "2020-01-01T10:00:00.000000Z"}]
+	}`)
+
+	board := trelloBoard(store, store.FindProject("1"))
+	assert.Equal(t, "Launch", board.Name, "they should be equal")
+	assert.Equal(t, 2, len(board.Lists), "they should be equal")
+	assert.Equal(t, "No section", board.Lists[0].Name, "they should be equal")
+	assert.Equal(t, "Doing", board.Lists[1].Name, "they should be equal")
+
+	assert.Equal(t, 2, len(board.Cards), "they should be equal")
+	first, second := board.Cards[0], board.Cards[1]
+	assert.Equal(t, "first", first.Name, "they should be equal")
+	assert.Equal(t, "details", first.Desc, "they should be equal")
+	assert.Equal(t, board.Lists[0].ID, first.IDList, "they should be equal")
+	assert.Nil(t, first.Due)
+	assert.Equal(t, board.Lists[1].ID, second.IDList, "they should be equal")
+	assert.Equal(t, "2020-01-02T12:00:00.000Z", *second.Due, "they should be equal")
+
+	assert.Equal(t, 2, len(board.Labels), "they should be equal")
+	assert.Equal(t, "red", board.Labels[0].Color, "they should be equal")
+	assert.Equal(t, "", board.Labels[1].Color, "they should be equal")
+	assert.Equal(t, []string{board.Labels[0].ID, board.Labels[1].ID}, second.IDLabels, "they should be equal")
+
+	assert.Equal(t, 1, len(board.Checklists), "they should be equal")
+	assert.Equal(t, []string{board.Checklists[0].ID}, second.IDChecklists, "they should be equal")
+	items := board.Checklists[0].CheckItems
+	assert.Equal(t, 2, len(items), "they should be equal")
+	assert.Equal(t, "complete", items[0].State, "they should be equal")
+	assert.Equal(t, "subsubtask", items[1].Name, "they should be equal")
+
+	assert.Equal(t, 1, len(board.Actions), "they should be equal")
+	assert.Equal(t, "commentCard", board.Actions[0].Type, "they should be equal")
+	assert.Equal(t, "2020-01-01T10:00:00.000Z", board.Actions[0].Date, "they should be equal")
+	assert.Equal(t, second.ID, board.Actions[0].Data.Card.ID, "they should be equal")
+	assert.Equal(t, "Alex Kim", board.Actions[0].MemberCreator.FullName, "they should be equal")
+
+	// Exporting again gives the same ids.
+	assert.Equal(t, board.Cards[0].ID, trelloBoard(store, store.FindProject("1")).Cards[0].ID, "they should be equal")
+}
+
+func TestTrelloCommands(t *testing.T) {
+	due := "2020-01-02T12:00:00.000Z"
+	timed := "2020-01-02T06:30:00.000Z"
+	board := TrelloBoard{
+		Name:   "Launch",
+		Labels: []TrelloLabel{{ID: "l1", Name: "needs review"}, {ID: "l2", Color: "red"}},
+		Lists: []TrelloList{
+			{ID: "b", Name: "Doing", Pos: 2},
+			{ID: "a", Name: "No section", Pos: 1},
+			{ID: "c", Name: "Archived", Pos: 3, Closed: true},
+		},
+		Cards: []TrelloCard{
+			{ID: "1", IDList: "b", Name: "second", Pos: 1, Due: &due, IDLabels: []string{"l1", "l2"}, DueComplete: true},
+			{ID: "2", IDList: "a", Name: "first", Desc: "details", Pos: 2, Due: &timed},
+			{ID: "3", IDList: "c", Name: "in an archived list"},
+			{ID: "4", IDList: "a", Name: "archived", Closed: true},
+		},
+		Checklists: []TrelloChecklist{
+			{ID: "k", IDCard: "1", CheckItems: []TrelloCheckItem{{Name: "later", Pos: 2}, {Name: "done", Pos: 1, State: "complete"}}},
+		},
+		Actions: []TrelloAction{
+			{Type: "commentCard", Date: "2020-01-02T00:00:00.000Z", Data: TrelloActionData{Text: "newer", Card: &TrelloRef{ID: "1"}}},
+			{Type: "commentCard", Date: "2020-01-01T00:00:00.000Z", Data: TrelloActionData{Text: "older", Card: &TrelloRef{ID: "1"}}},
+			{Type: "updateCard", Data: TrelloActionData{Card: &TrelloRef{ID: "1"}}},
+		},
+	}
+
+	commands, tasks := trelloCommands(board, "Imported")
+	assert.Equal(t, 4, tasks, "they should be equal")
+
+	types := []string{}
+	for _, command := range commands {
+		types = append(types, command.Type)
+	}
+	assert.Equal(t, []string{
+		"project_add", "section_add",
+		"item_add",
+		"item_add", "note_add", "note_add", "item_add", "item_close", "item_add", "item_close",
+	}, types, "they should be equal")
+
+	project := commands[0]
+	first := commands[2].Args.(map[string]interface{})
+	assert.Equal(t, "first", first["content"], "they should be equal")
+	assert.Equal(t, "details", first["description"], "they should be equal")
+	assert.Equal(t, project.TempID, first["project_id"], "they should be equal")
+	assert.Nil(t, first["section_id"])
+	assert.Equal(t, time.Date(2020, 1, 2, 6, 30, 0, 0, time.UTC).Local().Format(todoist.RFC3339DateTime), first["due"].(*todoist.Due).Date, "they should be equal")
+
+	second := commands[3].Args.(map[string]interface{})
+	assert.Equal(t, commands[1].TempID, second["section_id"], "they should be equal")
+	assert.Equal(t, "2020-01-02", second["due"].(*todoist.Due).Date, "they should be equal")
+	assert.Equal(t, []string{"needs_review"}, second["labels"], "they should be equal")
+	assert.Equal(t, "older", commands[4].Args.(map[string]interface{})["content"], "they should be equal")
+	assert.Equal(t, "done", commands[6].Args.(map[string]interface{})["content"], "they should be equal")
+	assert.Equal(t, commands[3].TempID, commands[6].Args.(map[string]interface{})["parent_id"], "they should be equal")
+	assert.Equal(t, commands[3].TempID, commands[9].Args.(map[string]interface{})["id"], "they should be equal")
+}
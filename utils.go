@@ -1,33 +1,204 @@
 package main
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
-	"text/tabwriter"
 )
 
 type Writer interface {
+	WriteHeader([]string) error
 	Write([]string) error
 	Flush()
 }
 
+type WriterOptions struct {
+	// Header reports whether the user asked for a header row.
+	Header bool
+	// Zebra reports whether alternate rows should be shaded.
+	Zebra bool
+}
+
+// WriterFactory creates a Writer for an output format.
+type WriterFactory func(w io.Writer, opts WriterOptions) Writer
+
+var writerRegistry = map[string]WriterFactory{}
+
+// RegisterWriter makes an output format available to the --output flag.
+func RegisterWriter(name string, factory WriterFactory) {
+	writerRegistry[name] = factory
+}
+
+func WriterNames() []string {
+	names := []string{}
+	for name := range writerRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func NewWriter(name string, w io.Writer, opts WriterOptions) (Writer, error) {
+	factory, ok := writerRegistry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown output format %q (expected one of %s)", name, strings.Join(WriterNames(), ", "))
+	}
+	return factory(w, opts), nil
+}
+
+func init() {
+	RegisterWriter("tsv", func(w io.Writer, opts WriterOptions) Writer { return NewTSVWriter(w, opts.Header) })
+	RegisterWriter("csv", func(w io.Writer, opts WriterOptions) Writer { return NewCSVWriter(w, opts.Header) })
+	RegisterWriter("json", func(w io.Writer, opts WriterOptions) Writer { return NewJSONWriter(w) })
+}
+
+// TSVWriter aligns columns with spaces, like text/tabwriter, but measures
+// cells without their color escape sequences, so that colored and plain
+// cells line up.
 type TSVWriter struct {
-	w *tabwriter.Writer
+	w       io.Writer
+	header  bool
+	records [][]string
 }
 
-func NewTSVWriter(w io.Writer) *TSVWriter {
+func NewTSVWriter(w io.Writer, header bool) *TSVWriter {
 	return &TSVWriter{
-		w: tabwriter.NewWriter(w, 0, 4, 1, ' ', 0),
+		w:      w,
+		header: header,
 	}
 }
 
 func (w *TSVWriter) Flush() {
-	w.w.Flush()
+	widths := []int{}
+	for _, record := range w.records {
+		// The last cell of a line is not padded, so it has no width.
+		for i, field := range record[:len(record)-1] {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if width := visibleWidth(field); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+
+	var b strings.Builder
+	for _, record := range w.records {
+		for i, field := range record {
+			b.WriteString(field)
+			if i < len(record)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-visibleWidth(field)+1))
+			}
+		}
+		b.WriteString("\n")
+	}
+	io.WriteString(w.w, b.String())
+	w.records = nil
+}
+
+func (w *TSVWriter) WriteHeader(record []string) error {
+	if !w.header {
+		return nil
+	}
+	return w.Write(record)
 }
 
 func (w *TSVWriter) Write(record []string) error {
-	string := strings.Join(record[:], "\t")
-	fmt.Fprintln(w.w, string)
+	if len(record) == 0 {
+		record = []string{""}
+	}
+	w.records = append(w.records, record)
 	return nil
 }
+
+type CSVWriter struct {
+	*csv.Writer
+	header bool
+}
+
+func NewCSVWriter(w io.Writer, header bool) *CSVWriter {
+	return &CSVWriter{
+		Writer: csv.NewWriter(w),
+		header: header,
+	}
+}
+
+func (w *CSVWriter) WriteHeader(record []string) error {
+	if !w.header {
+		return nil
+	}
+	return w.Write(record)
+}
+
+// JSONWriter buffers records and writes them as a JSON array on Flush. When a
+// header is given every record becomes an object keyed by the header.
+type JSONWriter struct {
+	w       io.Writer
+	header  []string
+	records [][]string
+}
+
+func NewJSONWriter(w io.Writer) *JSONWriter {
+	return &JSONWriter{w: w}
+}
+
+func (w *JSONWriter) WriteHeader(record []string) error {
+	w.header = record
+	return nil
+}
+
+func (w *JSONWriter) Write(record []string) error {
+	fields := make([]string, len(record))
+	for i, field := range record {
+		fields[i] = ansiRegex.ReplaceAllString(field, "")
+	}
+	w.records = append(w.records, fields)
+	return nil
+}
+
+func (w *JSONWriter) Flush() {
+	var b strings.Builder
+	b.WriteString("[")
+	for i, record := range w.records {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n  ")
+		b.Write(w.marshalRecord(record))
+	}
+	if len(w.records) > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString("]\n")
+	io.WriteString(w.w, b.String())
+	w.records = nil
+}
+
+// marshalRecord keeps the fields in header order, which a map would not.
+func (w *JSONWriter) marshalRecord(record []string) []byte {
+	if w.header == nil {
+		buf, _ := json.Marshal(record)
+		return buf
+	}
+
+	var b strings.Builder
+	b.WriteString("{")
+	for i, key := range w.header {
+		if i >= len(record) {
+			break
+		}
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		k, _ := json.Marshal(key)
+		v, _ := json.Marshal(record[i])
+		b.Write(k)
+		b.WriteString(": ")
+		b.Write(v)
+	}
+	b.WriteString("}")
+	return []byte(b.String())
+}
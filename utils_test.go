@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONWriter(t *testing.T) {
+	var b bytes.Buffer
+	w := NewJSONWriter(&b)
+	w.WriteHeader([]string{"ID", "Content"})
+	w.Write([]string{"\x1b[34m1\x1b[0m", "buy milk"})
+	w.Write([]string{"2", "say \"hi\""})
+	w.Flush()
+
+	expected := `[
+  {"ID": "1", "Content": "buy milk"},
+  {"ID": "2", "Content": "say \"hi\""}
+]
+`
+	assert.Equal(t, expected, b.String(), "they should be equal")
+}
+
+func TestNewWriterUnknownFormat(t *testing.T) {
+	_, err := NewWriter("xml", &bytes.Buffer{}, WriterOptions{})
+	assert.Error(t, err)
+}
+
+func TestTSVWriter(t *testing.T) {
+	var b bytes.Buffer
+	w := NewTSVWriter(&b, true)
+	w.WriteHeader([]string{"ID", "Labels", "Content"})
+	w.Write([]string{"1", "\x1b[38;5;63m@office\x1b[0m", "first"})
+	w.Write([]string{"22", "", "second"})
+	w.Flush()
+	assert.Equal(t, "ID Labels  Content\n1  \x1b[38;5;63m@office\x1b[0m first\n22         second\n", b.String(), "they should be equal")
+}
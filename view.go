@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/urfave/cli"
+)
+
+// Views returns the views defined in the "views" section of the config.
+func Views() (map[string]View, error) {
+	views := map[string]View{}
+	if err := viper.UnmarshalKey("views", &views); err != nil {
+		return nil, err
+	}
+	return views, nil
+}
+
+func ShowNamedView(c *cli.Context) error {
+	views, err := Views()
+	if err != nil {
+		return err
+	}
+
+	names := []string{}
+	for name := range views {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if !c.Args().Present() {
+		defer writer.Flush()
+		writer.WriteHeader([]string{"Name", "Filter"})
+		for _, name := range names {
+			writer.Write([]string{name, views[name].Filter})
+		}
+		return nil
+	}
+
+	view, ok := views[c.Args().First()]
+	if !ok {
+		return &Error{Code: "view_not_found", Message: fmt.Sprintf("view %q not found", c.Args().First()), Hint: "define it in the \"views\" section of the config, known views: " + strings.Join(names, ", ")}
+	}
+	return ShowView(c, view)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// maxPageSize is how much of a page is read looking for its title.
+const maxPageSize = 1 << 20
+
+var (
+	pageTitleRegex = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	pageMetaRegex  = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	pageAttrRegex  = regexp.MustCompile(`(?is)([\w:-]+)\s*=\s*("[^"]*"|'[^']*'|[^\s"'>]+)`)
+)
+
+// pageAttrs returns the attributes of the tag, with lowercase names.
+func pageAttrs(tag string) map[string]string {
+	attrs := map[string]string{}
+	for _, match := range pageAttrRegex.FindAllStringSubmatch(tag, -1) {
+		attrs[strings.ToLower(match[1])] = html.UnescapeString(strings.Trim(match[2], `"'`))
+	}
+	return attrs
+}
+
+// pageTitle returns the title of the page, the og:title of sharing if given
+// as it leaves out the name of the site, or "" if it has none. charset is the
+// one of the response, else the one the page gives is used.
+func pageTitle(page []byte, charset string) string {
+	if charset == "" {
+		for _, tag := range pageMetaRegex.FindAll(page, -1) {
+			attrs := pageAttrs(string(tag))
+			if attrs["charset"] != "" {
+				charset = attrs["charset"]
+			} else if strings.EqualFold(attrs["http-equiv"], "content-type") {
+				if _, params, err := mime.ParseMediaType(attrs["content"]); err == nil {
+					charset = params["charset"]
+				}
+			}
+		}
+	}
+	if decoded, err := decodeCharset(page, charset); err == nil {
+		page = decoded
+	}
+
+	title := ""
+	for _, tag := range pageMetaRegex.FindAll(page, -1) {
+		attrs := pageAttrs(string(tag))
+		if attrs["property"] == "og:title" || attrs["name"] == "og:title" {
+			title = attrs["content"]
+			break
+		}
+	}
+	if title == "" {
+		if match := pageTitleRegex.FindSubmatch(page); match != nil {
+			title = html.UnescapeString(string(match[1]))
+		}
+	}
+	return strings.Join(strings.Fields(title), " ")
+}
+
+// fetchPageTitle returns the title of the page at rawurl, or "" if it has
+// none.
+func fetchPageTitle(ctx context.Context, rawurl string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawurl, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	req.Header.Set("User-Agent", "todoist-cli")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s answered %s", req.URL.Host, resp.Status)
+	}
+	mediaType, params, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "" && mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return "", nil
+	}
+	page, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return "", err
+	}
+	return pageTitle(page, params["charset"]), nil
+}
+
+// urlLabel is the label of tasks added with --url, url_label in the config,
+// none for "".
+func urlLabel() string {
+	if viper.IsSet("url_label") {
+		return strings.TrimPrefix(viper.GetString("url_label"), "@")
+	}
+	return "read-later"
+}
+
+// parseWebURL returns rawurl if it is the URL of a web page.
+func parseWebURL(rawurl string) (string, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", &Error{Code: "invalid_argument", Message: fmt.Sprintf("invalid URL %q", rawurl), Hint: "give the URL of a web page, starting with http:// or https://"}
+	}
+	return u.String(), nil
+}
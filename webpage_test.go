@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sachaos/todoist/lib/todoisttest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPageTitle(t *testing.T) {
+	assert.Equal(t, "Fish & Chips", pageTitle([]byte("<html><head><TITLE>\n  Fish &amp;\n Chips </TITLE></head></html>"), ""), "they should be equal")
+	assert.Equal(t, "Article", pageTitle([]byte(`<title>Article | Site</title><meta property="og:title" content="Article">`), ""), "they should be equal")
+	assert.Equal(t, "Café", pageTitle([]byte("<meta charset=iso-8859-1><title>Caf\xe9</title>"), ""), "they should be equal")
+	assert.Equal(t, "Café", pageTitle([]byte("<title>Caf&eacute;</title>"), "iso-8859-1"), "they should be equal")
+	assert.Equal(t, "", pageTitle([]byte("<p>no title</p>"), ""), "they should be equal")
+}
+
+func TestFetchPageTitle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/article":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte("<title>How to grow tomatoes</title>"))
+		case "/image.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("<title>not a page</title>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	title, err := fetchPageTitle(ctx, server.URL+"/article")
+	assert.NoError(t, err)
+	assert.Equal(t, "How to grow tomatoes", title, "they should be equal")
+	title, err = fetchPageTitle(ctx, server.URL+"/image.png")
+	assert.NoError(t, err)
+	assert.Equal(t, "", title, "they should be equal")
+	_, err = fetchPageTitle(ctx, server.URL+"/missing")
+	assert.Error(t, err)
+
+	_, err = parseWebURL("example.com/article")
+	assert.Error(t, err)
+}
+
+func TestAddURL(t *testing.T) {
+	page := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<title>How to grow tomatoes</title>"))
+	}))
+	defer page.Close()
+	server := todoisttest.NewServer(t, `{
+		"user": {"id": "1", "inbox_project_id": "1"},
+		"projects": [{"id": "1", "name": "Inbox", "inbox_project": true}]
+	}`)
+	run := runTodoist(t, server)
+
+	_, err := run("add", "--url", page.URL+"/tomatoes")
+	assert.NoError(t, err)
+	_, err = run("add", "--url", page.URL+"/gone", "--label-ids", "", "Read it")
+	assert.NoError(t, err)
+
+	client := server.NewClient()
+	assert.NoError(t, client.Sync(context.Background()))
+	assert.Equal(t, 2, len(client.Store.Items), "they should be equal")
+	item := client.Store.Items[0]
+	assert.Equal(t, "How to grow tomatoes", item.Content, "they should be equal")
+	assert.Equal(t, page.URL+"/tomatoes", item.Description, "they should be equal")
+	assert.Equal(t, []string{"read-later"}, item.LabelNames, "they should be equal")
+	assert.Equal(t, "Read it", client.Store.Items[1].Content, "they should be equal")
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sachaos/todoist/lib"
+	"github.com/urfave/cli"
+)
+
+// workspacePath returns the path of the workspace in use of the account of
+// the cache at cachePath, kept next to it as ids differ between accounts.
+func workspacePath(cachePath string) string {
+	return cachePath + ".workspace.json"
+}
+
+// WorkspaceState is the workspace currently in use. An ID of "" with the name
+// personal is for the projects outside of any workspace, and an empty name
+// for all projects.
+type WorkspaceState struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func currentWorkspace() (WorkspaceState, error) {
+	var state WorkspaceState
+	err := readJSONFile(workspacePath(default_cache_path), &state)
+	return state, err
+}
+
+// workspaceScope returns the id of the workspace called name, or without a
+// name of the one in use, and whether there is one at all.
+func workspaceScope(store *todoist.Store, name string) (string, bool, error) {
+	if name != "" {
+		id, err := resolveWorkspace(store, name)
+		return id, err == nil, err
+	}
+	state, err := currentWorkspace()
+	if err != nil || state.Name == "" {
+		return "", false, err
+	}
+	if state.ID != "" && store.FindWorkspace(state.ID) == nil {
+		return "", false, &Error{Code: "workspace_not_found", Message: fmt.Sprintf("workspace %q in use not found", state.Name), Hint: "switch with `todoist workspace use` or stop with `todoist workspace clear`"}
+	}
+	return state.ID, true, nil
+}
+
+// resolveWorkspace returns the id of the workspace called name, or "" for
+// "personal", meaning the projects outside of any workspace.
+func resolveWorkspace(store *todoist.Store, name string) (string, error) {
+	if id := store.Workspaces.GetIDByName(name); id != "" {
+		return id, nil
+	}
+	if strings.EqualFold(name, "personal") {
+		return "", nil
+	}
+	return "", &Error{Code: "workspace_not_found", Message: fmt.Sprintf("workspace %q not found", name), Hint: "use personal or a name from `todoist workspaces`"}
+}
+
+// workspaceGroups returns the ids of the workspaces projects are listed
+// under: "" for the personal projects first, then the workspaces in the
+// order of the cache. With only personal projects, it is just "".
+func workspaceGroups(store *todoist.Store) []string {
+	groups := []string{""}
+	for _, workspace := range store.Workspaces {
+		if !workspace.IsDeleted {
+			groups = append(groups, workspace.ID)
+		}
+	}
+	return groups
+}
+
+// projectIDInWorkspace returns the id of the project called name in the
+// workspace with id, or "".
+func projectIDInWorkspace(store *todoist.Store, name string, id string) string {
+	for _, project := range store.Projects {
+		if project.Name == name && project.WorkspaceID == id {
+			return project.ID
+		}
+	}
+	return ""
+}
+
+// filterWorkspace returns the items of the projects of the workspace with id.
+func filterWorkspace(store *todoist.Store, items []listedItem, id string) []listedItem {
+	filtered := []listedItem{}
+	for _, listed := range items {
+		if project := store.FindProject(listed.item.ProjectID); project != nil && project.WorkspaceID == id {
+			filtered = append(filtered, listed)
+		}
+	}
+	return filtered
+}
+
+func ShowWorkspace(c *cli.Context) error {
+	state, err := currentWorkspace()
+	if err != nil {
+		return err
+	}
+	defer writer.Flush()
+	writer.WriteHeader([]string{"ID", "Name"})
+	if state.Name == "" {
+		fmt.Fprintln(os.Stderr, "No workspace is in use.")
+		return nil
+	}
+	writer.Write([]string{state.ID, state.Name})
+	return nil
+}
+
+func ListWorkspaces(c *cli.Context) error {
+	client := GetClient(c)
+	store := client.Store
+
+	if len(store.Workspaces) == 0 {
+		fmt.Fprintln(os.Stderr, "You are not a member of any workspace.")
+		return nil
+	}
+	current, _ := currentWorkspace()
+
+	projects := map[string]int{}
+	for _, project := range store.Projects {
+		if !project.IsDeleted {
+			projects[project.WorkspaceID]++
+		}
+	}
+	members := map[string]int{}
+	for _, user := range store.WorkspaceUsers {
+		if !user.IsDeleted {
+			members[user.WorkspaceID]++
+		}
+	}
+
+	defer writer.Flush()
+
+	writer.WriteHeader([]string{"ID", "Name", "Projects", "Members", "Current"})
+
+	for _, workspace := range store.Workspaces {
+		if workspace.IsDeleted {
+			continue
+		}
+		mark := ""
+		if current.Name != "" && workspace.ID == current.ID {
+			mark = "*"
+		}
+		writer.Write([]string{
+			IdFormat(workspace),
+			workspace.Name,
+			strconv.Itoa(projects[workspace.ID]),
+			strconv.Itoa(members[workspace.ID]),
+			mark,
+		})
+	}
+	return nil
+}
+
+func UseWorkspace(c *cli.Context) error {
+	client := GetClient(c)
+
+	if !c.Args().Present() {
+		return ArgumentRequired
+	}
+	id, err := resolveWorkspace(client.Store, c.Args().First())
+	if err != nil {
+		return err
+	}
+	state := WorkspaceState{ID: id, Name: "personal"}
+	if workspace := client.Store.FindWorkspace(id); workspace != nil {
+		state.Name = workspace.Name
+	}
+	return writeJSONFile(workspacePath(default_cache_path), state)
+}
+
+func ClearWorkspace(c *cli.Context) error {
+	return writeJSONFile(workspacePath(default_cache_path), WorkspaceState{})
+}
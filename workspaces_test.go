@@ -0,0 +1,106 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkspaces(t *testing.T) {
+	store := testStore(t, `{
+		"user": {"id": "1"},
+		"projects": [
+			{"id": "1", "name": "Inbox"},
+			{"id": "2", "name": "Roadmap", "workspace_id": "51"},
+			{"id": "3", "name": "Hiring", "workspace_id": "51", "is_invite_only": true}
+		],
+		"items": [
+			{"id": "10", "project_id": "1", "content": "personal"},
+			{"id": "11", "project_id": "2", "content": "team"},
+			{"id": "12", "project_id": "3", "content": "invite only"}
+		],
+		"workspaces": [{"id": "51", "name": "Acme"}, {"id": "52", "name": "Old", "is_deleted": true}],
+		"workspace_users": [
+			{"user_id": "1", "workspace_id": "51", "full_name": "Me"},
+			{"user_id": "4", "workspace_id": "51", "full_name": "Jo Park", "email": "jo@example.com"}
+		]
+	}`)
+
+	assert.Equal(t, []string{"", "51"}, workspaceGroups(store), "they should be equal")
+
+	id, err := resolveWorkspace(store, "Acme")
+	assert.NoError(t, err)
+	assert.Equal(t, "51", id, "they should be equal")
+	id, err = resolveWorkspace(store, "personal")
+	assert.NoError(t, err)
+	assert.Equal(t, "", id, "they should be equal")
+	_, err = resolveWorkspace(store, "Old")
+	assert.Error(t, err)
+
+	items := []listedItem{}
+	for i := range store.Items {
+		items = append(items, listedItem{item: &store.Items[i]})
+	}
+	ids := []string{}
+	for _, listed := range filterWorkspace(store, items, "51") {
+		ids = append(ids, listed.item.ID)
+	}
+	assert.Equal(t, []string{"11", "12"}, ids, "they should be equal")
+
+	// Members of the workspace can be assigned its tasks, unless the
+	// project is invite only.
+	uid, err := resolveAssignee(store, "jo")
+	assert.NoError(t, err)
+	assert.Equal(t, "4", uid, "they should be equal")
+	assert.True(t, store.IsShared("2", uid))
+	assert.False(t, store.IsShared("3", uid))
+	assert.False(t, store.IsShared("1", uid))
+}
+
+func TestWorkspaceScope(t *testing.T) {
+	dir, err := ioutil.TempDir("", "todoist")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	saved := default_cache_path
+	default_cache_path = filepath.Join(dir, "cache.json")
+	defer func() { default_cache_path = saved }()
+
+	store := testStore(t, `{
+		"projects": [
+			{"id": "1", "name": "Roadmap"},
+			{"id": "2", "name": "Roadmap", "workspace_id": "51"}
+		],
+		"workspaces": [{"id": "51", "name": "Acme"}]
+	}`)
+
+	_, scoped, err := workspaceScope(store, "")
+	assert.NoError(t, err)
+	assert.False(t, scoped)
+
+	assert.NoError(t, writeJSONFile(workspacePath(default_cache_path), WorkspaceState{ID: "51", Name: "Acme"}))
+	id, scoped, err := workspaceScope(store, "")
+	assert.NoError(t, err)
+	assert.True(t, scoped)
+	assert.Equal(t, "51", id, "they should be equal")
+	assert.Equal(t, "2", projectIDInWorkspace(store, "Roadmap", id), "they should be equal")
+
+	// A flag wins over the workspace in use.
+	id, scoped, err = workspaceScope(store, "personal")
+	assert.NoError(t, err)
+	assert.True(t, scoped)
+	assert.Equal(t, "1", projectIDInWorkspace(store, "Roadmap", id), "they should be equal")
+
+	assert.NoError(t, writeJSONFile(workspacePath(default_cache_path), WorkspaceState{ID: "52", Name: "Gone"}))
+	_, _, err = workspaceScope(store, "")
+	assert.Error(t, err)
+
+	// Another account has a cache, and a workspace in use, of its own.
+	default_cache_path = filepath.Join(dir, "other.json")
+	_, scoped, err = workspaceScope(store, "")
+	assert.NoError(t, err)
+	assert.False(t, scoped)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"io"
+
+	"gopkg.in/yaml.v2"
+)
+
+func init() {
+	RegisterWriter("yaml", func(w io.Writer, opts WriterOptions) Writer { return NewYAMLWriter(w) })
+}
+
+// YAMLWriter writes the same structures as JSONWriter in YAML, for tools like
+// yq and Ansible: a sequence of mappings keyed by the header, or of sequences
+// without one.
+type YAMLWriter struct {
+	w       io.Writer
+	header  []string
+	records [][]string
+}
+
+func NewYAMLWriter(w io.Writer) *YAMLWriter {
+	return &YAMLWriter{w: w}
+}
+
+func (w *YAMLWriter) WriteHeader(record []string) error {
+	w.header = record
+	return nil
+}
+
+func (w *YAMLWriter) Write(record []string) error {
+	fields := make([]string, len(record))
+	for i, field := range record {
+		fields[i] = ansiRegex.ReplaceAllString(field, "")
+	}
+	w.records = append(w.records, fields)
+	return nil
+}
+
+func (w *YAMLWriter) Flush() {
+	documents := make([]interface{}, len(w.records))
+	for i, record := range w.records {
+		documents[i] = w.yamlRecord(record)
+	}
+	buf, _ := yaml.Marshal(documents)
+	w.w.Write(buf)
+	w.records = nil
+}
+
+// yamlRecord keeps the fields in header order, which a map would not.
+func (w *YAMLWriter) yamlRecord(record []string) interface{} {
+	if w.header == nil {
+		return record
+	}
+	fields := yaml.MapSlice{}
+	for i, key := range w.header {
+		if i >= len(record) {
+			break
+		}
+		fields = append(fields, yaml.MapItem{Key: key, Value: record[i]})
+	}
+	return fields
+}
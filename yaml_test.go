@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestYAMLWriter(t *testing.T) {
+	var b bytes.Buffer
+	w := NewYAMLWriter(&b)
+	w.WriteHeader([]string{"ID", "Project", "Content"})
+	w.Write([]string{"\x1b[34m1\x1b[0m", "#Work", "buy milk"})
+	w.Write([]string{"2", "#Home", "say: \"hi\""})
+	w.Flush()
+
+	expected := `- ID: "1"
+  Project: '#Work'
+  Content: buy milk
+- ID: "2"
+  Project: '#Home'
+  Content: 'say: "hi"'
+`
+	assert.Equal(t, expected, b.String(), "they should be equal")
+
+	b.Reset()
+	w = NewYAMLWriter(&b)
+	w.Write([]string{"ID", "1"})
+	w.Flush()
+	assert.Equal(t, "- - ID\n  - \"1\"\n", b.String(), "they should be equal")
+
+	b.Reset()
+	w.Flush()
+	assert.Equal(t, "[]\n", b.String(), "they should be equal")
+}